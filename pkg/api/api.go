@@ -51,6 +51,21 @@ type CallContext interface {
 	GetFuncName() string
 	// Get the package name of the original function
 	GetPackageName() string
+	// Get the import path of the package of the original function
+	GetPackagePath() string
+	// Get the receiver type of the original function, e.g. "*Client", it's
+	// empty if the original function is not a method
+	GetReceiverType() string
+	// Get the source file where the original function is declared
+	GetSourceFile() string
+	// Get the line where the original function is declared
+	GetSourceLine() int
+	// Get the parameter names of the original function, the receiver is the
+	// first one if present, in the same order as GetParam
+	GetParamNames() []string
+	// Get the return value names of the original function, in the same order
+	// as GetReturnVal
+	GetResultNames() []string
 	// Number of original function parameters
 	GetParamCount() int
 	// Number of original function return values
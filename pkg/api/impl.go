@@ -20,6 +20,15 @@ type CallContextImpl struct {
 	ReturnVals []interface{}
 	SkipCall   bool
 	Data       interface{}
+
+	FuncName     string
+	PackageName  string
+	PackagePath  string
+	ReceiverType string
+	SourceFile   string
+	SourceLine   int
+	ParamNames   []string
+	ResultNames  []string
 }
 
 func (c *CallContextImpl) SetSkipCall(skip bool)    { c.SkipCall = skip }
@@ -64,11 +73,35 @@ func (c *CallContextImpl) SetReturnVal(idx int, val interface{}) {
 }
 
 func (c *CallContextImpl) GetFuncName() string {
-	return ""
+	return c.FuncName
 }
 
 func (c *CallContextImpl) GetPackageName() string {
-	return ""
+	return c.PackageName
+}
+
+func (c *CallContextImpl) GetPackagePath() string {
+	return c.PackagePath
+}
+
+func (c *CallContextImpl) GetReceiverType() string {
+	return c.ReceiverType
+}
+
+func (c *CallContextImpl) GetSourceFile() string {
+	return c.SourceFile
+}
+
+func (c *CallContextImpl) GetSourceLine() int {
+	return c.SourceLine
+}
+
+func (c *CallContextImpl) GetParamNames() []string {
+	return c.ParamNames
+}
+
+func (c *CallContextImpl) GetResultNames() []string {
+	return c.ResultNames
 }
 
 func (c *CallContextImpl) GetParamCount() int {
@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+)
+
+// The admin endpoint is disabled by default, it's enabled by setting the
+// environment variable to either a TCP address such as "127.0.0.1:9465" or a
+// Unix domain socket path prefixed with "unix:", e.g. "unix:/tmp/otel.sock".
+// There is no authentication at all, so it should never be exposed to the
+// network other than the loopback interface
+const EnvAdminEndpoint = "OTEL_EXPERIMENTAL_ADMIN_ENDPOINT"
+
+const instrumentationsPath = "/instrumentations"
+
+// Server serves the endpoint to list the instrumentations registered in the
+// process and to turn them on and off at runtime
+//
+//	GET  /instrumentations                  list all instrumentations
+//	GET  /instrumentations/<scope>          show the instrumentation
+//	POST /instrumentations/<scope>/enable   enable the instrumentation
+//	POST /instrumentations/<scope>/disable  disable the instrumentation
+//	POST /instrumentations/<scope>/reset    drop the value set at runtime
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+func listen(endpoint string) (net.Listener, error) {
+	if strings.HasPrefix(endpoint, "unix:") {
+		path := strings.TrimPrefix(endpoint, "unix:")
+		// Remove the socket file left by the previous process, if any
+		if info, err := os.Stat(path); err == nil &&
+			info.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(path)
+		}
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", endpoint)
+}
+
+// Start starts serving the admin endpoint in the background
+func Start(endpoint string) (*Server, error) {
+	listener, err := listen(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(instrumentationsPath, handleInstrumentations)
+	mux.HandleFunc(instrumentationsPath+"/", handleInstrumentations)
+	s := &Server{
+		listener: listener,
+		server:   &http.Server{Handler: mux},
+	}
+	go func() {
+		err := s.server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Failed to serve admin endpoint %s: %v", endpoint, err)
+		}
+	}()
+	log.Printf("Serving admin endpoint at %s", endpoint)
+	return s, nil
+}
+
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+func writeJson(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJson(w, code, map[string]string{"error": msg})
+}
+
+func handleInstrumentations(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, instrumentationsPath)
+	path = strings.Trim(path, "/")
+	if path == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJson(w, http.StatusOK, instrumenter.GetInstrumentEnablerStatus())
+		return
+	}
+	// Scope names never contain slashes, so the action is the last segment
+	scope, action := path, ""
+	if idx := strings.LastIndex(path, "/"); idx >= 0 {
+		scope, action = path[:idx], path[idx+1:]
+	}
+	enabler := instrumenter.GetInstrumentEnabler(scope)
+	if enabler == nil {
+		writeError(w, http.StatusNotFound,
+			"instrumentation "+scope+" is not registered")
+		return
+	}
+	if action == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJson(w, http.StatusOK, enabler.Status())
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var err error
+	switch action {
+	case "enable":
+		err = instrumenter.SetInstrumentEnabled(scope, true)
+	case "disable":
+		err = instrumenter.SetInstrumentEnabled(scope, false)
+	case "reset":
+		err = instrumenter.ResetInstrumentEnabled(scope)
+	default:
+		writeError(w, http.StatusNotFound, "unknown action "+action)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Instrumentation %s is updated by admin endpoint: %s", scope, action)
+	writeJson(w, http.StatusOK, enabler.Status())
+}
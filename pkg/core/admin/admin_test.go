@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+)
+
+func request(t *testing.T, client *http.Client, method, url string, v any) int {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if v != nil {
+		if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestAdminEndpoint(t *testing.T) {
+	enabler := instrumenter.RegisterInstrumentEnabler(
+		utils.NET_HTTP_SERVER_SCOPE_NAME, "nethttp", true)
+	s, err := Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+	base := "http://" + s.Addr().String() + instrumentationsPath
+	client := http.DefaultClient
+
+	var list []instrumenter.EnablerStatus
+	if request(t, client, http.MethodGet, base, &list) != http.StatusOK ||
+		len(list) != 1 || list[0].Name != "nethttp" || !list[0].Enabled {
+		t.Fatalf("unexpected instrumentations %+v", list)
+	}
+	var status instrumenter.EnablerStatus
+	url := base + "/" + utils.NET_HTTP_SERVER_SCOPE_NAME
+	if request(t, client, http.MethodPost, url+"/disable", &status) != http.StatusOK ||
+		status.Enabled || status.Source != instrumenter.EnablerSourceRuntime {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if enabler.Enable() {
+		t.Fatal("nethttp is expected to be disabled")
+	}
+	if request(t, client, http.MethodPost, url+"/reset", &status) != http.StatusOK ||
+		!status.Enabled || !enabler.Enable() {
+		t.Fatalf("unexpected status after reset %+v", status)
+	}
+	if request(t, client, http.MethodGet, url+"/disable", nil) != http.StatusMethodNotAllowed {
+		t.Fatal("expect method not allowed")
+	}
+	if request(t, client, http.MethodGet, base+"/unknown", nil) != http.StatusNotFound {
+		t.Fatal("expect not found for unregistered instrumentation")
+	}
+}
+
+func TestAdminEndpointUnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "otel.sock")
+	s, err := Start("unix:" + sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+	var list []instrumenter.EnablerStatus
+	if request(t, client, http.MethodGet, "http://otel"+instrumentationsPath,
+		&list) != http.StatusOK {
+		t.Fatal("failed to list instrumentations over unix socket")
+	}
+}
@@ -0,0 +1,436 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the declarative configuration of the agent. It follows the
+// OpenTelemetry declarative configuration schema where possible, i.e. the
+// sections and the field names are the same, but only a subset of them is
+// supported. Since YAML is a superset of JSON, the file can be written in
+// either format.
+type Config struct {
+	FileFormat      string           `yaml:"file_format"`
+	Disabled        bool             `yaml:"disabled"`
+	AttributeLimits *AttributeLimits `yaml:"attribute_limits"`
+	TracerProvider  *TracerProvider  `yaml:"tracer_provider"`
+	MeterProvider   *MeterProvider   `yaml:"meter_provider"`
+	Instrumentation *Instrumentation `yaml:"instrumentation/development"`
+}
+
+type AttributeLimits struct {
+	AttributeValueLengthLimit *int `yaml:"attribute_value_length_limit"`
+	AttributeCountLimit       *int `yaml:"attribute_count_limit"`
+}
+
+type TracerProvider struct {
+	Processors []*SpanProcessor `yaml:"processors"`
+	Limits     *SpanLimits      `yaml:"limits"`
+	Sampler    *Sampler         `yaml:"sampler"`
+}
+
+type SpanLimits struct {
+	AttributeValueLengthLimit *int `yaml:"attribute_value_length_limit"`
+	AttributeCountLimit       *int `yaml:"attribute_count_limit"`
+	EventCountLimit           *int `yaml:"event_count_limit"`
+	LinkCountLimit            *int `yaml:"link_count_limit"`
+	EventAttributeCountLimit  *int `yaml:"event_attribute_count_limit"`
+	LinkAttributeCountLimit   *int `yaml:"link_attribute_count_limit"`
+}
+
+type SpanProcessor struct {
+	Batch  *BatchSpanProcessor  `yaml:"batch"`
+	Simple *SimpleSpanProcessor `yaml:"simple"`
+}
+
+// BatchSpanProcessor configures the batch span processor, all durations are
+// in milliseconds
+type BatchSpanProcessor struct {
+	ScheduleDelay      *int          `yaml:"schedule_delay"`
+	ExportTimeout      *int          `yaml:"export_timeout"`
+	MaxQueueSize       *int          `yaml:"max_queue_size"`
+	MaxExportBatchSize *int          `yaml:"max_export_batch_size"`
+	Exporter           *SpanExporter `yaml:"exporter"`
+}
+
+type SimpleSpanProcessor struct {
+	Exporter *SpanExporter `yaml:"exporter"`
+}
+
+type SpanExporter struct {
+	OTLP    *OTLPExporter    `yaml:"otlp"`
+	Console *ConsoleExporter `yaml:"console"`
+	Zipkin  *ZipkinExporter  `yaml:"zipkin"`
+}
+
+// OTLPExporter configures the OTLP exporter of any signal, the timeout is in
+// milliseconds, and the temporality preference applies to metrics only
+type OTLPExporter struct {
+	Protocol              string       `yaml:"protocol"`
+	Endpoint              string       `yaml:"endpoint"`
+	Headers               []*NameValue `yaml:"headers"`
+	Insecure              bool         `yaml:"insecure"`
+	Timeout               *int         `yaml:"timeout"`
+	TemporalityPreference string       `yaml:"temporality_preference"`
+}
+
+type NameValue struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type ConsoleExporter struct{}
+
+type ZipkinExporter struct {
+	Endpoint string `yaml:"endpoint"`
+}
+
+type Sampler struct {
+	AlwaysOn          *AlwaysOnSampler          `yaml:"always_on"`
+	AlwaysOff         *AlwaysOffSampler         `yaml:"always_off"`
+	TraceIDRatioBased *TraceIDRatioBasedSampler `yaml:"trace_id_ratio_based"`
+	ParentBased       *ParentBasedSampler       `yaml:"parent_based"`
+	RuleBased         *RuleBasedSampler         `yaml:"rule_based"`
+}
+
+type AlwaysOnSampler struct{}
+
+type AlwaysOffSampler struct{}
+
+type TraceIDRatioBasedSampler struct {
+	Ratio *float64 `yaml:"ratio"`
+}
+
+type ParentBasedSampler struct {
+	Root                   *Sampler `yaml:"root"`
+	RemoteParentSampled    *Sampler `yaml:"remote_parent_sampled"`
+	RemoteParentNotSampled *Sampler `yaml:"remote_parent_not_sampled"`
+	LocalParentSampled     *Sampler `yaml:"local_parent_sampled"`
+	LocalParentNotSampled  *Sampler `yaml:"local_parent_not_sampled"`
+}
+
+// RuleBasedSampler is not part of the declarative configuration schema, it
+// samples the spans by the first matching rule, and delegates to the fallback
+// sampler if none of the rules matches
+type RuleBasedSampler struct {
+	Rules    []*SamplingRule `yaml:"rules"`
+	Fallback *Sampler        `yaml:"fallback"`
+}
+
+// SamplingRule matches the span if all of the specified conditions hold, the
+// span name and the attribute values are matched by regular expressions, which
+// must match the whole value. Only the attributes known when the span starts,
+// such as http.route, rpc.method and db.system, can be matched
+type SamplingRule struct {
+	Action     string            `yaml:"action"`
+	SpanKind   string            `yaml:"span_kind"`
+	Name       string            `yaml:"name"`
+	Attributes map[string]string `yaml:"attributes"`
+}
+
+const (
+	SamplingActionDrop            = "drop"
+	SamplingActionRecordAndSample = "record_and_sample"
+)
+
+var spanKinds = map[string]bool{
+	"internal": true, "server": true, "client": true,
+	"producer": true, "consumer": true,
+}
+
+type MeterProvider struct {
+	Readers []*MetricReader `yaml:"readers"`
+}
+
+type MetricReader struct {
+	Periodic *PeriodicMetricReader `yaml:"periodic"`
+	Pull     *PullMetricReader     `yaml:"pull"`
+}
+
+// PeriodicMetricReader configures the periodic metric reader, all durations
+// are in milliseconds
+type PeriodicMetricReader struct {
+	Interval *int            `yaml:"interval"`
+	Timeout  *int            `yaml:"timeout"`
+	Exporter *MetricExporter `yaml:"exporter"`
+}
+
+type MetricExporter struct {
+	OTLP    *OTLPExporter    `yaml:"otlp"`
+	Console *ConsoleExporter `yaml:"console"`
+}
+
+type PullMetricReader struct {
+	Exporter *PullMetricExporter `yaml:"exporter"`
+}
+
+type PullMetricExporter struct {
+	Prometheus *PrometheusExporter `yaml:"prometheus"`
+}
+
+type PrometheusExporter struct {
+	Host string `yaml:"host"`
+	Port *int   `yaml:"port"`
+}
+
+// Instrumentation configures the instrumentations of the Go language, they are
+// keyed by the instrumentation name, which is the lower-cased <NAME> part of the
+// OTEL_INSTRUMENTATION_<NAME>_ENABLED environment variable, e.g. "nethttp"
+type Instrumentation struct {
+	Go map[string]*InstrumentationConfig `yaml:"go"`
+}
+
+type InstrumentationConfig struct {
+	Enabled *bool `yaml:"enabled"`
+	// CostConfig is the pricing file of the ollama instrumentation, same as
+	// OLLAMA_COST_CONFIG
+	CostConfig string `yaml:"cost_config"`
+	// MaxQueueLength is the maximum number of pending requests kept by the
+	// redigo instrumentation, same as MAX_REDIGO_QUEUE_LENGTH
+	MaxQueueLength *int `yaml:"max_queue_length"`
+}
+
+// Components without any property such as "console" are usually written as
+// "console:" rather than "console: {}", which is decoded as null and leaves the
+// pointer nil, so their presence is checked by the key
+func hasKey(node *yaml.Node, key string) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *SpanExporter) UnmarshalYAML(node *yaml.Node) error {
+	type plain SpanExporter
+	if err := node.Decode((*plain)(e)); err != nil {
+		return err
+	}
+	if e.Console == nil && hasKey(node, "console") {
+		e.Console = &ConsoleExporter{}
+	}
+	return nil
+}
+
+func (e *MetricExporter) UnmarshalYAML(node *yaml.Node) error {
+	type plain MetricExporter
+	if err := node.Decode((*plain)(e)); err != nil {
+		return err
+	}
+	if e.Console == nil && hasKey(node, "console") {
+		e.Console = &ConsoleExporter{}
+	}
+	return nil
+}
+
+func (e *PullMetricExporter) UnmarshalYAML(node *yaml.Node) error {
+	type plain PullMetricExporter
+	if err := node.Decode((*plain)(e)); err != nil {
+		return err
+	}
+	if e.Prometheus == nil && hasKey(node, "prometheus") {
+		e.Prometheus = &PrometheusExporter{}
+	}
+	return nil
+}
+
+func (s *Sampler) UnmarshalYAML(node *yaml.Node) error {
+	type plain Sampler
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	if s.AlwaysOn == nil && hasKey(node, "always_on") {
+		s.AlwaysOn = &AlwaysOnSampler{}
+	}
+	if s.AlwaysOff == nil && hasKey(node, "always_off") {
+		s.AlwaysOff = &AlwaysOffSampler{}
+	}
+	if s.ParentBased == nil && hasKey(node, "parent_based") {
+		s.ParentBased = &ParentBasedSampler{}
+	}
+	return nil
+}
+
+// ${VAR} or ${VAR:-default}, as defined by the declarative configuration
+var envSubstRegexp = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)(:-([^}]*))?\}`)
+
+func substituteEnv(content []byte) []byte {
+	return envSubstRegexp.ReplaceAllFunc(content, func(m []byte) []byte {
+		sub := envSubstRegexp.FindSubmatch(m)
+		if val, ok := os.LookupEnv(string(sub[1])); ok && val != "" {
+			return []byte(val)
+		}
+		return sub[3]
+	})
+}
+
+// Parse parses the configuration in YAML or JSON format, environment variables
+// referenced by ${VAR} are substituted before parsing
+func Parse(content []byte) (*Config, error) {
+	conf := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(substituteEnv(content)))
+	decoder.KnownFields(true)
+	err := decoder.Decode(conf)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return conf, conf.validate()
+}
+
+func (c *Config) validate() error {
+	if c.TracerProvider != nil {
+		err := c.TracerProvider.Sampler.validate()
+		if err != nil {
+			return fmt.Errorf("tracer_provider.sampler: %w", err)
+		}
+		for i, p := range c.TracerProvider.Processors {
+			if p == nil || (p.Batch == nil) == (p.Simple == nil) {
+				return fmt.Errorf("tracer_provider.processors[%d]: "+
+					"exactly one of batch and simple is expected", i)
+			}
+		}
+	}
+	if c.MeterProvider != nil {
+		for i, r := range c.MeterProvider.Readers {
+			if r == nil || (r.Periodic == nil) == (r.Pull == nil) {
+				return fmt.Errorf("meter_provider.readers[%d]: "+
+					"exactly one of periodic and pull is expected", i)
+			}
+		}
+	}
+	if c.Instrumentation != nil {
+		for name, ic := range c.Instrumentation.Go {
+			if ic != nil && ic.MaxQueueLength != nil && *ic.MaxQueueLength <= 0 {
+				return fmt.Errorf("instrumentation/development.go.%s: "+
+					"max_queue_length must be positive", name)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Sampler) validate() error {
+	if s == nil {
+		return nil
+	}
+	if pb := s.ParentBased; pb != nil {
+		for _, sampler := range []*Sampler{pb.Root, pb.RemoteParentSampled,
+			pb.RemoteParentNotSampled, pb.LocalParentSampled,
+			pb.LocalParentNotSampled} {
+			if err := sampler.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return s.RuleBased.validate()
+}
+
+func (s *RuleBasedSampler) validate() error {
+	if s == nil {
+		return nil
+	}
+	for i, rule := range s.Rules {
+		if rule == nil {
+			return fmt.Errorf("rules[%d]: empty rule", i)
+		}
+		if rule.Action != SamplingActionDrop &&
+			rule.Action != SamplingActionRecordAndSample {
+			return fmt.Errorf("rules[%d]: unknown action %q", i, rule.Action)
+		}
+		if rule.SpanKind != "" && !spanKinds[rule.SpanKind] {
+			return fmt.Errorf("rules[%d]: unknown span kind %q", i,
+				rule.SpanKind)
+		}
+		if rule.Name == "" && len(rule.Attributes) == 0 {
+			return fmt.Errorf("rules[%d]: either name or attributes "+
+				"is expected", i)
+		}
+		patterns := []string{rule.Name}
+		for _, pattern := range rule.Attributes {
+			patterns = append(patterns, pattern)
+		}
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("rules[%d]: %w", i, err)
+			}
+		}
+	}
+	return s.Fallback.validate()
+}
+
+// ParseRuleBasedSampler parses the sampling rules file in YAML or JSON format,
+// which has the same structure as the rule_based sampler of the config file
+func ParseRuleBasedSampler(content []byte) (*RuleBasedSampler, error) {
+	s := &RuleBasedSampler{}
+	decoder := yaml.NewDecoder(bytes.NewReader(substituteEnv(content)))
+	decoder.KnownFields(true)
+	err := decoder.Decode(s)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return s, s.validate()
+}
+
+// LookupInstrumentation returns the configuration of the instrumentation, nil
+// is returned if it's not configured at all
+func LookupInstrumentation(name string) *InstrumentationConfig {
+	conf := Get()
+	if conf == nil || conf.Instrumentation == nil {
+		return nil
+	}
+	return conf.Instrumentation.Go[name]
+}
+
+// LookupInstrumentationEnabled returns whether the instrumentation is enabled by
+// the current configuration, ok is false if it's not configured at all
+func LookupInstrumentationEnabled(name string) (enabled bool, ok bool) {
+	conf := Get()
+	if conf == nil {
+		return false, false
+	}
+	if conf.Disabled {
+		return false, true
+	}
+	if conf.Instrumentation == nil {
+		return false, false
+	}
+	ic, ok := conf.Instrumentation.Go[name]
+	if !ok || ic == nil || ic.Enabled == nil {
+		return false, false
+	}
+	return *ic.Enabled, true
+}
+
+// IsInstrumentationEnabled reports whether the instrumentation is enabled by
+// the current configuration, def is returned if it's not configured at all
+func IsInstrumentationEnabled(name string, def bool) bool {
+	if enabled, ok := LookupInstrumentationEnabled(name); ok {
+		return enabled
+	}
+	return def
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testConfig = `
+file_format: "0.3"
+attribute_limits:
+  attribute_value_length_limit: ${TEST_VALUE_LENGTH_LIMIT:-1024}
+tracer_provider:
+  processors:
+    - batch:
+        schedule_delay: 1000
+        exporter:
+          otlp:
+            protocol: grpc
+            endpoint: ${TEST_OTLP_ENDPOINT}
+            headers:
+              - name: api-key
+                value: secret
+    - simple:
+        exporter:
+          console:
+  sampler:
+    parent_based:
+      root:
+        trace_id_ratio_based:
+          ratio: 0.25
+meter_provider:
+  readers:
+    - pull:
+        exporter:
+          prometheus:
+instrumentation/development:
+  go:
+    gin:
+      enabled: false
+    nethttp:
+    ollama:
+      cost_config: /etc/otel/ollama_cost.json
+    redigo:
+      max_queue_length: 500
+`
+
+func TestParse(t *testing.T) {
+	t.Setenv("TEST_OTLP_ENDPOINT", "http://collector:4317")
+	conf, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *conf.AttributeLimits.AttributeValueLengthLimit != 1024 {
+		t.Errorf("unexpected attribute value length limit %d",
+			*conf.AttributeLimits.AttributeValueLengthLimit)
+	}
+	processors := conf.TracerProvider.Processors
+	if len(processors) != 2 {
+		t.Fatalf("expect 2 processors, got %d", len(processors))
+	}
+	otlp := processors[0].Batch.Exporter.OTLP
+	if otlp.Endpoint != "http://collector:4317" || otlp.Protocol != "grpc" {
+		t.Errorf("unexpected otlp exporter %+v", otlp)
+	}
+	if len(otlp.Headers) != 1 || otlp.Headers[0].Value != "secret" {
+		t.Errorf("unexpected otlp headers %+v", otlp.Headers)
+	}
+	if processors[1].Simple.Exporter.Console == nil {
+		t.Errorf("console exporter is expected")
+	}
+	root := conf.TracerProvider.Sampler.ParentBased.Root
+	if *root.TraceIDRatioBased.Ratio != 0.25 {
+		t.Errorf("unexpected sampler ratio %v", *root.TraceIDRatioBased.Ratio)
+	}
+	if conf.MeterProvider.Readers[0].Pull.Exporter.Prometheus == nil {
+		t.Errorf("prometheus exporter is expected")
+	}
+}
+
+func TestParseJson(t *testing.T) {
+	conf, err := Parse([]byte(`{"disabled": true, "tracer_provider": ` +
+		`{"sampler": {"always_off": {}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !conf.Disabled || conf.TracerProvider.Sampler.AlwaysOff == nil {
+		t.Errorf("unexpected config %+v", conf)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, content := range []string{
+		"unknown_field: true",
+		"tracer_provider:\n  processors:\n    - {}",
+		"meter_provider:\n  readers:\n    - periodic: {}\n      pull: {}",
+		"instrumentation/development:\n  go:\n    redigo:\n" +
+			"      max_queue_length: 0",
+	} {
+		if _, err := Parse([]byte(content)); err == nil {
+			t.Errorf("expect error for %q", content)
+		}
+	}
+}
+
+func TestIsInstrumentationEnabled(t *testing.T) {
+	defer current.Store(nil)
+	if !IsInstrumentationEnabled("gin", true) {
+		t.Errorf("default value is expected without config file")
+	}
+	conf, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.Store(conf)
+	if IsInstrumentationEnabled("gin", true) {
+		t.Errorf("gin is expected to be disabled")
+	}
+	if !IsInstrumentationEnabled("nethttp", true) ||
+		IsInstrumentationEnabled("grpc", false) {
+		t.Errorf("default value is expected if not configured")
+	}
+	conf.Disabled = true
+	if IsInstrumentationEnabled("nethttp", true) {
+		t.Errorf("all instrumentations are expected to be disabled")
+	}
+}
+
+func TestLookupInstrumentation(t *testing.T) {
+	defer current.Store(nil)
+	if LookupInstrumentation("ollama") != nil {
+		t.Errorf("nil is expected without config file")
+	}
+	conf, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.Store(conf)
+	if ic := LookupInstrumentation("ollama"); ic == nil ||
+		ic.CostConfig != "/etc/otel/ollama_cost.json" {
+		t.Errorf("unexpected ollama config %+v", ic)
+	}
+	if ic := LookupInstrumentation("redigo"); ic == nil ||
+		ic.MaxQueueLength == nil || *ic.MaxQueueLength != 500 {
+		t.Errorf("unexpected redigo config %+v", ic)
+	}
+	if LookupInstrumentation("grpc") != nil {
+		t.Errorf("nil is expected if not configured")
+	}
+}
+
+func TestLoadAndReload(t *testing.T) {
+	defer current.Store(nil)
+	file := filepath.Join(t.TempDir(), "otel.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("instrumentation/development:\n  go:\n    gin:\n      enabled: true\n")
+	t.Setenv(EnvConfigFile, file)
+	t.Setenv(EnvConfigReloadInterval, "10")
+	reloaded := make(chan *Config, 1)
+	OnReload(func(conf *Config) {
+		select {
+		case reloaded <- conf:
+		default:
+		}
+	})
+	if _, err := Load(); err != nil {
+		t.Fatal(err)
+	}
+	<-reloaded
+	if !IsInstrumentationEnabled("gin", false) {
+		t.Fatalf("gin is expected to be enabled")
+	}
+	// The modification time may be the same as the previous one on file
+	// systems with coarse timestamps, change the size as well
+	write("instrumentation/development:\n  go:\n    gin:\n      enabled: false\n\n")
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("config file is not reloaded")
+	}
+	if IsInstrumentationEnabled("gin", true) {
+		t.Errorf("gin is expected to be disabled after reload")
+	}
+}
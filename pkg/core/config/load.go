@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	EnvConfigFile           = "OTEL_EXPERIMENTAL_CONFIG_FILE"
+	EnvConfigReloadInterval = "OTEL_EXPERIMENTAL_CONFIG_RELOAD_INTERVAL"
+	defaultReloadInterval   = 5 * time.Second
+)
+
+var (
+	current   atomic.Pointer[Config]
+	watchOnce sync.Once
+	mu        sync.Mutex
+	listeners []func(*Config)
+)
+
+// Get returns the current configuration, or nil if there is no config file
+func Get() *Config {
+	return current.Load()
+}
+
+// OnReload registers a listener that is called with the new configuration
+// whenever the config file is reloaded
+func OnReload(listener func(*Config)) {
+	mu.Lock()
+	defer mu.Unlock()
+	listeners = append(listeners, listener)
+}
+
+func update(conf *Config) {
+	current.Store(conf)
+	mu.Lock()
+	ls := append([]func(*Config){}, listeners...)
+	mu.Unlock()
+	for _, listener := range ls {
+		listener(conf)
+	}
+}
+
+func loadFile(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return conf, nil
+}
+
+func getReloadInterval() time.Duration {
+	val := os.Getenv(EnvConfigReloadInterval)
+	if val == "" {
+		return defaultReloadInterval
+	}
+	ms, err := strconv.Atoi(val)
+	if err != nil || ms <= 0 {
+		log.Printf("Invalid %s value: %s, fallback to %v",
+			EnvConfigReloadInterval, val, defaultReloadInterval)
+		return defaultReloadInterval
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stampOf(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// WatchFile polls the file for changes rather than relying on file system
+// notifications, which are not delivered reliably for mounted volumes such as
+// the Kubernetes ConfigMap. The reload function is called in the background
+// whenever the file is changed, at the interval of config file reloading
+func WatchFile(path string, reload func()) {
+	go watch(path, stampOf(path), reload)
+}
+
+func watch(path string, last fileStamp, reload func()) {
+	ticker := time.NewTicker(getReloadInterval())
+	defer ticker.Stop()
+	for range ticker.C {
+		stamp := stampOf(path)
+		if stamp == last {
+			continue
+		}
+		last = stamp
+		reload()
+	}
+}
+
+func reload(path string) {
+	conf, err := loadFile(path)
+	if err != nil {
+		log.Printf("Failed to reload config file, keep the previous one: %v",
+			err)
+		return
+	}
+	update(conf)
+	log.Printf("Reloaded config file %s", path)
+}
+
+// Load loads the config file specified by OTEL_EXPERIMENTAL_CONFIG_FILE and
+// watches it for changes afterwards, nil is returned if no config file is
+// specified. Only the instrumentation section takes effect on reload, others
+// are applied when the SDK is initialized and require a restart to change.
+func Load() (*Config, error) {
+	path := os.Getenv(EnvConfigFile)
+	if path == "" {
+		return nil, nil
+	}
+	stamp := stampOf(path)
+	conf, err := loadFile(path)
+	if err == nil {
+		update(conf)
+	}
+	// Keep watching even if the file is invalid, it may be fixed later
+	watchOnce.Do(func() {
+		go watch(path, stamp, func() { reload(path) })
+	})
+	return conf, err
+}
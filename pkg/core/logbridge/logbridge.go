@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logbridge
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/version"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -----------------------------------------------------------------------------
+// Log Bridge
+//
+// The log rules emit the records of the log libraries, e.g. zap and logrus, to
+// the logger provider configured by OTEL_LOGS_EXPORTER, so that the logs reach
+// the same backend as the traces and metrics. Each log library has its own
+// logger named by its instrumentation scope, and the records are correlated
+// with the active span. Nothing is emitted if the logger provider is absent.
+
+var (
+	enabled  atomic.Bool
+	lock     sync.RWMutex
+	provider log.LoggerProvider
+	loggers  map[string]log.Logger
+)
+
+// SetLoggerProvider sets the logger provider the records are emitted to, nil
+// stops bridging the records
+func SetLoggerProvider(lp log.LoggerProvider) {
+	lock.Lock()
+	defer lock.Unlock()
+	provider = lp
+	loggers = make(map[string]log.Logger)
+	enabled.Store(lp != nil)
+}
+
+// Enabled reports whether the records should be bridged, the log rules should
+// check it before converting the records
+func Enabled() bool {
+	return enabled.Load()
+}
+
+func getLogger(scope string) log.Logger {
+	lock.RLock()
+	logger, ok := loggers[scope]
+	lock.RUnlock()
+	if ok {
+		return logger
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if provider == nil {
+		return nil
+	}
+	if logger, ok = loggers[scope]; !ok {
+		logger = provider.Logger(scope, log.WithInstrumentationVersion(version.Tag))
+		loggers[scope] = logger
+	}
+	return logger
+}
+
+// Emit emits the record to the logger of the instrumentation scope, the record
+// is correlated with the span if it's not nil
+func Emit(scope string, span trace.Span, record log.Record) {
+	logger := getLogger(scope)
+	if logger == nil {
+		return
+	}
+	ctx := context.Background()
+	if span != nil {
+		ctx = trace.ContextWithSpan(ctx, span)
+	}
+	logger.Emit(ctx, record)
+}
+
+// KeyValue converts the structured field of the log libraries to the
+// attribute of the record
+func KeyValue(key string, value any) log.KeyValue {
+	return log.KeyValue{Key: key, Value: Value(value)}
+}
+
+// Value converts the value of the structured field, values of unknown types
+// are formatted as strings
+func Value(value any) log.Value {
+	switch v := value.(type) {
+	case nil:
+		return log.Value{}
+	case log.Value:
+		return v
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case int:
+		return log.IntValue(v)
+	case int8:
+		return log.Int64Value(int64(v))
+	case int16:
+		return log.Int64Value(int64(v))
+	case int32:
+		return log.Int64Value(int64(v))
+	case int64:
+		return log.Int64Value(v)
+	case uint8:
+		return log.Int64Value(int64(v))
+	case uint16:
+		return log.Int64Value(int64(v))
+	case uint32:
+		return log.Int64Value(int64(v))
+	case uint:
+		return uint64Value(uint64(v))
+	case uint64:
+		return uint64Value(v)
+	case float32:
+		return log.Float64Value(float64(v))
+	case float64:
+		return log.Float64Value(v)
+	case []byte:
+		return log.BytesValue(v)
+	case time.Duration:
+		return log.Int64Value(v.Nanoseconds())
+	case time.Time:
+		return log.StringValue(v.Format(time.RFC3339Nano))
+	case error:
+		return log.StringValue(v.Error())
+	case fmt.Stringer:
+		return log.StringValue(v.String())
+	case []any:
+		values := make([]log.Value, 0, len(v))
+		for _, elem := range v {
+			values = append(values, Value(elem))
+		}
+		return log.SliceValue(values...)
+	case map[string]any:
+		kvs := make([]log.KeyValue, 0, len(v))
+		for key, elem := range v {
+			kvs = append(kvs, KeyValue(key, elem))
+		}
+		return log.MapValue(kvs...)
+	}
+	// The pointers are dereferenced, e.g. *string fields
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return log.Value{}
+		}
+		return Value(rv.Elem().Interface())
+	}
+	return log.StringValue(fmt.Sprintf("%+v", value))
+}
+
+func uint64Value(v uint64) log.Value {
+	if v > math.MaxInt64 {
+		return log.StringValue(fmt.Sprintf("%d", v))
+	}
+	return log.Int64Value(int64(v))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logbridge
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEmit(t *testing.T) {
+	Emit("test", nil, log.Record{})
+	if Enabled() {
+		t.Fatal("bridge is expected to be disabled without logger provider")
+	}
+	recorder := logtest.NewRecorder()
+	SetLoggerProvider(recorder)
+	defer SetLoggerProvider(nil)
+	if !Enabled() {
+		t.Fatal("bridge is expected to be enabled")
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	var record log.Record
+	record.SetBody(log.StringValue("hello"))
+	record.SetSeverity(log.SeverityInfo)
+	Emit("test", trace.SpanFromContext(trace.ContextWithSpanContext(
+		context.Background(), sc)), record)
+	Emit("test", nil, record)
+
+	result := recorder.Result()
+	if len(result) != 1 || result[0].Name != "test" ||
+		len(result[0].Records) != 2 {
+		t.Fatalf("unexpected records %+v", result)
+	}
+	records := result[0].Records
+	if got := trace.SpanContextFromContext(records[0].Context()); !got.Equal(sc) {
+		t.Errorf("record is expected to be correlated with the span, got %v", got)
+	}
+	if trace.SpanContextFromContext(records[1].Context()).IsValid() {
+		t.Errorf("record is not expected to be correlated with any span")
+	}
+	if records[0].Body().AsString() != "hello" {
+		t.Errorf("unexpected body %v", records[0].Body())
+	}
+}
+
+func TestValue(t *testing.T) {
+	str := "pointer"
+	for _, c := range []struct {
+		value any
+		want  log.Value
+	}{
+		{nil, log.Value{}},
+		{"str", log.StringValue("str")},
+		{true, log.BoolValue(true)},
+		{int32(-1), log.Int64Value(-1)},
+		{uint64(math.MaxUint64), log.StringValue("18446744073709551615")},
+		{1.5, log.Float64Value(1.5)},
+		{[]byte("bytes"), log.BytesValue([]byte("bytes"))},
+		{time.Second, log.Int64Value(int64(time.Second))},
+		{errors.New("failed"), log.StringValue("failed")},
+		{&str, log.StringValue("pointer")},
+		{[]any{1, "a"}, log.SliceValue(log.IntValue(1), log.StringValue("a"))},
+		{map[string]any{"k": "v"}, log.MapValue(log.String("k", "v"))},
+		{struct{ A int }{1}, log.StringValue("{A:1}")},
+	} {
+		if got := Value(c.value); !got.Equal(c.want) {
+			t.Errorf("%#v: expect %v, got %v", c.value, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logcorrelation
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -----------------------------------------------------------------------------
+// Log Correlation
+//
+// The log rules inject the fields correlating the log records with the active
+// span, e.g. trace_id and span_id. Which fields are injected, under which keys
+// and in which format are configured once by the environment variables and
+// shared by all the log rules.
+
+const (
+	EnvFields   = "OTEL_LOGS_CORRELATION_FIELDS"
+	EnvIDFormat = "OTEL_LOGS_CORRELATION_ID_FORMAT"
+)
+
+// The fields that can be injected, each of them is injected under its own name
+// unless it's renamed
+const (
+	TraceID     = "trace_id"
+	SpanID      = "span_id"
+	TraceFlags  = "trace_flags"
+	Sampled     = "sampled"
+	ServiceName = "service_name"
+	TraceParent = "traceparent"
+)
+
+// The formats of the trace id and the span id, the decimal format is the
+// unsigned decimal of the lower 64 bits, as expected by e.g. Datadog
+const (
+	FormatHex     = "hex"
+	FormatDecimal = "decimal"
+)
+
+const defaultFields = TraceID + "," + SpanID
+
+var knownFields = map[string]bool{
+	TraceID: true, SpanID: true, TraceFlags: true,
+	Sampled: true, ServiceName: true, TraceParent: true,
+}
+
+// FieldSpec specifies a field to inject and the key of it in the log records
+type FieldSpec struct {
+	Name string
+	Key  string
+}
+
+type Config struct {
+	Fields      []FieldSpec
+	IDFormat    string
+	ServiceName string
+}
+
+// Field is the injected field, the values are always strings so that they are
+// rendered in the same way by all the log libraries
+type Field struct {
+	Key   string
+	Value string
+}
+
+// ParseFields parses the fields in the form of "name1=key1,name2", the field
+// is injected under its own name if the key is omitted
+func ParseFields(val string) ([]FieldSpec, error) {
+	var specs []FieldSpec
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(item, "=")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !knownFields[name] {
+			return nil, fmt.Errorf("unknown correlation field %q", name)
+		}
+		if !ok {
+			key = name
+		} else if key == "" {
+			return nil, fmt.Errorf("empty key of correlation field %q", name)
+		}
+		specs = append(specs, FieldSpec{Name: name, Key: key})
+	}
+	return specs, nil
+}
+
+// serviceNameFromEnv resolves the service name in the same way as the SDK
+// resource does, it's not read from the resource since the log rules may run
+// before the SDK is set up, e.g. during the package initialization
+func serviceNameFromEnv() string {
+	if name := strings.TrimSpace(os.Getenv("OTEL_SERVICE_NAME")); name != "" {
+		return name
+	}
+	for _, pair := range strings.Split(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"), ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) != "service.name" {
+			continue
+		}
+		if v, err := url.PathUnescape(strings.TrimSpace(value)); err == nil && v != "" {
+			return v
+		}
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "unknown_service:go"
+	}
+	return "unknown_service:" + filepath.Base(exe)
+}
+
+func ConfigFromEnv() Config {
+	conf := Config{IDFormat: FormatHex, ServiceName: serviceNameFromEnv()}
+	conf.Fields, _ = ParseFields(defaultFields)
+	if val := os.Getenv(EnvFields); val != "" {
+		fields, err := ParseFields(val)
+		if err != nil {
+			log.Printf("Invalid %s value, fallback to %s: %v", EnvFields,
+				defaultFields, err)
+		} else {
+			conf.Fields = fields
+		}
+	}
+	switch val := strings.ToLower(os.Getenv(EnvIDFormat)); val {
+	case "", FormatHex:
+	case FormatDecimal:
+		conf.IDFormat = FormatDecimal
+	default:
+		log.Printf("Invalid %s value: %s, fallback to %s", EnvIDFormat, val,
+			FormatHex)
+	}
+	return conf
+}
+
+var (
+	once    sync.Once
+	current atomic.Pointer[Config]
+)
+
+// The config is loaded lazily rather than during the package initialization,
+// because the log rules may run before this package is initialized
+func getConfig() *Config {
+	once.Do(func() {
+		conf := ConfigFromEnv()
+		current.CompareAndSwap(nil, &conf)
+	})
+	return current.Load()
+}
+
+// SetConfig replaces the config loaded from the environment variables
+func SetConfig(conf Config) {
+	once.Do(func() {})
+	current.Store(&conf)
+}
+
+// Keys returns the keys of the injected fields, the log rules should not inject
+// the fields whose keys are already present in the log records
+func Keys() []string {
+	conf := getConfig()
+	keys := make([]string, 0, len(conf.Fields))
+	for _, spec := range conf.Fields {
+		keys = append(keys, spec.Key)
+	}
+	return keys
+}
+
+// Fields returns the configured fields of the span context, nothing if the span
+// context is invalid
+func Fields(sc trace.SpanContext) []Field {
+	if !sc.IsValid() {
+		return nil
+	}
+	conf := getConfig()
+	fields := make([]Field, 0, len(conf.Fields))
+	for _, spec := range conf.Fields {
+		var value string
+		switch spec.Name {
+		case TraceID:
+			value = formatTraceID(sc.TraceID(), conf.IDFormat)
+		case SpanID:
+			value = formatSpanID(sc.SpanID(), conf.IDFormat)
+		case TraceFlags:
+			value = sc.TraceFlags().String()
+		case Sampled:
+			value = strconv.FormatBool(sc.IsSampled())
+		case ServiceName:
+			value = conf.ServiceName
+		case TraceParent:
+			value = "00-" + sc.TraceID().String() + "-" +
+				sc.SpanID().String() + "-" + sc.TraceFlags().String()
+		}
+		fields = append(fields, Field{Key: spec.Key, Value: value})
+	}
+	return fields
+}
+
+// SpanFields returns the configured fields of the span, nothing if the span is
+// nil
+func SpanFields(span trace.Span) []Field {
+	if span == nil {
+		return nil
+	}
+	return Fields(span.SpanContext())
+}
+
+func formatTraceID(id trace.TraceID, format string) string {
+	if format == FormatDecimal {
+		return strconv.FormatUint(binary.BigEndian.Uint64(id[8:]), 10)
+	}
+	return id.String()
+}
+
+func formatSpanID(id trace.SpanID, format string) string {
+	if format == FormatDecimal {
+		return strconv.FormatUint(binary.BigEndian.Uint64(id[:]), 10)
+	}
+	return id.String()
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logcorrelation
+
+import (
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+var testSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID: trace.TraceID{0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0x01, 0x02},
+	SpanID:     trace.SpanID{0, 0, 0, 0, 0, 0, 0, 0xff},
+	TraceFlags: trace.FlagsSampled,
+})
+
+func TestParseFields(t *testing.T) {
+	specs, err := ParseFields(" trace_id=traceId, span_id = spanId,sampled,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []FieldSpec{
+		{TraceID, "traceId"}, {SpanID, "spanId"}, {Sampled, Sampled},
+	}
+	if !reflect.DeepEqual(specs, expected) {
+		t.Errorf("expect %v, got %v", expected, specs)
+	}
+	for _, val := range []string{"trace", "trace_id="} {
+		if _, err := ParseFields(val); err == nil {
+			t.Errorf("%q is expected to be invalid", val)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvFields, "trace_id=dd.trace_id,service_name=dd.service")
+	t.Setenv(EnvIDFormat, "DECIMAL")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "a=b,service.name=my%20svc")
+	conf := ConfigFromEnv()
+	if conf.IDFormat != FormatDecimal || conf.ServiceName != "my svc" ||
+		len(conf.Fields) != 2 || conf.Fields[1].Key != "dd.service" {
+		t.Errorf("unexpected config %+v", conf)
+	}
+
+	t.Setenv(EnvFields, "unknown")
+	t.Setenv(EnvIDFormat, "base64")
+	t.Setenv("OTEL_SERVICE_NAME", "svc")
+	conf = ConfigFromEnv()
+	if conf.IDFormat != FormatHex || conf.ServiceName != "svc" ||
+		len(conf.Fields) != 2 || conf.Fields[0].Key != TraceID {
+		t.Errorf("expect to fallback to the defaults, got %+v", conf)
+	}
+}
+
+func TestFields(t *testing.T) {
+	defer SetConfig(ConfigFromEnv())
+	specs, _ := ParseFields("trace_id=traceId,span_id,trace_flags,sampled," +
+		"service_name,traceparent")
+	SetConfig(Config{Fields: specs, IDFormat: FormatHex, ServiceName: "svc"})
+	expected := []Field{
+		{"traceId", "00000000000000010000000000000102"},
+		{SpanID, "00000000000000ff"},
+		{TraceFlags, "01"},
+		{Sampled, "true"},
+		{ServiceName, "svc"},
+		{TraceParent, "00-00000000000000010000000000000102-00000000000000ff-01"},
+	}
+	if got := Fields(testSpanContext); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expect %v, got %v", expected, got)
+	}
+	if keys := Keys(); len(keys) != 6 || keys[0] != "traceId" {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	if got := Fields(trace.SpanContext{}); got != nil {
+		t.Errorf("expect no fields of invalid span context, got %v", got)
+	}
+	if got := SpanFields(nil); got != nil {
+		t.Errorf("expect no fields of nil span, got %v", got)
+	}
+
+	SetConfig(Config{Fields: specs[:2], IDFormat: FormatDecimal})
+	expected = []Field{{"traceId", "258"}, {SpanID, "255"}}
+	if got := Fields(testSpanContext); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expect %v, got %v", expected, got)
+	}
+}
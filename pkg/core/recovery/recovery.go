@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -----------------------------------------------------------------------------
+// Panic Recovery
+//
+// The trampolines of the instrumented functions recover the panics raised by
+// the hooks, so that a faulty hook never crashes the application, these panics
+// are counted per hook. Besides, the panics passing through the instrumented
+// functions can be recorded on the current span before they are propagated
+// again. The latter is disabled by default, because the propagated panic is
+// reported by the runtime as raised from the trampoline, although the recorded
+// stack trace still points to where it's raised.
+
+const EnvRecordPanic = "OTEL_INSTRUMENTATION_RECORD_PANIC_ENABLED"
+
+// hookPanics maps the hook name to the number of panics it raised
+var hookPanics sync.Map
+
+// ReportHookPanic counts the panic raised by the hook, it's called by the
+// trampolines after the panic is recovered
+func ReportHookPanic(hook string, err interface{}) {
+	cnt, ok := hookPanics.Load(hook)
+	if !ok {
+		cnt, _ = hookPanics.LoadOrStore(hook, new(atomic.Int64))
+	}
+	cnt.(*atomic.Int64).Add(1)
+}
+
+// HookPanics returns the number of panics raised by each hook
+func HookPanics() map[string]int64 {
+	panics := make(map[string]int64)
+	hookPanics.Range(func(key, value any) bool {
+		panics[key.(string)] = value.(*atomic.Int64).Load()
+		return true
+	})
+	return panics
+}
+
+// recorder is nil unless recording panics is enabled
+var recorder = recorderFromEnv()
+
+func recorderFromEnv() func(interface{}, []byte) {
+	if os.Getenv(EnvRecordPanic) != "true" {
+		return nil
+	}
+	return RecordPanic
+}
+
+// Recorder returns the function recording the panics passing through the
+// instrumented functions, or nil if the recording is disabled. Trampolines
+// never intercept the panics if there is no recorder
+func Recorder() func(interface{}, []byte) {
+	return recorder
+}
+
+// RecordPanic records the panic along with the stack trace as an exception
+// event of the current span, and marks the span as failed
+func RecordPanic(err interface{}, stack []byte) {
+	// The current span is taken from the goroutine local storage if there is no
+	// span in the context, see otel-context rules
+	recordPanic(trace.SpanFromContext(context.Background()), err, stack)
+}
+
+func recordPanic(span trace.Span, err interface{}, stack []byte) {
+	if span == nil || !span.IsRecording() {
+		return
+	}
+	msg := fmt.Sprint(err)
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", err)),
+		semconv.ExceptionMessage(msg),
+		semconv.ExceptionStacktrace(string(stack)),
+	))
+	span.SetStatus(codes.Error, "panic: "+msg)
+}
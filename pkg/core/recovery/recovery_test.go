@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recovery
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestReportHookPanic(t *testing.T) {
+	ReportHookPanic("onEnterFoo", "boom")
+	ReportHookPanic("onEnterFoo", errors.New("boom"))
+	ReportHookPanic("onExitFoo", nil)
+	panics := HookPanics()
+	if panics["onEnterFoo"] != 2 || panics["onExitFoo"] != 1 {
+		t.Errorf("unexpected hook panics %v", panics)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Setenv(EnvRecordPanic, "")
+	if recorderFromEnv() != nil {
+		t.Error("recording panics is expected to be disabled by default")
+	}
+	t.Setenv(EnvRecordPanic, "true")
+	if recorderFromEnv() == nil {
+		t.Error("recording panics is expected to be enabled")
+	}
+}
+
+func TestRecordPanic(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := tp.Tracer("test").Start(context.Background(), "foo")
+	recordPanic(span, errors.New("boom"), []byte("goroutine 1 [running]:"))
+	span.End()
+	// Nothing happens if there is no current span
+	recordPanic(nil, "boom", nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expect 1 span, got %d", len(spans))
+	}
+	status := spans[0].Status()
+	if status.Code != codes.Error || status.Description != "panic: boom" {
+		t.Errorf("unexpected status %v", status)
+	}
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Name != "exception" {
+		t.Fatalf("unexpected events %v", events)
+	}
+	attrs := make(map[string]string)
+	for _, attr := range events[0].Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs["exception.type"] != "*errors.errorString" ||
+		attrs["exception.message"] != "boom" ||
+		!strings.HasPrefix(attrs["exception.stacktrace"], "goroutine 1") {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+}
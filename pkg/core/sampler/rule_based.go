@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampler
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/config"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var spanKinds = map[string]trace.SpanKind{
+	"internal": trace.SpanKindInternal,
+	"server":   trace.SpanKindServer,
+	"client":   trace.SpanKindClient,
+	"producer": trace.SpanKindProducer,
+	"consumer": trace.SpanKindConsumer,
+}
+
+type samplingRule struct {
+	decision   sdktrace.SamplingDecision
+	kind       trace.SpanKind
+	name       *regexp.Regexp
+	attributes map[attribute.Key]*regexp.Regexp
+}
+
+type ruleSet struct {
+	rules    []*samplingRule
+	fallback sdktrace.Sampler
+}
+
+// RuleBasedSampler samples the span by the first matching rule, so that e.g.
+// the health checks are dropped while the payment RPCs are always sampled, and
+// the spans matching none of the rules are left to the fallback sampler. The
+// rules can be replaced at runtime by Update.
+type RuleBasedSampler struct {
+	rules atomic.Pointer[ruleSet]
+}
+
+func NewRuleBasedSampler(conf *config.RuleBasedSampler) (*RuleBasedSampler, error) {
+	s := &RuleBasedSampler{}
+	if err := s.Update(conf); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// compilePattern compiles the pattern which must match the whole value
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+func compileRule(conf *config.SamplingRule) (*samplingRule, error) {
+	rule := &samplingRule{
+		decision:   sdktrace.Drop,
+		kind:       trace.SpanKindUnspecified,
+		attributes: make(map[attribute.Key]*regexp.Regexp),
+	}
+	if conf.Action == config.SamplingActionRecordAndSample {
+		rule.decision = sdktrace.RecordAndSample
+	}
+	if conf.SpanKind != "" {
+		kind, ok := spanKinds[conf.SpanKind]
+		if !ok {
+			return nil, fmt.Errorf("unknown span kind %q", conf.SpanKind)
+		}
+		rule.kind = kind
+	}
+	var err error
+	if conf.Name != "" {
+		rule.name, err = compilePattern(conf.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for key, pattern := range conf.Attributes {
+		rule.attributes[attribute.Key(key)], err = compilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rule, nil
+}
+
+// Update replaces the rules and the fallback sampler
+func (s *RuleBasedSampler) Update(conf *config.RuleBasedSampler) error {
+	rs := &ruleSet{}
+	for i, ruleConf := range conf.Rules {
+		rule, err := compileRule(ruleConf)
+		if err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		rs.rules = append(rs.rules, rule)
+	}
+	rs.fallback = FromConfig(conf.Fallback)
+	if rs.fallback == nil {
+		rs.fallback = defaultSampler()
+	}
+	s.rules.Store(rs)
+	return nil
+}
+
+func (r *samplingRule) matches(p sdktrace.SamplingParameters) bool {
+	if r.kind != trace.SpanKindUnspecified && r.kind != p.Kind {
+		return false
+	}
+	if r.name != nil && !r.name.MatchString(p.Name) {
+		return false
+	}
+	for key, pattern := range r.attributes {
+		found := false
+		for _, attr := range p.Attributes {
+			if attr.Key == key {
+				found = pattern.MatchString(attr.Value.Emit())
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *RuleBasedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	rs := s.rules.Load()
+	for _, rule := range rs.rules {
+		if rule.matches(p) {
+			return sdktrace.SamplingResult{
+				Decision:   rule.decision,
+				Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+			}
+		}
+	}
+	return rs.fallback.ShouldSample(p)
+}
+
+func (s *RuleBasedSampler) Description() string {
+	rs := s.rules.Load()
+	return fmt.Sprintf("RuleBased{rules:%d,fallback:%s}", len(rs.rules),
+		rs.fallback.Description())
+}
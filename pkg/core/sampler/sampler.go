@@ -0,0 +1,205 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampler
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	EnvTracesSampler    = "OTEL_TRACES_SAMPLER"
+	EnvTracesSamplerArg = "OTEL_TRACES_SAMPLER_ARG"
+	// EnvTraceSampler is the legacy sampler ratio, it's honored only if
+	// OTEL_TRACES_SAMPLER is absent
+	EnvTraceSampler = "OTEL_TRACE_SAMPLER"
+)
+
+func defaultSampler() sdktrace.Sampler {
+	return sdktrace.ParentBased(sdktrace.AlwaysSample())
+}
+
+// FromConfig builds the sampler from the config file, nil is returned if the
+// sampler is not configured
+func FromConfig(s *config.Sampler) sdktrace.Sampler {
+	switch {
+	case s == nil:
+		return nil
+	case s.AlwaysOn != nil:
+		return sdktrace.AlwaysSample()
+	case s.AlwaysOff != nil:
+		return sdktrace.NeverSample()
+	case s.TraceIDRatioBased != nil:
+		ratio := 1.0
+		if s.TraceIDRatioBased.Ratio != nil {
+			ratio = *s.TraceIDRatioBased.Ratio
+		}
+		return sdktrace.TraceIDRatioBased(ratio)
+	case s.ParentBased != nil:
+		pb := s.ParentBased
+		root := FromConfig(pb.Root)
+		if root == nil {
+			root = sdktrace.AlwaysSample()
+		}
+		var opts []sdktrace.ParentBasedSamplerOption
+		if sampler := FromConfig(pb.RemoteParentSampled); sampler != nil {
+			opts = append(opts, sdktrace.WithRemoteParentSampled(sampler))
+		}
+		if sampler := FromConfig(pb.RemoteParentNotSampled); sampler != nil {
+			opts = append(opts, sdktrace.WithRemoteParentNotSampled(sampler))
+		}
+		if sampler := FromConfig(pb.LocalParentSampled); sampler != nil {
+			opts = append(opts, sdktrace.WithLocalParentSampled(sampler))
+		}
+		if sampler := FromConfig(pb.LocalParentNotSampled); sampler != nil {
+			opts = append(opts, sdktrace.WithLocalParentNotSampled(sampler))
+		}
+		return sdktrace.ParentBased(root, opts...)
+	case s.RuleBased != nil:
+		sampler, err := NewRuleBasedSampler(s.RuleBased)
+		if err != nil {
+			log.Printf("Invalid rule based sampler in config file, "+
+				"fallback to parent based sampler: %v", err)
+			return defaultSampler()
+		}
+		return sampler
+	default:
+		log.Printf("Unknown sampler in config file, fallback to parent based sampler")
+		return defaultSampler()
+	}
+}
+
+// parseRatio parses OTEL_TRACES_SAMPLER_ARG of the ratio based samplers, which
+// defaults to 1.0 as the specification requires
+func parseRatio(arg string) float64 {
+	if arg == "" {
+		return 1.0
+	}
+	ratio, err := strconv.ParseFloat(arg, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		log.Printf("Invalid %s value: %s, fallback to 1.0",
+			EnvTracesSamplerArg, arg)
+		return 1.0
+	}
+	return ratio
+}
+
+func fromLegacyEnv(val string) sdktrace.Sampler {
+	ratio, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		log.Printf("Invalid %s value: %s, fallback to parent based sampler",
+			EnvTraceSampler, val)
+		return defaultSampler()
+	}
+	if ratio <= 0 {
+		return sdktrace.NeverSample()
+	} else if ratio >= 1 {
+		return sdktrace.AlwaysSample()
+	}
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+}
+
+func loadRules(path string) (*config.RuleBasedSampler, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := config.ParseRuleBasedSampler(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sampling rules file %s: %w", path, err)
+	}
+	return conf, nil
+}
+
+// newRuleBasedFromFile loads the sampling rules from the file and watches it
+// for changes afterwards, like the config file
+func newRuleBasedFromFile(path string) (sdktrace.Sampler, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%s is expected to be the path of the "+
+			"sampling rules file", EnvTracesSamplerArg)
+	}
+	conf, err := loadRules(path)
+	if err != nil {
+		return nil, err
+	}
+	sampler, err := NewRuleBasedSampler(conf)
+	if err != nil {
+		return nil, err
+	}
+	config.WatchFile(path, func() {
+		conf, err := loadRules(path)
+		if err == nil {
+			err = sampler.Update(conf)
+		}
+		if err != nil {
+			log.Printf("Failed to reload sampling rules, keep the previous "+
+				"ones: %v", err)
+			return
+		}
+		log.Printf("Reloaded sampling rules file %s", path)
+	})
+	return sampler, nil
+}
+
+// FromEnv builds the sampler from OTEL_TRACES_SAMPLER and
+// OTEL_TRACES_SAMPLER_ARG, see
+// https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/#general-sdk-configuration
+// In addition to the standard values, rule_based and parentbased_rule_based
+// load the sampling rules from the file specified by OTEL_TRACES_SAMPLER_ARG.
+func FromEnv() sdktrace.Sampler {
+	name := strings.ToLower(strings.TrimSpace(os.Getenv(EnvTracesSampler)))
+	arg := strings.TrimSpace(os.Getenv(EnvTracesSamplerArg))
+	if name == "" {
+		if legacy := strings.TrimSpace(os.Getenv(EnvTraceSampler)); legacy != "" {
+			return fromLegacyEnv(legacy)
+		}
+		return defaultSampler()
+	}
+	switch name {
+	case "always_on":
+		return sdktrace.AlwaysSample()
+	case "always_off":
+		return sdktrace.NeverSample()
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(parseRatio(arg))
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample())
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(parseRatio(arg)))
+	case "rule_based", "parentbased_rule_based":
+		sampler, err := newRuleBasedFromFile(arg)
+		if err != nil {
+			log.Printf("Failed to create rule based sampler, fallback to "+
+				"parent based sampler: %v", err)
+			return defaultSampler()
+		}
+		if name == "parentbased_rule_based" {
+			return sdktrace.ParentBased(sampler)
+		}
+		return sampler
+	default:
+		log.Printf("Unsupported %s value: %s, fallback to parent based sampler",
+			EnvTracesSampler, name)
+		return defaultSampler()
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/config"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testRules = `
+rules:
+  - action: drop
+    span_kind: server
+    attributes:
+      http.route: /health.*
+  - action: record_and_sample
+    attributes:
+      rpc.method: Pay.*
+  - action: drop
+    name: SELECT
+fallback:
+  always_off:
+`
+
+func params(name string, kind trace.SpanKind, attrs ...attribute.KeyValue) sdktrace.SamplingParameters {
+	return sdktrace.SamplingParameters{
+		ParentContext: context.Background(),
+		TraceID:       trace.TraceID{1},
+		Name:          name,
+		Kind:          kind,
+		Attributes:    attrs,
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	for _, c := range []struct {
+		sampler     string
+		arg         string
+		description string
+	}{
+		{"", "", "ParentBased{root:AlwaysOnSampler"},
+		{"always_off", "", "AlwaysOffSampler"},
+		{"traceidratio", "0.5", "TraceIDRatioBased{0.5}"},
+		{"traceidratio", "invalid", "AlwaysOnSampler"},
+		{"parentbased_traceidratio", "0.25", "ParentBased{root:TraceIDRatioBased{0.25}"},
+		{"PARENTBASED_ALWAYS_OFF", "", "ParentBased{root:AlwaysOffSampler"},
+		{"jaeger_remote", "", "ParentBased{root:AlwaysOnSampler"},
+		{"rule_based", "", "ParentBased{root:AlwaysOnSampler"},
+	} {
+		t.Setenv(EnvTracesSampler, c.sampler)
+		t.Setenv(EnvTracesSamplerArg, c.arg)
+		description := FromEnv().Description()
+		if !strings.HasPrefix(description, c.description) {
+			t.Errorf("%s=%s: expect %s, got %s", c.sampler, c.arg,
+				c.description, description)
+		}
+	}
+}
+
+func TestFromLegacyEnv(t *testing.T) {
+	t.Setenv(EnvTraceSampler, "0")
+	if FromEnv().Description() != "AlwaysOffSampler" {
+		t.Errorf("legacy sampler ratio is expected to be honored")
+	}
+	t.Setenv(EnvTracesSampler, "always_on")
+	if FromEnv().Description() != "AlwaysOnSampler" {
+		t.Errorf("%s is expected to take precedence", EnvTracesSampler)
+	}
+}
+
+func TestRuleBasedSampler(t *testing.T) {
+	conf, err := config.ParseRuleBasedSampler([]byte(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampler, err := NewRuleBasedSampler(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		params   sdktrace.SamplingParameters
+		decision sdktrace.SamplingDecision
+	}{
+		{params("GET /healthz", trace.SpanKindServer,
+			attribute.String("http.route", "/healthz")), sdktrace.Drop},
+		// The span kind does not match, dropped by the fallback sampler
+		{params("GET /healthz", trace.SpanKindClient,
+			attribute.String("http.route", "/healthz")), sdktrace.Drop},
+		{params("PaymentService/Pay", trace.SpanKindServer,
+			attribute.String("rpc.method", "PayOrder")), sdktrace.RecordAndSample},
+		// The pattern must match the whole value, dropped by the fallback sampler
+		{params("PaymentService/Pay", trace.SpanKindServer,
+			attribute.String("rpc.method", "RePay")), sdktrace.Drop},
+		{params("SELECT", trace.SpanKindClient,
+			attribute.String("db.system", "mysql")), sdktrace.Drop},
+	} {
+		result := sampler.ShouldSample(c.params)
+		if result.Decision != c.decision {
+			t.Errorf("%s %v: expect %v, got %v", c.params.Name,
+				c.params.Attributes, c.decision, result.Decision)
+		}
+	}
+	conf.Fallback = nil
+	if err = sampler.Update(conf); err != nil {
+		t.Fatal(err)
+	}
+	result := sampler.ShouldSample(params("GET /healthz", trace.SpanKindClient,
+		attribute.String("http.route", "/healthz")))
+	if result.Decision != sdktrace.RecordAndSample {
+		t.Errorf("default fallback sampler is expected to sample the span")
+	}
+}
+
+func TestParseInvalidRules(t *testing.T) {
+	for _, content := range []string{
+		"rules:\n  - action: keep\n    name: x",
+		"rules:\n  - action: drop",
+		"rules:\n  - action: drop\n    span_kind: unknown\n    name: x",
+		"rules:\n  - action: drop\n    name: '('",
+	} {
+		if _, err := config.ParseRuleBasedSampler([]byte(content)); err == nil {
+			t.Errorf("expect error for %q", content)
+		}
+	}
+}
+
+func TestRuleBasedFromFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("rules:\n  - action: drop\n    name: GET\n")
+	t.Setenv(config.EnvConfigReloadInterval, "10")
+	t.Setenv(EnvTracesSampler, "rule_based")
+	t.Setenv(EnvTracesSamplerArg, file)
+	sampler := FromEnv()
+	p := params("GET", trace.SpanKindServer)
+	if sampler.ShouldSample(p).Decision != sdktrace.Drop {
+		t.Fatal("span is expected to be dropped by the rules file")
+	}
+	write("rules:\n  - action: drop\n    name: POST\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for sampler.ShouldSample(p).Decision == sdktrace.Drop {
+		if time.Now().After(deadline) {
+			t.Fatal("sampling rules file is not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selftelemetry
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	_ "unsafe"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/recovery"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -----------------------------------------------------------------------------
+// Self Telemetry
+//
+// The agent reports its own overhead and health through the configured meter
+// provider, i.e. how often the hooks are invoked and how long they take, and
+// how many spans are suppressed, dropped or failed to export. Most events are
+// counted in memory from the very beginning, because they may happen before
+// the meter provider is set up, and they are observed by asynchronous counters
+// once it's set up. Hooks are timed only if the self telemetry is enabled, as
+// it's not free to read the clock twice per hook invocation.
+
+const EnvSelfTelemetry = "OTEL_INSTRUMENTATION_SELF_TELEMETRY_ENABLED"
+
+// ScopeName is the instrumentation scope of the self telemetry metrics
+const ScopeName = "loongsuite.agent"
+
+const (
+	MetricHookInvocations    = "loongsuite.agent.hook.invocations"
+	MetricHookDuration       = "loongsuite.agent.hook.duration"
+	MetricHookPanics         = "loongsuite.agent.hook.panics"
+	MetricSpanSuppressed     = "loongsuite.agent.span.suppressed"
+	MetricSpanContextDropped = "loongsuite.agent.span.trace_context_dropped"
+	MetricSpanQueueDropped   = "loongsuite.agent.span.queue_dropped"
+	MetricSpanExportFailed   = "loongsuite.agent.span.export_failed"
+	AttrRule                 = attribute.Key("rule")
+	AttrHook                 = attribute.Key("hook")
+	AttrScope                = attribute.Key("scope")
+	AttrSpanKind             = attribute.Key("span.kind")
+	AttrExporter             = attribute.Key("exporter")
+)
+
+// Hooks take microseconds typically, the default boundaries of the histogram
+// are far too coarse for them
+var hookDurationBoundaries = []float64{
+	0.000001, 0.000005, 0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005,
+	0.001, 0.005, 0.01, 0.1,
+}
+
+// SDKStats reads the statistics kept by the SDK internals, which are only
+// accessible after the SDK is instrumented
+type SDKStats struct {
+	// TraceContextDropped returns the number of spans not tracked by the trace
+	// context of goroutines since it's full
+	TraceContextDropped func() int64
+	// QueueDropped returns the number of spans dropped by the batch span
+	// processors since their queues are full, keyed by the exporter name
+	QueueDropped func() map[string]int64
+}
+
+type hookKey struct {
+	rule string
+	hook string
+}
+
+type hookStat struct {
+	invocations atomic.Int64
+	attrs       metric.MeasurementOption
+}
+
+type suppressKey struct {
+	scope string
+	kind  trace.SpanKind
+}
+
+// Recording the hook duration may invoke instrumented functions as well, e.g.
+// the exemplar filter looks up the span from the context, their hooks must not
+// be recorded again on the same goroutine, otherwise it deadlocks. The flag is
+// kept in the goroutine and swapped by the instrumented runtime
+//
+//go:linkname otel_swap_self_telemetry_busy otel_swap_self_telemetry_busy
+var otel_swap_self_telemetry_busy func(bool) bool
+
+var (
+	epoch        = time.Now()
+	hooksTimed   atomic.Bool
+	hookDuration metric.Float64Histogram
+	hookStats    sync.Map // hookKey -> *hookStat
+	suppressed   sync.Map // suppressKey -> *atomic.Int64
+	exportFailed sync.Map // exporter name -> *atomic.Int64
+)
+
+func counterOf(counters *sync.Map, key any) *atomic.Int64 {
+	cnt, ok := counters.Load(key)
+	if !ok {
+		cnt, _ = counters.LoadOrStore(key, new(atomic.Int64))
+	}
+	return cnt.(*atomic.Int64)
+}
+
+func Enabled() bool {
+	return os.Getenv(EnvSelfTelemetry) == "true"
+}
+
+// HookStart returns the start time of the hook invocation, or zero if hooks
+// are not timed. It's called by the trampolines before the hook is invoked
+func HookStart() int64 {
+	if !hooksTimed.Load() {
+		return 0
+	}
+	return int64(time.Since(epoch)) + 1
+}
+
+// HookEnd counts the hook invocation of the rule and records its duration, it's
+// called by the trampolines after the hook returns or panics
+func HookEnd(rule, hook string, start int64) {
+	if start == 0 {
+		return
+	}
+	elapsed := time.Since(epoch) - time.Duration(start-1)
+	swapBusy := otel_swap_self_telemetry_busy
+	if swapBusy != nil && swapBusy(true) {
+		return
+	}
+	key := hookKey{rule, hook}
+	stat, ok := hookStats.Load(key)
+	if !ok {
+		stat, _ = hookStats.LoadOrStore(key, &hookStat{
+			attrs: metric.WithAttributeSet(attribute.NewSet(
+				AttrRule.String(rule), AttrHook.String(hook))),
+		})
+	}
+	stat.(*hookStat).invocations.Add(1)
+	hookDuration.Record(context.Background(), elapsed.Seconds(),
+		stat.(*hookStat).attrs)
+	if swapBusy != nil {
+		swapBusy(false)
+	}
+}
+
+// SpanSuppressed counts the span suppressed by the instrumentation, e.g. the
+// client span nested in another client span of the same kind
+func SpanSuppressed(scope string, kind trace.SpanKind) {
+	counterOf(&suppressed, suppressKey{scope, kind}).Add(1)
+}
+
+type spanExporter struct {
+	sdktrace.SpanExporter
+	failed *atomic.Int64
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context,
+	spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		e.failed.Add(int64(len(spans)))
+	}
+	return err
+}
+
+// WrapSpanExporter wraps the exporter to count the spans failed to export
+func WrapSpanExporter(name string,
+	exporter sdktrace.SpanExporter) sdktrace.SpanExporter {
+	return &spanExporter{exporter, counterOf(&exportFailed, name)}
+}
+
+// Init registers the self telemetry metrics on the meter provider and starts
+// timing the hooks, it does nothing unless the self telemetry is enabled
+func Init(mp metric.MeterProvider, stats SDKStats) error {
+	if !Enabled() {
+		return nil
+	}
+	m := mp.Meter(ScopeName)
+	duration, err := m.Float64Histogram(MetricHookDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of the hook invocations, including the trampolines"),
+		metric.WithExplicitBucketBoundaries(hookDurationBoundaries...))
+	if err != nil {
+		return err
+	}
+	invocations, err := m.Int64ObservableCounter(MetricHookInvocations,
+		metric.WithUnit("{invocation}"),
+		metric.WithDescription("Number of the hook invocations"))
+	if err != nil {
+		return err
+	}
+	panics, err := m.Int64ObservableCounter(MetricHookPanics,
+		metric.WithUnit("{panic}"),
+		metric.WithDescription("Number of the panics raised by the hooks"))
+	if err != nil {
+		return err
+	}
+	spanSuppressed, err := m.Int64ObservableCounter(MetricSpanSuppressed,
+		metric.WithUnit("{span}"),
+		metric.WithDescription("Number of the spans suppressed by the instrumentations"))
+	if err != nil {
+		return err
+	}
+	contextDropped, err := m.Int64ObservableCounter(MetricSpanContextDropped,
+		metric.WithUnit("{span}"),
+		metric.WithDescription("Number of the spans not tracked by the trace context of goroutines since it's full"))
+	if err != nil {
+		return err
+	}
+	queueDropped, err := m.Int64ObservableCounter(MetricSpanQueueDropped,
+		metric.WithUnit("{span}"),
+		metric.WithDescription("Number of the spans dropped since the queue of the span processor is full"))
+	if err != nil {
+		return err
+	}
+	exportFailures, err := m.Int64ObservableCounter(MetricSpanExportFailed,
+		metric.WithUnit("{span}"),
+		metric.WithDescription("Number of the spans failed to export"))
+	if err != nil {
+		return err
+	}
+	_, err = m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		hookStats.Range(func(_, value any) bool {
+			stat := value.(*hookStat)
+			o.ObserveInt64(invocations, stat.invocations.Load(), stat.attrs)
+			return true
+		})
+		for hook, cnt := range recovery.HookPanics() {
+			o.ObserveInt64(panics, cnt,
+				metric.WithAttributes(AttrHook.String(hook)))
+		}
+		suppressed.Range(func(key, value any) bool {
+			k := key.(suppressKey)
+			o.ObserveInt64(spanSuppressed, value.(*atomic.Int64).Load(),
+				metric.WithAttributes(AttrScope.String(k.scope),
+					AttrSpanKind.String(k.kind.String())))
+			return true
+		})
+		if stats.TraceContextDropped != nil {
+			o.ObserveInt64(contextDropped, stats.TraceContextDropped())
+		}
+		if stats.QueueDropped != nil {
+			for exporter, cnt := range stats.QueueDropped() {
+				o.ObserveInt64(queueDropped, cnt,
+					metric.WithAttributes(AttrExporter.String(exporter)))
+			}
+		}
+		exportFailed.Range(func(key, value any) bool {
+			o.ObserveInt64(exportFailures, value.(*atomic.Int64).Load(),
+				metric.WithAttributes(AttrExporter.String(key.(string))))
+			return true
+		})
+		return nil
+	}, invocations, panics, spanSuppressed, contextDropped, queueDropped,
+		exportFailures)
+	if err != nil {
+		return err
+	}
+	hookDuration = duration
+	hooksTimed.Store(true)
+	return nil
+}
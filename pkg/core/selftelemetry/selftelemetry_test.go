@@ -0,0 +1,132 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selftelemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/recovery"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type failingExporter struct {
+	*tracetest.NoopExporter
+}
+
+func (failingExporter) ExportSpans(context.Context,
+	[]sdktrace.ReadOnlySpan) error {
+	return errors.New("unavailable")
+}
+
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		if sm.Scope.Name != ScopeName {
+			continue
+		}
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func sumOf(t *testing.T, data metricdata.Aggregation) int64 {
+	sum, ok := data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("unexpected aggregation %T", data)
+	}
+	total := int64(0)
+	for _, dp := range sum.DataPoints {
+		total += dp.Value
+	}
+	return total
+}
+
+func TestSelfTelemetry(t *testing.T) {
+	// Hooks are not timed until the self telemetry is enabled
+	if HookStart() != 0 {
+		t.Fatal("hooks are expected not to be timed")
+	}
+	HookEnd("net/http.(*Client).Do", "clientOnEnter", 0)
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	if err := Init(mp, SDKStats{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(collect(t, reader)) != 0 {
+		t.Fatal("self telemetry is expected to be disabled by default")
+	}
+
+	t.Setenv(EnvSelfTelemetry, "true")
+	err := Init(mp, SDKStats{
+		TraceContextDropped: func() int64 { return 3 },
+		QueueDropped: func() map[string]int64 {
+			return map[string]int64{"otlp": 5}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		start := HookStart()
+		if start == 0 {
+			t.Fatal("hooks are expected to be timed")
+		}
+		HookEnd("net/http.(*Client).Do", "clientOnEnter", start)
+	}
+	recovery.ReportHookPanic("clientOnExit", "boom")
+	SpanSuppressed("loongsuite.instrumentation.http", trace.SpanKindClient)
+	exporter := WrapSpanExporter("otlp", failingExporter{tracetest.NewNoopExporter()})
+	err = exporter.ExportSpans(context.Background(), make([]sdktrace.ReadOnlySpan, 4))
+	if err == nil {
+		t.Fatal("export error is expected to be returned")
+	}
+
+	metrics := collect(t, reader)
+	if got := sumOf(t, metrics[MetricHookInvocations]); got != 2 {
+		t.Errorf("expect 2 hook invocations, got %d", got)
+	}
+	duration, ok := metrics[MetricHookDuration].(metricdata.Histogram[float64])
+	if !ok || len(duration.DataPoints) != 1 || duration.DataPoints[0].Count != 2 {
+		t.Errorf("unexpected hook duration %v", metrics[MetricHookDuration])
+	}
+	if got := sumOf(t, metrics[MetricHookPanics]); got != 1 {
+		t.Errorf("expect 1 hook panic, got %d", got)
+	}
+	if got := sumOf(t, metrics[MetricSpanSuppressed]); got != 1 {
+		t.Errorf("expect 1 suppressed span, got %d", got)
+	}
+	if got := sumOf(t, metrics[MetricSpanContextDropped]); got != 3 {
+		t.Errorf("expect 3 spans dropped by trace context, got %d", got)
+	}
+	if got := sumOf(t, metrics[MetricSpanQueueDropped]); got != 5 {
+		t.Errorf("expect 5 spans dropped by queue, got %d", got)
+	}
+	if got := sumOf(t, metrics[MetricSpanExportFailed]); got != 4 {
+		t.Errorf("expect 4 spans failed to export, got %d", got)
+	}
+}
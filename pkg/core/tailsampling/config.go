@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	EnvDecisionWait     = "OTEL_EXPERIMENTAL_TAIL_SAMPLING_DECISION_WAIT"
+	EnvMaxTraces        = "OTEL_EXPERIMENTAL_TAIL_SAMPLING_MAX_TRACES"
+	EnvMaxSpansPerTrace = "OTEL_EXPERIMENTAL_TAIL_SAMPLING_MAX_SPANS_PER_TRACE"
+	EnvKeepErrors       = "OTEL_EXPERIMENTAL_TAIL_SAMPLING_ERRORS"
+	EnvLatencyThreshold = "OTEL_EXPERIMENTAL_TAIL_SAMPLING_LATENCY_THRESHOLD"
+	EnvAttributes       = "OTEL_EXPERIMENTAL_TAIL_SAMPLING_ATTRIBUTES"
+	EnvRatio            = "OTEL_EXPERIMENTAL_TAIL_SAMPLING_RATIO"
+)
+
+// Config configures the tail sampling processor. A trace is kept if any of its
+// spans has the error status, lasts longer than the latency threshold, or has
+// an attribute matching the predicates, the rest are kept by the ratio.
+type Config struct {
+	// How long to wait for the spans of a trace before making the decision,
+	// the decision is made as soon as the local root span ends otherwise
+	DecisionWait time.Duration
+	// Maximum number of traces buffered, the oldest trace is decided early if
+	// the limit is reached
+	MaxTraces int
+	// Maximum number of spans buffered for a single trace, the trace is
+	// decided early if the limit is reached
+	MaxSpansPerTrace int
+	KeepErrors       bool
+	// Zero disables the latency policy
+	LatencyThreshold time.Duration
+	// The attribute values are matched by regular expressions, which must
+	// match the whole value
+	Attributes map[attribute.Key]*regexp.Regexp
+	// Ratio of the traces kept if none of the policies matches
+	Ratio float64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		DecisionWait:     5 * time.Second,
+		MaxTraces:        10000,
+		MaxSpansPerTrace: 1000,
+		KeepErrors:       true,
+		Attributes:       make(map[attribute.Key]*regexp.Regexp),
+	}
+}
+
+// ParseAttributes parses the attribute predicates in the form of
+// "key1=regexp1,key2=regexp2"
+func ParseAttributes(val string) (map[attribute.Key]*regexp.Regexp, error) {
+	attrs := make(map[attribute.Key]*regexp.Regexp)
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, pattern, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid attribute predicate %q", pair)
+		}
+		re, err := regexp.Compile("^(?:" + strings.TrimSpace(pattern) + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute predicate %q: %w",
+				pair, err)
+		}
+		attrs[attribute.Key(strings.TrimSpace(key))] = re
+	}
+	return attrs, nil
+}
+
+func getPositiveInt(name string, def int) int {
+	val := os.Getenv(name)
+	if val == "" {
+		return def
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid %s value: %s, fallback to %d", name, val, def)
+		return def
+	}
+	return n
+}
+
+// ConfigFromEnv reads the config from the environment variables, durations are
+// in milliseconds
+func ConfigFromEnv() Config {
+	conf := DefaultConfig()
+	conf.DecisionWait = time.Duration(getPositiveInt(EnvDecisionWait,
+		int(conf.DecisionWait/time.Millisecond))) * time.Millisecond
+	conf.MaxTraces = getPositiveInt(EnvMaxTraces, conf.MaxTraces)
+	conf.MaxSpansPerTrace = getPositiveInt(EnvMaxSpansPerTrace,
+		conf.MaxSpansPerTrace)
+	if val := os.Getenv(EnvKeepErrors); val != "" {
+		keep, err := strconv.ParseBool(val)
+		if err != nil {
+			log.Printf("Invalid %s value: %s, fallback to %v", EnvKeepErrors,
+				val, conf.KeepErrors)
+		} else {
+			conf.KeepErrors = keep
+		}
+	}
+	conf.LatencyThreshold = time.Duration(getPositiveInt(EnvLatencyThreshold,
+		0)) * time.Millisecond
+	if val := os.Getenv(EnvAttributes); val != "" {
+		attrs, err := ParseAttributes(val)
+		if err != nil {
+			log.Printf("Invalid %s value, ignored: %v", EnvAttributes, err)
+		} else {
+			conf.Attributes = attrs
+		}
+	}
+	if val := os.Getenv(EnvRatio); val != "" {
+		ratio, err := strconv.ParseFloat(val, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			log.Printf("Invalid %s value: %s, fallback to 0", EnvRatio, val)
+		} else {
+			conf.Ratio = ratio
+		}
+	}
+	return conf
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -----------------------------------------------------------------------------
+// Tail Sampling Processor
+//
+// Head sampling decides whether to sample a trace before anything happens, so
+// the rare slow or failed requests are very likely lost with a low ratio. The
+// tail sampling processor buffers the ended spans by trace ID instead, and
+// decides once the local fragment of the trace is complete, i.e. the local root
+// span ends or the decision wait expires. Only the kept traces are passed to
+// the downstream processors such as the batch span processor. Since it works
+// within a single process, a trace that spans multiple services may be kept by
+// some of them and dropped by the others.
+
+type traceBuffer struct {
+	spans []sdktrace.ReadOnlySpan
+	// Whether any of the spans matches the policies
+	matched   bool
+	firstSeen time.Time
+	elem      *list.Element
+}
+
+type Processor struct {
+	conf Config
+	next []sdktrace.SpanProcessor
+
+	lock   sync.Mutex
+	traces map[trace.TraceID]*traceBuffer
+	// Buffered traces in the order of arrival, for eviction and expiration
+	order *list.List
+	// Recent decisions, so that the spans ending after the decision are
+	// handled consistently with the rest of the trace
+	decisions     map[trace.TraceID]bool
+	decisionOrder []trace.TraceID
+	decisionIdx   int
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewProcessor(conf Config, next ...sdktrace.SpanProcessor) *Processor {
+	p := &Processor{
+		conf:          conf,
+		next:          next,
+		traces:        make(map[trace.TraceID]*traceBuffer),
+		order:         list.New(),
+		decisions:     make(map[trace.TraceID]bool),
+		decisionOrder: make([]trace.TraceID, 0, conf.MaxTraces),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go p.expire()
+	return p
+}
+
+func (p *Processor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	for _, next := range p.next {
+		next.OnStart(parent, s)
+	}
+}
+
+func (p *Processor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	tid := s.SpanContext().TraceID()
+	var spans []sdktrace.ReadOnlySpan
+	p.lock.Lock()
+	if keep, ok := p.decisions[tid]; ok {
+		p.lock.Unlock()
+		if keep {
+			p.forward([]sdktrace.ReadOnlySpan{s})
+		}
+		return
+	}
+	buf, ok := p.traces[tid]
+	if !ok {
+		if p.order.Len() >= p.conf.MaxTraces {
+			spans = append(spans, p.decide(p.order.Front().Value.(trace.TraceID))...)
+		}
+		buf = &traceBuffer{firstSeen: time.Now()}
+		buf.elem = p.order.PushBack(tid)
+		p.traces[tid] = buf
+	}
+	buf.spans = append(buf.spans, s)
+	buf.matched = buf.matched || p.matches(s)
+	if isLocalRoot(s) || len(buf.spans) >= p.conf.MaxSpansPerTrace {
+		spans = append(spans, p.decide(tid)...)
+	}
+	p.lock.Unlock()
+	p.forward(spans)
+}
+
+func isLocalRoot(s sdktrace.ReadOnlySpan) bool {
+	return !s.Parent().IsValid() || s.Parent().IsRemote()
+}
+
+func (p *Processor) matches(s sdktrace.ReadOnlySpan) bool {
+	if p.conf.KeepErrors && s.Status().Code == codes.Error {
+		return true
+	}
+	if p.conf.LatencyThreshold > 0 &&
+		s.EndTime().Sub(s.StartTime()) >= p.conf.LatencyThreshold {
+		return true
+	}
+	for _, attr := range s.Attributes() {
+		if pattern, ok := p.conf.Attributes[attr.Key]; ok &&
+			pattern.MatchString(attr.Value.Emit()) {
+			return true
+		}
+	}
+	return false
+}
+
+// sampledByRatio follows TraceIDRatioBased, so that the decision is consistent
+// with the head sampler of the other services
+func (p *Processor) sampledByRatio(tid trace.TraceID) bool {
+	if p.conf.Ratio <= 0 {
+		return false
+	}
+	bound := uint64(p.conf.Ratio * (1 << 63))
+	return binary.BigEndian.Uint64(tid[8:16])>>1 < bound
+}
+
+// decide removes the trace from the buffer and records the decision, the spans
+// to keep are returned, the caller must hold the lock
+func (p *Processor) decide(tid trace.TraceID) []sdktrace.ReadOnlySpan {
+	buf, ok := p.traces[tid]
+	if !ok {
+		return nil
+	}
+	delete(p.traces, tid)
+	p.order.Remove(buf.elem)
+	keep := buf.matched || p.sampledByRatio(tid)
+	// The decisions are kept in a ring buffer of the same capacity as the
+	// trace buffer
+	if len(p.decisionOrder) < p.conf.MaxTraces {
+		p.decisionOrder = append(p.decisionOrder, tid)
+	} else {
+		delete(p.decisions, p.decisionOrder[p.decisionIdx])
+		p.decisionOrder[p.decisionIdx] = tid
+		p.decisionIdx = (p.decisionIdx + 1) % p.conf.MaxTraces
+	}
+	p.decisions[tid] = keep
+	if !keep {
+		return nil
+	}
+	return buf.spans
+}
+
+// decideExpired decides the traces buffered longer than the decision wait, or
+// all of them if force is true
+func (p *Processor) decideExpired(force bool) {
+	var spans []sdktrace.ReadOnlySpan
+	now := time.Now()
+	p.lock.Lock()
+	for p.order.Len() > 0 {
+		tid := p.order.Front().Value.(trace.TraceID)
+		if !force && now.Sub(p.traces[tid].firstSeen) < p.conf.DecisionWait {
+			break
+		}
+		spans = append(spans, p.decide(tid)...)
+	}
+	p.lock.Unlock()
+	p.forward(spans)
+}
+
+func (p *Processor) expire() {
+	defer close(p.done)
+	interval := p.conf.DecisionWait / 10
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.decideExpired(false)
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *Processor) forward(spans []sdktrace.ReadOnlySpan) {
+	for _, s := range spans {
+		for _, next := range p.next {
+			next.OnEnd(s)
+		}
+	}
+}
+
+// Shutdown decides all buffered traces and shuts down the downstream
+// processors
+func (p *Processor) Shutdown(ctx context.Context) error {
+	var err error
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+		p.decideExpired(true)
+		for _, next := range p.next {
+			err = errors.Join(err, next.Shutdown(ctx))
+		}
+	})
+	return err
+}
+
+// ForceFlush decides all buffered traces and flushes the downstream processors
+func (p *Processor) ForceFlush(ctx context.Context) error {
+	p.decideExpired(true)
+	var err error
+	for _, next := range p.next {
+		err = errors.Join(err, next.ForceFlush(ctx))
+	}
+	return err
+}
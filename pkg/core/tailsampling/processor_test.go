@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestProvider(t *testing.T, conf Config) (trace.Tracer, *tracetest.SpanRecorder, *Processor) {
+	recorder := tracetest.NewSpanRecorder()
+	processor := NewProcessor(conf, recorder)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return tp.Tracer("test"), recorder, processor
+}
+
+func spanNames(recorder *tracetest.SpanRecorder) map[string]bool {
+	names := make(map[string]bool)
+	for _, s := range recorder.Ended() {
+		names[s.Name()] = true
+	}
+	return names
+}
+
+func TestTailSamplingPolicies(t *testing.T) {
+	conf := DefaultConfig()
+	conf.LatencyThreshold = 50 * time.Millisecond
+	conf.Attributes[attribute.Key("http.route")] = regexpMust(t, "/pay.*")
+	tracer, recorder, _ := newTestProvider(t, conf)
+
+	ctx, root := tracer.Start(context.Background(), "error-root")
+	_, child := tracer.Start(ctx, "error-child")
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+	root.End()
+
+	_, root = tracer.Start(context.Background(), "slow-root")
+	time.Sleep(60 * time.Millisecond)
+	root.End()
+
+	_, root = tracer.Start(context.Background(), "pay-root",
+		trace.WithAttributes(attribute.String("http.route", "/pay/order")))
+	root.End()
+
+	ctx, root = tracer.Start(context.Background(), "normal-root",
+		trace.WithAttributes(attribute.String("http.route", "/repay")))
+	_, child = tracer.Start(ctx, "normal-child")
+	child.End()
+	root.End()
+
+	names := spanNames(recorder)
+	for _, name := range []string{"error-root", "error-child", "slow-root", "pay-root"} {
+		if !names[name] {
+			t.Errorf("span %s is expected to be kept", name)
+		}
+	}
+	for _, name := range []string{"normal-root", "normal-child"} {
+		if names[name] {
+			t.Errorf("span %s is expected to be dropped", name)
+		}
+	}
+}
+
+func TestTailSamplingLateSpan(t *testing.T) {
+	tracer, recorder, _ := newTestProvider(t, DefaultConfig())
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracer.Start(ctx, "late-child")
+	root.SetStatus(codes.Error, "failed")
+	root.End()
+	// The child ending after the decision follows the decision of the trace
+	child.End()
+	if names := spanNames(recorder); !names["root"] || !names["late-child"] {
+		t.Errorf("late span is expected to be kept with the trace, got %v", names)
+	}
+}
+
+func TestTailSamplingBoundedMemory(t *testing.T) {
+	conf := DefaultConfig()
+	conf.MaxTraces = 2
+	conf.MaxSpansPerTrace = 2
+	tracer, recorder, processor := newTestProvider(t, conf)
+
+	// The first trace is decided early when the third one arrives
+	ctx, root := tracer.Start(context.Background(), "evicted-root")
+	_, child := tracer.Start(ctx, "evicted-child")
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+	var others []trace.Span
+	for i := 0; i < 2; i++ {
+		ctx, other := tracer.Start(context.Background(), "other-root")
+		_, c := tracer.Start(ctx, "other-child")
+		c.End()
+		others = append(others, other)
+	}
+	if !spanNames(recorder)["evicted-child"] {
+		t.Errorf("evicted trace is expected to be decided early")
+	}
+	root.End()
+	if !spanNames(recorder)["evicted-root"] {
+		t.Errorf("span ending after eviction is expected to follow the decision")
+	}
+	for _, other := range others {
+		other.End()
+	}
+
+	// The trace is decided early when it has too many spans
+	ctx, root = tracer.Start(context.Background(), "big-root")
+	for i := 0; i < 3; i++ {
+		_, c := tracer.Start(ctx, "big-child")
+		c.End()
+	}
+	processor.lock.Lock()
+	buffered := len(processor.traces)
+	processor.lock.Unlock()
+	if buffered > conf.MaxTraces {
+		t.Errorf("expect at most %d traces buffered, got %d", conf.MaxTraces,
+			buffered)
+	}
+	root.End()
+}
+
+func TestTailSamplingDecisionWait(t *testing.T) {
+	conf := DefaultConfig()
+	conf.DecisionWait = 50 * time.Millisecond
+	tracer, recorder, _ := newTestProvider(t, conf)
+	ctx, root := tracer.Start(context.Background(), "root")
+	defer root.End()
+	_, child := tracer.Start(ctx, "child")
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+	deadline := time.Now().Add(5 * time.Second)
+	for !spanNames(recorder)["child"] {
+		if time.Now().After(deadline) {
+			t.Fatal("trace is expected to be decided after the decision wait")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTailSamplingRatio(t *testing.T) {
+	conf := DefaultConfig()
+	conf.Ratio = 1
+	tracer, recorder, _ := newTestProvider(t, conf)
+	_, root := tracer.Start(context.Background(), "root")
+	root.End()
+	if !spanNames(recorder)["root"] {
+		t.Errorf("trace is expected to be kept by the ratio")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvDecisionWait, "100")
+	t.Setenv(EnvMaxTraces, "-1")
+	t.Setenv(EnvKeepErrors, "false")
+	t.Setenv(EnvLatencyThreshold, "200")
+	t.Setenv(EnvAttributes, "http.route=/pay.*, rpc.method = Pay")
+	t.Setenv(EnvRatio, "0.1")
+	conf := ConfigFromEnv()
+	if conf.DecisionWait != 100*time.Millisecond ||
+		conf.MaxTraces != DefaultConfig().MaxTraces || conf.KeepErrors ||
+		conf.LatencyThreshold != 200*time.Millisecond || conf.Ratio != 0.1 {
+		t.Errorf("unexpected config %+v", conf)
+	}
+	if len(conf.Attributes) != 2 ||
+		!conf.Attributes["rpc.method"].MatchString("Pay") ||
+		conf.Attributes["http.route"].MatchString("/repay") {
+		t.Errorf("unexpected attribute predicates %v", conf.Attributes)
+	}
+	if _, err := ParseAttributes("http.route"); err == nil {
+		t.Errorf("expect error for the predicate without value pattern")
+	}
+}
+
+func regexpMust(t *testing.T, pattern string) *regexp.Regexp {
+	attrs, err := ParseAttributes("key=" + pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return attrs["key"]
+}
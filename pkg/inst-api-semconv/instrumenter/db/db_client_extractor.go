@@ -89,7 +89,7 @@ func (d *DbClientAttrsExtractor[REQUEST, RESPONSE, GETTER]) OnEnd(attrs []attrib
 	attrs, context = d.Base.OnEnd(attrs, context, request, response, err)
 	attrs = append(attrs, attribute.KeyValue{
 		Key:   semconv.DBQueryTextKey,
-		Value: attribute.StringValue(d.Base.Getter.GetStatement(request)),
+		Value: attribute.StringValue(d.sanitizedStatement(request)),
 	}, attribute.KeyValue{
 		Key:   semconv.DBOperationNameKey,
 		Value: attribute.StringValue(d.Base.Getter.GetOperation(request)),
@@ -100,6 +100,11 @@ func (d *DbClientAttrsExtractor[REQUEST, RESPONSE, GETTER]) OnEnd(attrs []attrib
 		Key:   semconv.DBCollectionNameKey,
 		Value: attribute.StringValue(d.Base.Getter.GetCollection(request)),
 	})
+	if portGetter, ok := any(d.Base.Getter).(DbServerPortGetter[REQUEST]); ok {
+		if port := portGetter.GetServerPort(request); port > 0 {
+			attrs = append(attrs, attribute.KeyValue{Key: semconv.ServerPortKey, Value: attribute.IntValue(port)})
+		}
+	}
 	batchSize := d.Base.Getter.GetBatchSize(request)
 	if batchSize > 0 {
 		attrs = append(attrs, attribute.KeyValue{Key: semconv.DBOperationBatchSizeKey, Value: attribute.IntValue(batchSize)})
@@ -122,9 +127,15 @@ func (d *DbClientAttrsExtractor[REQUEST, RESPONSE, GETTER]) OnEnd(attrs []attrib
 	return attrs, context
 }
 
+func (d *DbClientAttrsExtractor[REQUEST, RESPONSE, GETTER]) sanitizedStatement(request REQUEST) string {
+	if getter, ok := any(d.Base.Getter).(DbSanitizedStatementGetter[REQUEST]); ok {
+		return getter.GetSanitizedStatement(request)
+	}
+	return SanitizeStatement(d.Base.Getter.GetSystem(request), d.Base.Getter.GetStatement(request))
+}
+
 func (d *DbClientAttrsExtractor[REQUEST, RESPONSE, GETTER]) GetSpanKey() attribute.Key {
 	return utils.DB_CLIENT_KEY
 }
 
-// TODO: sanitize sql
 // TODO: batch sql
@@ -134,3 +134,65 @@ func TestDbClientExtractorWithFilter(t *testing.T) {
 		panic("attribute should be test")
 	}
 }
+
+type sqlAttrsGetter struct {
+	mongoAttrsGetter
+}
+
+func (s sqlAttrsGetter) GetSystem(request testRequest) string {
+	return "mysql"
+}
+
+func (s sqlAttrsGetter) GetStatement(request testRequest) string {
+	return "select * from users where id = " + request.Target
+}
+
+type cachedSqlAttrsGetter struct {
+	sqlAttrsGetter
+}
+
+func (c cachedSqlAttrsGetter) GetSanitizedStatement(request testRequest) string {
+	return "cached"
+}
+
+func TestDbClientExtractorSanitizeStatement(t *testing.T) {
+	dbExtractor := DbClientAttrsExtractor[testRequest, testResponse, sqlAttrsGetter]{}
+	attrs, _ := dbExtractor.OnEnd(nil, context.Background(), testRequest{Target: "10"}, testResponse{}, nil)
+	if attrs[1].Key != semconv.DBQueryTextKey || attrs[1].Value.AsString() != "select * from users where id = ?" {
+		t.Fatalf("db statement should be sanitized, got %v", attrs[1].Value.AsString())
+	}
+	cachedExtractor := DbClientAttrsExtractor[testRequest, testResponse, cachedSqlAttrsGetter]{}
+	attrs, _ = cachedExtractor.OnEnd(nil, context.Background(), testRequest{Target: "10"}, testResponse{}, nil)
+	if attrs[1].Value.AsString() != "cached" {
+		t.Fatalf("db statement should be the cached one, got %v", attrs[1].Value.AsString())
+	}
+}
+
+type portSqlAttrsGetter struct {
+	sqlAttrsGetter
+}
+
+func (p portSqlAttrsGetter) GetServerPort(request testRequest) int {
+	return 3306
+}
+
+func TestDbClientExtractorServerPort(t *testing.T) {
+	dbExtractor := DbClientAttrsExtractor[testRequest, testResponse, sqlAttrsGetter]{}
+	attrs, _ := dbExtractor.OnEnd(nil, context.Background(), testRequest{}, testResponse{}, nil)
+	for _, attr := range attrs {
+		if attr.Key == semconv.ServerPortKey {
+			t.Fatalf("server port should be absent, got %v", attr.Value.AsInt64())
+		}
+	}
+	portExtractor := DbClientAttrsExtractor[testRequest, testResponse, portSqlAttrsGetter]{}
+	attrs, _ = portExtractor.OnEnd(nil, context.Background(), testRequest{}, testResponse{}, nil)
+	found := false
+	for _, attr := range attrs {
+		if attr.Key == semconv.ServerPortKey && attr.Value.AsInt64() == 3306 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("server port should be 3306, got %v", attrs)
+	}
+}
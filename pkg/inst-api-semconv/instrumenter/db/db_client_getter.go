@@ -29,6 +29,19 @@ type DbClientAttrsGetter[REQUEST any] interface {
 	GetParameters(REQUEST) []any
 }
 
+// DbSanitizedStatementGetter is optionally implemented by the getters caching
+// the sanitized statements, the statements of the other getters are sanitized
+// by SanitizeStatement on each request
+type DbSanitizedStatementGetter[REQUEST any] interface {
+	GetSanitizedStatement(REQUEST) string
+}
+
+// DbServerPortGetter is optionally implemented by the getters knowing the
+// server port apart from the server address
+type DbServerPortGetter[REQUEST any] interface {
+	GetServerPort(REQUEST) int
+}
+
 type SqlClientAttributesGetter[REQUEST any] interface {
 	DbClientCommonAttrsGetter[REQUEST]
 	GetRawStatement(REQUEST) string
@@ -0,0 +1,247 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// SQL Summary
+//
+// The SQL statements are summarized into the operations and the primary tables
+// by a lightweight lexer rather than a full parser, which is good enough for
+// the low-cardinality span names like "SELECT orders", and tolerates the
+// dialects and the placeholders a full parser may reject.
+
+// SqlSummary is the operation of a SQL statement and the primary table it
+// operates on, or the stored procedure it calls
+type SqlSummary struct {
+	Operation  string
+	Collection string
+}
+
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlQuoted
+	sqlLiteral
+	sqlSymbol
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+}
+
+// The operations summarized without the collections, the other statements
+// starting with unknown words are not summarized at all
+var sqlOperations = map[string]bool{
+	"BEGIN": true, "START": true, "COMMIT": true, "ROLLBACK": true,
+	"SAVEPOINT": true, "RELEASE": true, "SET": true, "SHOW": true,
+	"USE": true, "GRANT": true, "REVOKE": true, "DESCRIBE": true,
+	"DESC": true, "EXPLAIN": true, "LOCK": true, "UNLOCK": true,
+	"ANALYZE": true, "VACUUM": true, "PREPARE": true, "DEALLOCATE": true,
+}
+
+func tokenizeSql(sql string) []sqlToken {
+	var tokens []sqlToken
+	n := len(sql)
+	for i := 0; i < n; {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && i+1 < n && sql[i+1] == '-':
+			j := strings.IndexByte(sql[i:], '\n')
+			if j < 0 {
+				return tokens
+			}
+			i += j + 1
+		case c == '/' && i+1 < n && sql[i+1] == '*':
+			j := strings.Index(sql[i+2:], "*/")
+			if j < 0 {
+				return tokens
+			}
+			i += j + 4
+		case c == '\'':
+			j := skipQuoted(sql, i, c, true)
+			tokens = append(tokens, sqlToken{sqlLiteral, sql[i:j]})
+			i = j
+		case c == '"' || c == '`':
+			j := skipQuoted(sql, i, c, false)
+			tokens = append(tokens, sqlToken{sqlQuoted, strings.Trim(sql[i:j], string(c))})
+			i = j
+		case c == '[':
+			j := strings.IndexByte(sql[i:], ']')
+			if j < 0 {
+				j = n - i - 1
+			}
+			tokens = append(tokens, sqlToken{sqlQuoted, sql[i+1 : i+j]})
+			i += j + 1
+		case isDigit(c):
+			j := skipNumber(sql, i)
+			tokens = append(tokens, sqlToken{sqlLiteral, sql[i:j]})
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < n && isIdentPart(sql[j]) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{sqlWord, sql[i:j]})
+			i = j
+		default:
+			tokens = append(tokens, sqlToken{sqlSymbol, sql[i : i+1]})
+			i++
+		}
+	}
+	return tokens
+}
+
+func (t sqlToken) is(keywords ...string) bool {
+	if t.kind != sqlWord {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(t.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlParser walks through the tokens of a SQL statement
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+}
+
+func (p *sqlParser) peek() sqlToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return sqlToken{kind: sqlSymbol}
+}
+
+// skip skips the optional keywords
+func (p *sqlParser) skip(keywords ...string) {
+	for p.pos < len(p.tokens) && p.tokens[p.pos].is(keywords...) {
+		p.pos++
+	}
+}
+
+// seek moves to the token right after the first keyword out of the
+// parentheses, and reports whether the keyword is found
+func (p *sqlParser) seek(keywords ...string) bool {
+	depth := 0
+	for ; p.pos < len(p.tokens); p.pos++ {
+		token := p.tokens[p.pos]
+		switch {
+		case token.text == "(" && token.kind == sqlSymbol:
+			depth++
+		case token.text == ")" && token.kind == sqlSymbol:
+			depth--
+		case depth <= 0 && token.is(keywords...):
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+// name reads the possibly qualified name, e.g. "db"."orders", nothing if the
+// next token is not a name, e.g. a subquery
+func (p *sqlParser) name() string {
+	var parts []string
+	for {
+		token := p.peek()
+		if token.kind != sqlWord && token.kind != sqlQuoted {
+			break
+		}
+		parts = append(parts, token.text)
+		p.pos++
+		if next := p.peek(); next.kind != sqlSymbol || next.text != "." {
+			break
+		}
+		p.pos++
+	}
+	return strings.Join(parts, ".")
+}
+
+// SummarizeSql summarizes the SQL statement into the upper-cased operation,
+// e.g. SELECT, and the primary table, i.e. the first one the statement reads
+// from or writes to, or the stored procedure of CALL and EXEC. Nothing is
+// returned if the statement doesn't start with a known keyword
+func SummarizeSql(sql string) SqlSummary {
+	p := &sqlParser{tokens: tokenizeSql(sql)}
+	for p.peek().kind == sqlSymbol && p.peek().text == "(" {
+		p.pos++
+	}
+	first := p.peek()
+	if first.kind != sqlWord {
+		return SqlSummary{}
+	}
+	p.pos++
+	operation := strings.ToUpper(first.text)
+	if operation == "WITH" {
+		// The main statement follows the common table expressions
+		if !p.seek("SELECT", "INSERT", "UPDATE", "DELETE", "MERGE") {
+			return SqlSummary{Operation: operation}
+		}
+		operation = strings.ToUpper(p.tokens[p.pos-1].text)
+	}
+	summary := SqlSummary{Operation: operation}
+	switch operation {
+	case "SELECT":
+		if p.seek("FROM") {
+			p.skip("ONLY")
+			summary.Collection = p.name()
+		}
+	case "DELETE":
+		if p.seek("FROM") {
+			p.skip("ONLY")
+			summary.Collection = p.name()
+		}
+	case "INSERT", "REPLACE", "UPSERT", "MERGE":
+		p.skip("LOW_PRIORITY", "DELAYED", "HIGH_PRIORITY", "IGNORE", "OR",
+			"REPLACE", "ROLLBACK", "ABORT", "FAIL", "INTO")
+		summary.Collection = p.name()
+	case "UPDATE":
+		p.skip("LOW_PRIORITY", "IGNORE", "ONLY", "OR", "REPLACE", "ROLLBACK",
+			"ABORT", "FAIL")
+		summary.Collection = p.name()
+	case "CALL", "EXEC", "EXECUTE":
+		summary.Collection = p.name()
+	case "CREATE", "DROP", "ALTER":
+		p.skip("OR", "REPLACE", "TEMPORARY", "TEMP", "GLOBAL", "LOCAL",
+			"UNLOGGED", "MATERIALIZED")
+		// Only the names of the tables and the views are the collections
+		if !p.peek().is("TABLE", "VIEW") {
+			break
+		}
+		p.pos++
+		p.skip("IF", "NOT", "EXISTS")
+		summary.Collection = p.name()
+	case "TRUNCATE":
+		p.skip("TABLE", "ONLY")
+		summary.Collection = p.name()
+	default:
+		if !sqlOperations[operation] {
+			return SqlSummary{}
+		}
+	}
+	return summary
+}
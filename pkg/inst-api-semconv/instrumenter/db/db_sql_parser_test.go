@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+)
+
+func TestSummarizeSql(t *testing.T) {
+	for _, c := range []struct {
+		sql        string
+		operation  string
+		collection string
+	}{
+		{"select name from users where id = ?", "SELECT", "users"},
+		{"SELECT o.id FROM `shop`.`orders` o JOIN users u ON o.uid = u.id", "SELECT", "shop.orders"},
+		{"SELECT extract(year FROM d), (SELECT 1 FROM t) FROM \"orders\"", "SELECT", "orders"},
+		{"SELECT * FROM (SELECT * FROM orders) AS o", "SELECT", ""},
+		{"SELECT VERSION()", "SELECT", ""},
+		{"/* hint */ (SELECT * FROM [dbo].[orders])", "SELECT", "dbo.orders"},
+		{"WITH o AS (SELECT * FROM orders) DELETE FROM items WHERE oid IN (SELECT id FROM o)", "DELETE", "items"},
+		{"INSERT INTO users (id, name) VALUES (?, ?)", "INSERT", "users"},
+		{"insert ignore into users values (1)", "INSERT", "users"},
+		{"INSERT OR REPLACE INTO users VALUES (1)", "INSERT", "users"},
+		{"REPLACE INTO users VALUES (1)", "REPLACE", "users"},
+		{"MERGE INTO orders USING items ON 1 = 1", "MERGE", "orders"},
+		{"UPDATE LOW_PRIORITY users SET name = ? WHERE id = ?", "UPDATE", "users"},
+		{"DELETE FROM users WHERE id = ?", "DELETE", "users"},
+		{"CALL get_orders(?)", "CALL", "get_orders"},
+		{"EXEC dbo.get_orders @id = 1", "EXEC", "dbo.get_orders"},
+		{"CREATE TABLE IF NOT EXISTS users (id char(255))", "CREATE", "users"},
+		{"create or replace view v_orders as select * from orders", "CREATE", "v_orders"},
+		{"DROP TABLE IF EXISTS users", "DROP", "users"},
+		{"CREATE INDEX idx ON users (name)", "CREATE", ""},
+		{"TRUNCATE TABLE users", "TRUNCATE", "users"},
+		{"START TRANSACTION", "START", ""},
+		{"commit", "COMMIT", ""},
+		{"ping", "", ""},
+		{"", "", ""},
+		{"-- comment only", "", ""},
+	} {
+		summary := SummarizeSql(c.sql)
+		if summary.Operation != c.operation || summary.Collection != c.collection {
+			t.Errorf("%q: expect %s %s, got %s %s", c.sql, c.operation, c.collection,
+				summary.Operation, summary.Collection)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// -----------------------------------------------------------------------------
+// Statement Sanitization
+//
+// The statements recorded as db.query.text may carry sensitive data, e.g. the
+// user names and the phone numbers in the WHERE clauses. The sanitizers replace
+// the literals of the statements with "?" while keeping their shapes, so that
+// the statements are still useful to tell the slow queries apart. The SQL
+// statements (including CQL) are sanitized by a lexer, the Redis commands keep
+// the command names and the keys only, and the JSON documents, e.g. MongoDB
+// filters and Elasticsearch bodies, keep the field names only.
+
+const EnvStatementSanitizerEnabled = "OTEL_INSTRUMENTATION_DB_STATEMENT_SANITIZER_ENABLED"
+
+// The sanitization is enabled by default, it can be turned off for all the
+// database systems, or for a single one by e.g.
+// OTEL_INSTRUMENTATION_DB_STATEMENT_SANITIZER_REDIS_ENABLED=false
+var statementSanitizerEnabled = func() bool {
+	val, err := strconv.ParseBool(os.Getenv(EnvStatementSanitizerEnabled))
+	if err != nil {
+		return true
+	}
+	return val
+}()
+
+// The per-system switches are looked up on demand since the systems are not
+// known in advance, and cached afterwards
+var systemSanitizerEnabled sync.Map
+
+var inListPattern = regexp.MustCompile(`(?i)\bIN(\s*)\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+
+func systemSanitizerEnv(system string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(system))
+	return "OTEL_INSTRUMENTATION_DB_STATEMENT_SANITIZER_" + name + "_ENABLED"
+}
+
+func sanitizerEnabled(system string) bool {
+	if enabled, ok := systemSanitizerEnabled.Load(system); ok {
+		return enabled.(bool)
+	}
+	enabled := statementSanitizerEnabled
+	if system != "" {
+		if val, err := strconv.ParseBool(os.Getenv(systemSanitizerEnv(system))); err == nil {
+			enabled = val
+		}
+	}
+	systemSanitizerEnabled.Store(system, enabled)
+	return enabled
+}
+
+// SanitizeStatement sanitizes the statement by the sanitizer of the database
+// system, the statement is returned as is if the sanitization is disabled
+func SanitizeStatement(system, statement string) string {
+	if statement == "" || !sanitizerEnabled(system) {
+		return statement
+	}
+	switch strings.ToLower(system) {
+	case "redis", "rueidis":
+		return sanitizeRedisCommand(statement)
+	case "mongodb", "elasticsearch":
+		return sanitizeJson(statement)
+	case "mysql", "mariadb", "tidb":
+		// Double-quoted strings are string literals in MySQL by default
+		return sanitizeSql(statement, true)
+	default:
+		return sanitizeSql(statement, false)
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isIdentStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '$'
+}
+
+// skipQuoted returns the end of the quoted token starting at i, the quote is
+// escaped by doubling it, or by a backslash if escapable, an unterminated
+// token ends with the statement
+func skipQuoted(s string, i int, quote byte, escapable bool) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			if escapable {
+				j++
+			}
+		case quote:
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// skipNumber returns the end of the numeric literal starting at i, i.e. the
+// decimals, the hexadecimals and the exponents
+func skipNumber(s string, i int) int {
+	j := i
+	if s[j] == '0' && j+2 < len(s) && (s[j+1] == 'x' || s[j+1] == 'X') && isHexDigit(s[j+2]) {
+		j += 2
+		for j < len(s) && isHexDigit(s[j]) {
+			j++
+		}
+		return j
+	}
+	for j < len(s) && isDigit(s[j]) {
+		j++
+	}
+	if j < len(s) && s[j] == '.' {
+		j++
+		for j < len(s) && isDigit(s[j]) {
+			j++
+		}
+	}
+	if j+1 < len(s) && (s[j] == 'e' || s[j] == 'E') {
+		k := j + 1
+		if s[k] == '+' || s[k] == '-' {
+			k++
+		}
+		if k < len(s) && isDigit(s[k]) {
+			for k < len(s) && isDigit(s[k]) {
+				k++
+			}
+			j = k
+		}
+	}
+	return j
+}
+
+// sanitizeSql replaces the string, numeric, hexadecimal and dollar-quoted
+// literals with "?", and collapses the IN lists of literals into "IN (?)". The
+// identifiers, the keywords, the placeholders and the comments are kept as
+// they are
+func sanitizeSql(sql string, doubleQuotedStrings bool) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+	n := len(sql)
+	for i := 0; i < n; {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			i = skipQuoted(sql, i, c, true)
+			b.WriteByte('?')
+		case c == '"' && doubleQuotedStrings:
+			i = skipQuoted(sql, i, c, true)
+			b.WriteByte('?')
+		case c == '"' || c == '`':
+			// Quoted identifiers
+			j := skipQuoted(sql, i, c, false)
+			b.WriteString(sql[i:j])
+			i = j
+		case c == '-' && i+1 < n && sql[i+1] == '-':
+			j := strings.IndexByte(sql[i:], '\n')
+			if j < 0 {
+				j = n - i
+			}
+			b.WriteString(sql[i : i+j])
+			i += j
+		case c == '/' && i+1 < n && sql[i+1] == '*':
+			j := strings.Index(sql[i+2:], "*/")
+			if j < 0 {
+				j = n
+			} else {
+				j += i + 4
+			}
+			b.WriteString(sql[i:j])
+			i = j
+		case c == '$':
+			j := i + 1
+			for j < n && isDigit(sql[j]) {
+				j++
+			}
+			if j > i+1 {
+				// Positional placeholders, e.g. $1
+				b.WriteString(sql[i:j])
+				i = j
+				break
+			}
+			for j < n && isIdentPart(sql[j]) && sql[j] != '$' {
+				j++
+			}
+			if j < n && sql[j] == '$' {
+				// Dollar-quoted strings, e.g. $$text$$ or $tag$text$tag$
+				tag := sql[i : j+1]
+				end := strings.Index(sql[j+1:], tag)
+				if end < 0 {
+					i = n
+				} else {
+					i = j + 1 + end + len(tag)
+				}
+				b.WriteByte('?')
+				break
+			}
+			b.WriteByte(c)
+			i++
+		case isDigit(c) || (c == '.' && i+1 < n && isDigit(sql[i+1])):
+			j := skipNumber(sql, i)
+			if j < n && isIdentPart(sql[j]) {
+				// Identifiers starting with digits
+				for j < n && isIdentPart(sql[j]) {
+					j++
+				}
+				b.WriteString(sql[i:j])
+			} else {
+				b.WriteByte('?')
+			}
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < n && isIdentPart(sql[j]) {
+				j++
+			}
+			if j-i == 1 && j < n && sql[j] == '\'' && strings.IndexByte("xXbBnNeE", c) >= 0 {
+				// Prefixed strings, e.g. X'0F', N'text' and E'text'
+				i = skipQuoted(sql, j, '\'', true)
+				b.WriteByte('?')
+				break
+			}
+			b.WriteString(sql[i:j])
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return inListPattern.ReplaceAllStringFunc(b.String(), func(in string) string {
+		return in[:strings.IndexByte(in, '(')] + "(?)"
+	})
+}
+
+// sanitizeRedisCommand keeps the command name and the first argument, which is
+// the key of most commands or the subcommand, e.g. CLIENT SETNAME, the other
+// arguments are replaced with "?". All the arguments of the commands carrying
+// credentials are replaced. The commands of a transaction or a pipeline, e.g.
+// "EXEC INCR foo; INCR bar;", are sanitized one by one
+func sanitizeRedisCommand(cmd string) string {
+	segments := strings.Split(cmd, ";")
+	for i, segment := range segments {
+		segments[i] = sanitizeRedisSegment(segment)
+	}
+	return strings.Join(segments, ";")
+}
+
+func sanitizeRedisSegment(segment string) string {
+	fields := strings.Fields(segment)
+	prefix := 0
+	if len(fields) > 1 && strings.EqualFold(fields[0], "exec") {
+		prefix = 1
+	}
+	if len(fields) == prefix {
+		return segment
+	}
+	keep := prefix + 2
+	switch strings.ToLower(fields[prefix]) {
+	case "auth", "hello", "migrate":
+		keep = prefix + 1
+	}
+	if len(fields) <= keep {
+		return segment
+	}
+	for i := keep; i < len(fields); i++ {
+		fields[i] = "?"
+	}
+	leading := len(segment) - len(strings.TrimLeft(segment, " \t\r\n"))
+	return segment[:leading] + strings.Join(fields, " ")
+}
+
+// sanitizeJson replaces the values of the JSON document in the statement with
+// "?" and keeps the field names, the text before the document, e.g. the
+// command name or the request path, is kept as is
+func sanitizeJson(s string) string {
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(s[:start])
+	// The enclosing containers, '{' or '['
+	var stack []byte
+	expectKey := false
+	for i := start; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '{' || c == '[':
+			stack = append(stack, c)
+			expectKey = c == '{'
+			b.WriteByte(c)
+			i++
+		case c == '}' || c == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			expectKey = false
+			b.WriteByte(c)
+			i++
+		case c == ',':
+			expectKey = len(stack) > 0 && stack[len(stack)-1] == '{'
+			b.WriteByte(c)
+			i++
+		case c == ':':
+			expectKey = false
+			b.WriteByte(c)
+			i++
+		case c == '"' || c == '\'':
+			j := skipQuoted(s, i, c, true)
+			if expectKey {
+				b.WriteString(s[i:j])
+			} else {
+				b.WriteString(`"?"`)
+			}
+			i = j
+		case c == '-' || isIdentPart(c):
+			j := i + 1
+			for j < len(s) && (isIdentPart(s[j]) || strings.IndexByte(".+-", s[j]) >= 0) {
+				j++
+			}
+			if expectKey {
+				// Unquoted field names, e.g. the MongoDB shell syntax
+				b.WriteString(s[i:j])
+			} else {
+				b.WriteString(`"?"`)
+			}
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"testing"
+)
+
+func TestSanitizeSql(t *testing.T) {
+	for _, c := range []struct {
+		sql      string
+		expected string
+	}{
+		{"SELECT * FROM users WHERE id = 1", "SELECT * FROM users WHERE id = ?"},
+		{"select name from users where name = 'O''Brien' and age > -1.5e3",
+			"select name from users where name = ? and age > -?"},
+		{`UPDATE "users" SET "name" = NULL, "age" = 10 WHERE "user"."id" = '1'`,
+			`UPDATE "users" SET "name" = NULL, "age" = ? WHERE "user"."id" = ?`},
+		{"INSERT INTO t1 (id, data) VALUES (0x1F, X'0F'), (2, E'a\\'b')",
+			"INSERT INTO t1 (id, data) VALUES (?, ?), (?, ?)"},
+		{"SELECT * FROM t WHERE id IN (1, 2, 3) AND name in ( 'a','b' )",
+			"SELECT * FROM t WHERE id IN (?) AND name in (?)"},
+		{"SELECT * FROM t WHERE id IN (SELECT id FROM s WHERE x = 1)",
+			"SELECT * FROM t WHERE id IN (SELECT id FROM s WHERE x = ?)"},
+		{"select id from users where id = $1 and name = :name and age = ?",
+			"select id from users where id = $1 and name = :name and age = ?"},
+		{"SELECT $$secret$$, $tag$it's$tag$", "SELECT ?, ?"},
+		{"SELECT 1 -- comment 2\n/* hint 3 */ FROM `t2`", "SELECT ? -- comment 2\n/* hint 3 */ FROM `t2`"},
+		{"SELECT * FROM 1table", "SELECT * FROM 1table"},
+		{"SELECT 'unterminated", "SELECT ?"},
+		{"CREATE KEYSPACE k WITH REPLICATION = { 'class' : 'SimpleStrategy', 'replication_factor' : '1' };",
+			"CREATE KEYSPACE k WITH REPLICATION = { ? : ?, ? : ? };"},
+	} {
+		if got := sanitizeSql(c.sql, false); got != c.expected {
+			t.Errorf("%q: expect %q, got %q", c.sql, c.expected, got)
+		}
+	}
+	if got := sanitizeSql(`SELECT * FROM t WHERE name = "alice"`, true); got != "SELECT * FROM t WHERE name = ?" {
+		t.Errorf("double-quoted strings are expected to be sanitized, got %q", got)
+	}
+}
+
+func TestSanitizeRedisCommand(t *testing.T) {
+	for _, c := range []struct {
+		cmd      string
+		expected string
+	}{
+		{"get a", "get a"},
+		{"set a b ex 5", "set a ? ? ?"},
+		{"client setname myclient", "client setname ?"},
+		{"AUTH user password", "AUTH ? ?"},
+		{"ping", "ping"},
+		{" ", " "},
+		{"EXEC INCR foo; INCR bar;", "EXEC INCR foo; INCR bar;"},
+		{"EXEC SET a b; SET c d", "EXEC SET a ?; SET c ?"},
+	} {
+		if got := sanitizeRedisCommand(c.cmd); got != c.expected {
+			t.Errorf("%q: expect %q, got %q", c.cmd, c.expected, got)
+		}
+	}
+}
+
+func TestSanitizeJson(t *testing.T) {
+	for _, c := range []struct {
+		doc      string
+		expected string
+	}{
+		{"find", "find"},
+		{`{"name": "alice", "age": {"$gt": 18}, "tags": ["a", 1, true], "x": null}`,
+			`{"name": "?", "age": {"$gt": "?"}, "tags": ["?", "?", "?"], "x": "?"}`},
+		{`POST /idx/_search {"query":{"term":{"user.id":"kimchy"}}}`,
+			`POST /idx/_search {"query":{"term":{"user.id":"?"}}}`},
+		{`{name: 'alice', age: -1}`, `{name: "?", age: "?"}`},
+	} {
+		if got := sanitizeJson(c.doc); got != c.expected {
+			t.Errorf("%q: expect %q, got %q", c.doc, c.expected, got)
+		}
+	}
+}
+
+func TestSanitizeStatement(t *testing.T) {
+	defer func(enabled bool) {
+		statementSanitizerEnabled = enabled
+		systemSanitizerEnabled.Clear()
+	}(statementSanitizerEnabled)
+	systemSanitizerEnabled.Clear()
+	t.Setenv(systemSanitizerEnv("redis"), "false")
+	if got := SanitizeStatement("redis", "set a b"); got != "set a b" {
+		t.Errorf("sanitization of redis is expected to be disabled, got %q", got)
+	}
+	if got := SanitizeStatement("mysql", `select "a"`); got != "select ?" {
+		t.Errorf("unexpected sanitized mysql statement %q", got)
+	}
+	if got := SanitizeStatement("mongodb", `{"a":1}`); got != `{"a":"?"}` {
+		t.Errorf("unexpected sanitized mongodb statement %q", got)
+	}
+
+	statementSanitizerEnabled = false
+	systemSanitizerEnabled.Clear()
+	t.Setenv(systemSanitizerEnv("postgresql"), "true")
+	if got := SanitizeStatement("postgresql", "select 1"); got != "select ?" {
+		t.Errorf("sanitization of postgresql is expected to be enabled, got %q", got)
+	}
+	if got := SanitizeStatement("cassandra", "select 1"); got != "select 1" {
+		t.Errorf("sanitization is expected to be disabled, got %q", got)
+	}
+}
+
+func TestSystemSanitizerEnv(t *testing.T) {
+	if env := systemSanitizerEnv("sql-server"); env != "OTEL_INSTRUMENTATION_DB_STATEMENT_SANITIZER_SQL_SERVER_ENABLED" {
+		t.Errorf("unexpected env %s", env)
+	}
+}
@@ -82,6 +82,8 @@ func (h *HttpClientAttrsExtractor[REQUEST, RESPONSE, GETTER1, GETTER2]) OnStart(
 		Key:   semconv.ServerPortKey,
 		Value: attribute.IntValue(h.Base.HttpGetter.GetServerPort(request)),
 	})
+	attributes = captureRequestHeaders[REQUEST, RESPONSE](attributes,
+		capturedClientRequestHeaders, h.Base.HttpGetter, request)
 	if h.Base.AttributesFilter != nil {
 		attributes = h.Base.AttributesFilter(attributes)
 	}
@@ -91,6 +93,8 @@ func (h *HttpClientAttrsExtractor[REQUEST, RESPONSE, GETTER1, GETTER2]) OnStart(
 func (h *HttpClientAttrsExtractor[REQUEST, RESPONSE, GETTER1, GETTER2]) OnEnd(attributes []attribute.KeyValue, context context.Context, request REQUEST, response RESPONSE, err error) ([]attribute.KeyValue, context.Context) {
 	attributes, context = h.Base.OnEnd(attributes, context, request, response, err)
 	attributes, context = h.NetworkExtractor.OnEnd(attributes, context, request, response, err)
+	attributes = captureResponseHeaders(attributes, capturedClientResponseHeaders,
+		h.Base.HttpGetter, request, response)
 	if h.Base.AttributesFilter != nil {
 		attributes = h.Base.AttributesFilter(attributes)
 	}
@@ -121,6 +125,8 @@ func (h *HttpServerAttrsExtractor[REQUEST, RESPONSE, GETTER1, GETTER2, GETTER3])
 		Key:   semconv.UserAgentOriginalKey,
 		Value: attribute.StringValue(firstUserAgent),
 	})
+	attributes = captureRequestHeaders[REQUEST, RESPONSE](attributes,
+		capturedServerRequestHeaders, h.Base.HttpGetter, request)
 	if h.Base.AttributesFilter != nil {
 		attributes = h.Base.AttributesFilter(attributes)
 	}
@@ -143,6 +149,8 @@ func (h *HttpServerAttrsExtractor[REQUEST, RESPONSE, GETTER1, GETTER2, GETTER3])
 			Value: attribute.StringValue(route),
 		})
 	}
+	attributes = captureResponseHeaders(attributes, capturedServerResponseHeaders,
+		h.Base.HttpGetter, request, response)
 	if h.Base.AttributesFilter != nil {
 		attributes = h.Base.AttributesFilter(attributes)
 	}
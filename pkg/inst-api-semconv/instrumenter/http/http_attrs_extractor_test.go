@@ -443,3 +443,39 @@ func TestNonRecordingSpan(t *testing.T) {
 		t.Fatalf("wrong network peer port")
 	}
 }
+
+func TestHttpExtractorCaptureHeaders(t *testing.T) {
+	capturedServerRequestHeaders = []string{"x-request-id"}
+	capturedServerResponseHeaders = []string{"content-type"}
+	capturedClientRequestHeaders = []string{"x-client"}
+	capturedClientResponseHeaders = []string{"x-server"}
+	defer func() {
+		capturedServerRequestHeaders = nil
+		capturedServerResponseHeaders = nil
+		capturedClientRequestHeaders = nil
+		capturedClientResponseHeaders = nil
+	}()
+	httpServerExtractor := HttpServerAttrsExtractor[testRequest, testResponse, httpServerAttrsGetter, networkAttrsGetter, urlAttrsGetter]{}
+	attrs, _ := httpServerExtractor.OnStart(nil, context.Background(), testRequest{})
+	expectAttr(t, attrs, "http.request.header.x-request-id", "request-header")
+	attrs, _ = httpServerExtractor.OnEnd(nil, context.Background(), testRequest{}, testResponse{}, nil)
+	expectAttr(t, attrs, "http.response.header.content-type", "response-header")
+
+	httpClientExtractor := HttpClientAttrsExtractor[testRequest, testResponse, httpClientAttrsGetter, networkAttrsGetter]{}
+	attrs, _ = httpClientExtractor.OnStart(nil, context.Background(), testRequest{})
+	expectAttr(t, attrs, "http.request.header.x-client", "request-header")
+	attrs, _ = httpClientExtractor.OnEnd(nil, context.Background(), testRequest{}, testResponse{}, nil)
+	expectAttr(t, attrs, "http.response.header.x-server", "response-header")
+}
+
+func expectAttr(t *testing.T, attrs []attribute.KeyValue, key string, value string) {
+	for _, attr := range attrs {
+		if string(attr.Key) == key {
+			if got := attr.Value.AsStringSlice(); len(got) != 1 || got[0] != value {
+				t.Fatalf("%s should be [%s], got %v", key, value, got)
+			}
+			return
+		}
+	}
+	t.Fatalf("%s should be captured", key)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// The comma-separated names of the headers captured as the
+// http.request.header.<name> and http.response.header.<name> attributes
+const (
+	EnvCaptureHeadersServerRequest  = "OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_REQUEST"
+	EnvCaptureHeadersServerResponse = "OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_RESPONSE"
+	EnvCaptureHeadersClientRequest  = "OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_CLIENT_REQUEST"
+	EnvCaptureHeadersClientResponse = "OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_CLIENT_RESPONSE"
+)
+
+const (
+	requestHeaderPrefix  = "http.request.header."
+	responseHeaderPrefix = "http.response.header."
+)
+
+var (
+	capturedServerRequestHeaders  = utils.CapturedHeadersFromEnv(EnvCaptureHeadersServerRequest)
+	capturedServerResponseHeaders = utils.CapturedHeadersFromEnv(EnvCaptureHeadersServerResponse)
+	capturedClientRequestHeaders  = utils.CapturedHeadersFromEnv(EnvCaptureHeadersClientRequest)
+	capturedClientResponseHeaders = utils.CapturedHeadersFromEnv(EnvCaptureHeadersClientResponse)
+)
+
+func captureRequestHeaders[REQUEST any, RESPONSE any](attrs []attribute.KeyValue, names []string, getter HttpCommonAttrsGetter[REQUEST, RESPONSE], request REQUEST) []attribute.KeyValue {
+	if len(names) == 0 {
+		return attrs
+	}
+	return utils.CaptureHeaders(attrs, requestHeaderPrefix, names, func(name string) []string {
+		return getter.GetHttpRequestHeader(request, name)
+	})
+}
+
+func captureResponseHeaders[REQUEST any, RESPONSE any](attrs []attribute.KeyValue, names []string, getter HttpCommonAttrsGetter[REQUEST, RESPONSE], request REQUEST, response RESPONSE) []attribute.KeyValue {
+	if len(names) == 0 {
+		return attrs
+	}
+	return utils.CaptureHeaders(attrs, responseHeaderPrefix, names, func(name string) []string {
+		return getter.GetHttpResponseHeader(request, response, name)
+	})
+}
@@ -108,6 +108,7 @@ func (m *MessageAttrsExtractor[REQUEST, RESPONSE, GETTER]) OnEnd(attributes []at
 		Key:   semconv.MessagingBatchMessageCountKey,
 		Value: attribute.Int64Value(m.Getter.GetBatchMessageCount(request, response)),
 	})
-	// TODO: add custom captured headers attributes
+	attributes = captureMessageHeaders[REQUEST, RESPONSE](attributes,
+		capturedMessageHeaders, m.Getter, request)
 	return attributes, context
 }
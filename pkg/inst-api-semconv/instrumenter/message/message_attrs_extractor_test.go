@@ -178,3 +178,16 @@ func TestMessageClientExtractorEnd(t *testing.T) {
 		t.Fatalf("messaging batch message count should be 2024")
 	}
 }
+
+func TestMessageExtractorCaptureHeaders(t *testing.T) {
+	capturedMessageHeaders = []string{"x-tenant"}
+	defer func() { capturedMessageHeaders = nil }()
+	messageExtractor := MessageAttrsExtractor[testRequest, testResponse, messageAttrsGetter]{}
+	attrs, _ := messageExtractor.OnEnd(nil, context.Background(), testRequest{}, testResponse{}, nil)
+	if len(attrs) != 3 || attrs[2].Key != "messaging.header.x-tenant" {
+		t.Fatalf("message header should be captured, got %v", attrs)
+	}
+	if values := attrs[2].Value.AsStringSlice(); len(values) != 2 || values[1] != "header2" {
+		t.Fatalf("unexpected header values %v", values)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package message
+
+import (
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// EnvCaptureHeaders is the comma-separated names of the message headers
+// captured as the messaging.header.<name> attributes, for both the producers
+// and the consumers
+const EnvCaptureHeaders = "OTEL_INSTRUMENTATION_MESSAGING_CAPTURE_HEADERS"
+
+const messageHeaderPrefix = "messaging.header."
+
+var capturedMessageHeaders = utils.CapturedHeadersFromEnv(EnvCaptureHeaders)
+
+func captureMessageHeaders[REQUEST any, RESPONSE any](attrs []attribute.KeyValue, names []string, getter MessageAttrsGetter[REQUEST, RESPONSE], request REQUEST) []attribute.KeyValue {
+	if len(names) == 0 {
+		return attrs
+	}
+	return utils.CaptureHeaders(attrs, messageHeaderPrefix, names, func(name string) []string {
+		return getter.GetMessageHeader(request, name)
+	})
+}
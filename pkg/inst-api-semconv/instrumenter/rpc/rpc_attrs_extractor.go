@@ -74,11 +74,17 @@ func (s *ServerRpcAttrsExtractor[REQUEST, RESPONSE, GETTER]) GetSpanKey() attrib
 }
 
 func (s *ServerRpcAttrsExtractor[REQUEST, RESPONSE, GETTER]) OnStart(attributes []attribute.KeyValue, parentContext context.Context, request REQUEST) ([]attribute.KeyValue, context.Context) {
-	return s.Base.OnStart(attributes, parentContext, request)
+	attributes, parentContext = s.Base.OnStart(attributes, parentContext, request)
+	attributes = captureRequestMetadata[REQUEST, RESPONSE](attributes,
+		capturedServerRequestMetadata, s.Base.Getter, request)
+	return attributes, parentContext
 }
 
 func (s *ServerRpcAttrsExtractor[REQUEST, RESPONSE, GETTER]) OnEnd(attributes []attribute.KeyValue, context context.Context, request REQUEST, response RESPONSE, err error) ([]attribute.KeyValue, context.Context) {
-	return s.Base.OnEnd(attributes, context, request, response, err)
+	attributes, context = s.Base.OnEnd(attributes, context, request, response, err)
+	attributes = captureResponseMetadata(attributes, capturedServerResponseMetadata,
+		s.Base.Getter, request, response)
+	return attributes, context
 }
 
 type ClientRpcAttrsExtractor[REQUEST any, RESPONSE any, GETTER RpcAttrsGetter[REQUEST]] struct {
@@ -90,9 +96,15 @@ func (s *ClientRpcAttrsExtractor[REQUEST, RESPONSE, GETTER]) GetSpanKey() attrib
 }
 
 func (s *ClientRpcAttrsExtractor[REQUEST, RESPONSE, GETTER]) OnStart(attributes []attribute.KeyValue, parentContext context.Context, request REQUEST) ([]attribute.KeyValue, context.Context) {
-	return s.Base.OnStart(attributes, parentContext, request)
+	attributes, parentContext = s.Base.OnStart(attributes, parentContext, request)
+	attributes = captureRequestMetadata[REQUEST, RESPONSE](attributes,
+		capturedClientRequestMetadata, s.Base.Getter, request)
+	return attributes, parentContext
 }
 
 func (s *ClientRpcAttrsExtractor[REQUEST, RESPONSE, GETTER]) OnEnd(attributes []attribute.KeyValue, context context.Context, request REQUEST, response RESPONSE, err error) ([]attribute.KeyValue, context.Context) {
-	return s.Base.OnEnd(attributes, context, request, response, err)
+	attributes, context = s.Base.OnEnd(attributes, context, request, response, err)
+	attributes = captureResponseMetadata(attributes, capturedClientResponseMetadata,
+		s.Base.Getter, request, response)
+	return attributes, context
 }
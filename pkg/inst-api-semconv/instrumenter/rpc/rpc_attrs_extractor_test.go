@@ -157,3 +157,44 @@ func TestGrpcClientExtractorEndSuccess(t *testing.T) {
 		log.Fatal("Expected status code 0 (OK)")
 	}
 }
+
+func (h grpcAttrsGetter) GetRequestMetadata(request testRequest, name string) []string {
+	return []string{"request-" + name}
+}
+
+func (h grpcAttrsGetter) GetResponseMetadata(request testRequest, response testResponse, name string) []string {
+	if name == "absent" {
+		return nil
+	}
+	return []string{"response-" + name}
+}
+
+func TestRpcExtractorCaptureMetadata(t *testing.T) {
+	capturedServerRequestMetadata = []string{"tenant"}
+	capturedClientResponseMetadata = []string{"region", "absent"}
+	defer func() {
+		capturedServerRequestMetadata = nil
+		capturedClientResponseMetadata = nil
+	}()
+	serverExtractor := ServerRpcAttrsExtractor[testRequest, testResponse, grpcAttrsGetter]{}
+	attrs, _ := serverExtractor.OnStart(nil, context.Background(), testRequest{})
+	last := attrs[len(attrs)-1]
+	if last.Key != "rpc.grpc.request.metadata.tenant" ||
+		last.Value.AsStringSlice()[0] != "request-tenant" {
+		t.Fatalf("request metadata should be captured, got %v", last)
+	}
+	clientExtractor := ClientRpcAttrsExtractor[testRequest, testResponse, grpcAttrsGetter]{}
+	attrs, _ = clientExtractor.OnEnd(nil, context.Background(), testRequest{}, testResponse{}, nil)
+	if len(attrs) != 2 || attrs[1].Key != "rpc.grpc.response.metadata.region" {
+		t.Fatalf("response metadata should be captured, got %v", attrs)
+	}
+	// The getter without metadata support captures nothing
+	rpcExtractor := ServerRpcAttrsExtractor[testRequest, testResponse, rpcAttrsGetter]{}
+	attrs, _ = rpcExtractor.OnStart(nil, context.Background(), testRequest{})
+	if len(attrs) != 4 {
+		t.Fatalf("no metadata should be captured, got %v", attrs)
+	}
+	if prefix := metadataPrefix("apache_dubbo", "request"); prefix != "rpc.request.metadata." {
+		t.Fatalf("unexpected metadata prefix %s", prefix)
+	}
+}
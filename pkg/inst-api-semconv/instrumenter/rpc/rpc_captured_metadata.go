@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// The comma-separated names of the metadata captured as the
+// rpc.request.metadata.<name> and rpc.response.metadata.<name> attributes, or
+// rpc.grpc.request.metadata.<name> and rpc.grpc.response.metadata.<name> for
+// gRPC
+const (
+	EnvCaptureMetadataServerRequest  = "OTEL_INSTRUMENTATION_RPC_CAPTURE_METADATA_SERVER_REQUEST"
+	EnvCaptureMetadataServerResponse = "OTEL_INSTRUMENTATION_RPC_CAPTURE_METADATA_SERVER_RESPONSE"
+	EnvCaptureMetadataClientRequest  = "OTEL_INSTRUMENTATION_RPC_CAPTURE_METADATA_CLIENT_REQUEST"
+	EnvCaptureMetadataClientResponse = "OTEL_INSTRUMENTATION_RPC_CAPTURE_METADATA_CLIENT_RESPONSE"
+)
+
+var (
+	capturedServerRequestMetadata  = utils.CapturedHeadersFromEnv(EnvCaptureMetadataServerRequest)
+	capturedServerResponseMetadata = utils.CapturedHeadersFromEnv(EnvCaptureMetadataServerResponse)
+	capturedClientRequestMetadata  = utils.CapturedHeadersFromEnv(EnvCaptureMetadataClientRequest)
+	capturedClientResponseMetadata = utils.CapturedHeadersFromEnv(EnvCaptureMetadataClientResponse)
+)
+
+// RpcMetadataGetter is implemented by the RpcAttrsGetter of the frameworks
+// whose metadata can be captured, the metadata of the others is never captured
+type RpcMetadataGetter[REQUEST any, RESPONSE any] interface {
+	GetRequestMetadata(request REQUEST, name string) []string
+	GetResponseMetadata(request REQUEST, response RESPONSE, name string) []string
+}
+
+func metadataPrefix(system string, direction string) string {
+	if system == "grpc" {
+		return "rpc.grpc." + direction + ".metadata."
+	}
+	return "rpc." + direction + ".metadata."
+}
+
+func captureRequestMetadata[REQUEST any, RESPONSE any, GETTER RpcAttrsGetter[REQUEST]](attrs []attribute.KeyValue, names []string, getter GETTER, request REQUEST) []attribute.KeyValue {
+	if len(names) == 0 {
+		return attrs
+	}
+	mdGetter, ok := any(getter).(RpcMetadataGetter[REQUEST, RESPONSE])
+	if !ok {
+		return attrs
+	}
+	prefix := metadataPrefix(getter.GetSystem(request), "request")
+	return utils.CaptureHeaders(attrs, prefix, names, func(name string) []string {
+		return mdGetter.GetRequestMetadata(request, name)
+	})
+}
+
+func captureResponseMetadata[REQUEST any, RESPONSE any, GETTER RpcAttrsGetter[REQUEST]](attrs []attribute.KeyValue, names []string, getter GETTER, request REQUEST, response RESPONSE) []attribute.KeyValue {
+	if len(names) == 0 {
+		return attrs
+	}
+	mdGetter, ok := any(getter).(RpcMetadataGetter[REQUEST, RESPONSE])
+	if !ok {
+		return attrs
+	}
+	prefix := metadataPrefix(getter.GetSystem(request), "response")
+	return utils.CaptureHeaders(attrs, prefix, names, func(name string) []string {
+		return mdGetter.GetResponseMetadata(request, response, name)
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ParseCapturedHeaders parses the comma-separated header names, which are
+// lower-cased since the header names are case-insensitive and become part of
+// the attribute keys
+func ParseCapturedHeaders(val string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(val, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func CapturedHeadersFromEnv(name string) []string {
+	return ParseCapturedHeaders(os.Getenv(name))
+}
+
+// CaptureHeaders appends the values of the captured headers as string slice
+// attributes keyed by the prefix and the header name, absent headers are
+// skipped
+func CaptureHeaders(attrs []attribute.KeyValue, prefix string, names []string, get func(name string) []string) []attribute.KeyValue {
+	for _, name := range names {
+		values := get(name)
+		if len(values) == 0 {
+			continue
+		}
+		attrs = append(attrs, attribute.StringSlice(prefix+name, values))
+	}
+	return attrs
+}
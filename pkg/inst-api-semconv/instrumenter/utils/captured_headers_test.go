@@ -0,0 +1,30 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCapturedHeaders(t *testing.T) {
+	names := ParseCapturedHeaders(" X-Request-ID,content-type,,x-request-id ")
+	if !reflect.DeepEqual(names, []string{"x-request-id", "content-type"}) {
+		t.Fatalf("unexpected header names %v", names)
+	}
+	if names := ParseCapturedHeaders(""); names != nil {
+		t.Fatalf("no header names are expected, got %v", names)
+	}
+}
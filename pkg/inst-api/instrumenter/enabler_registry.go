@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumenter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/config"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+)
+
+// Sources of the effective value of a registered enabler, from the highest
+// priority to the lowest
+const (
+	EnablerSourceRuntime = "runtime"
+	EnablerSourceConfig  = "config"
+	EnablerSourceEnv     = "env"
+)
+
+const (
+	overrideNone int32 = iota
+	overrideEnabled
+	overrideDisabled
+)
+
+// ScopeInstrumentEnabler is an InstrumentEnabler registered under the
+// instrumentation scope, so that the instrumentation can be turned on and off
+// in a running process. The value set at runtime takes precedence over the
+// config file, which in turn takes precedence over the environment variable
+// captured at startup
+type ScopeInstrumentEnabler struct {
+	scope    string
+	name     string
+	envValue bool
+	override atomic.Int32
+}
+
+// EnablerStatus is the snapshot of a registered enabler
+type EnablerStatus struct {
+	Scope    string                        `json:"scope"`
+	Name     string                        `json:"name"`
+	Category utils.InstrumentationCategory `json:"category"`
+	Enabled  bool                          `json:"enabled"`
+	Source   string                        `json:"source"`
+}
+
+var (
+	enablersLock sync.RWMutex
+	enablers     = make(map[string]*ScopeInstrumentEnabler)
+)
+
+// RegisterInstrumentEnabler registers the enabler of the instrumentation scope,
+// name is the lower-cased <NAME> part of OTEL_INSTRUMENTATION_<NAME>_ENABLED,
+// which is used to look up the config file, and enabled is the value of that
+// environment variable. The enabler registered first is returned if the scope
+// is shared by multiple rule packages, e.g. the client and the server of hertz
+func RegisterInstrumentEnabler(scope, name string, enabled bool) *ScopeInstrumentEnabler {
+	enablersLock.Lock()
+	defer enablersLock.Unlock()
+	if e, ok := enablers[scope]; ok {
+		return e
+	}
+	e := &ScopeInstrumentEnabler{scope: scope, name: name, envValue: enabled}
+	enablers[scope] = e
+	return e
+}
+
+// Enable reports whether the instrumentation is enabled. The hooks may run
+// before the rule package is initialized, e.g. logrus.New is called while
+// initializing the logrus package, the nil enabler is disabled in this case.
+// Since the state may change at any time, it's checked by onEnter hooks only,
+// onExit hooks finish whatever their onEnter hooks started, e.g. end the span
+// kept in the call context, regardless of the current state.
+func (e *ScopeInstrumentEnabler) Enable() bool {
+	if e == nil {
+		return false
+	}
+	enabled, _ := e.lookup()
+	return enabled
+}
+
+func (e *ScopeInstrumentEnabler) lookup() (bool, string) {
+	switch e.override.Load() {
+	case overrideEnabled:
+		return true, EnablerSourceRuntime
+	case overrideDisabled:
+		return false, EnablerSourceRuntime
+	}
+	if enabled, ok := config.LookupInstrumentationEnabled(e.name); ok {
+		return enabled, EnablerSourceConfig
+	}
+	return e.envValue, EnablerSourceEnv
+}
+
+func (e *ScopeInstrumentEnabler) Status() EnablerStatus {
+	enabled, source := e.lookup()
+	return EnablerStatus{
+		Scope:    e.scope,
+		Name:     e.name,
+		Category: utils.GetCategory(e.scope),
+		Enabled:  enabled,
+		Source:   source,
+	}
+}
+
+// GetInstrumentEnabler returns the enabler registered under the scope, or nil
+// if the instrumentation is not compiled into the binary
+func GetInstrumentEnabler(scope string) *ScopeInstrumentEnabler {
+	enablersLock.RLock()
+	defer enablersLock.RUnlock()
+	return enablers[scope]
+}
+
+// GetInstrumentEnablerStatus returns the status of all registered enablers,
+// sorted by the scope name
+func GetInstrumentEnablerStatus() []EnablerStatus {
+	enablersLock.RLock()
+	status := make([]EnablerStatus, 0, len(enablers))
+	for _, e := range enablers {
+		status = append(status, e.Status())
+	}
+	enablersLock.RUnlock()
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Scope < status[j].Scope
+	})
+	return status
+}
+
+// SetInstrumentEnabled overrides the enabled state of the instrumentation
+// until the process exits or ResetInstrumentEnabled is called
+func SetInstrumentEnabled(scope string, enabled bool) error {
+	e := GetInstrumentEnabler(scope)
+	if e == nil {
+		return fmt.Errorf("instrumentation %s is not registered", scope)
+	}
+	if enabled {
+		e.override.Store(overrideEnabled)
+	} else {
+		e.override.Store(overrideDisabled)
+	}
+	return nil
+}
+
+// ResetInstrumentEnabled drops the value set at runtime, the instrumentation
+// falls back to the config file and the environment variable
+func ResetInstrumentEnabled(scope string) error {
+	e := GetInstrumentEnabler(scope)
+	if e == nil {
+		return fmt.Errorf("instrumentation %s is not registered", scope)
+	}
+	e.override.Store(overrideNone)
+	return nil
+}
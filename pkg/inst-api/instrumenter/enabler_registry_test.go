@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumenter
+
+import (
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+)
+
+func TestRegisterInstrumentEnabler(t *testing.T) {
+	e := RegisterInstrumentEnabler(utils.HERTZ_HTTP_SERVER_SCOPE_NAME, "hertz", true)
+	if !e.Enable() {
+		t.Fatal("enabler is expected to be enabled by env")
+	}
+	if RegisterInstrumentEnabler(utils.HERTZ_HTTP_CLIENT_SCOPE_NAME, "hertz", true) != e {
+		t.Fatal("enabler of the same scope is expected to be shared")
+	}
+	status := e.Status()
+	if status.Category != utils.CategoryHTTP || status.Source != EnablerSourceEnv {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	var uninitialized *ScopeInstrumentEnabler
+	if uninitialized.Enable() {
+		t.Fatal("uninitialized enabler is expected to be disabled")
+	}
+}
+
+func TestSetInstrumentEnabled(t *testing.T) {
+	e := RegisterInstrumentEnabler(utils.ZAP_SCOPE_NAME, "zap", true)
+	if err := SetInstrumentEnabled(utils.ZAP_SCOPE_NAME, false); err != nil {
+		t.Fatal(err)
+	}
+	if e.Enable() || e.Status().Source != EnablerSourceRuntime {
+		t.Fatalf("enabler is expected to be disabled at runtime, got %+v", e.Status())
+	}
+	found := false
+	for _, status := range GetInstrumentEnablerStatus() {
+		if status.Scope == utils.ZAP_SCOPE_NAME {
+			found = !status.Enabled && status.Category == utils.CategoryLog
+		}
+	}
+	if !found {
+		t.Fatal("disabled zap enabler is expected in the status list")
+	}
+	if err := ResetInstrumentEnabled(utils.ZAP_SCOPE_NAME); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Enable() {
+		t.Fatal("enabler is expected to fall back to env after reset")
+	}
+	if SetInstrumentEnabled("loongsuite.instrumentation.unknown", true) == nil {
+		t.Fatal("expect error for unregistered scope")
+	}
+}
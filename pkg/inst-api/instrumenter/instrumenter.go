@@ -19,6 +19,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/selftelemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -45,6 +46,7 @@ type InternalInstrumenter[REQUEST any, RESPONSE any] struct {
 	spanSuppressor       SpanSuppressor
 	tracer               trace.Tracer
 	instVersion          string
+	scope                string
 }
 
 type PropagatingToDownstreamInstrumenter[REQUEST any, RESPONSE any] struct {
@@ -62,7 +64,9 @@ type PropagatingFromUpstreamInstrumenter[REQUEST any, RESPONSE any] struct {
 func (i *InternalInstrumenter[REQUEST, RESPONSE]) ShouldStart(parentContext context.Context, request REQUEST) bool {
 	spanKind := i.spanKindExtractor.Extract(request)
 	suppressed := i.spanSuppressor.ShouldSuppress(parentContext, spanKind)
-	// TODO: record suppressed span
+	if suppressed {
+		selftelemetry.SpanSuppressed(i.scope, spanKind)
+	}
 	return !suppressed
 }
 
@@ -105,13 +109,15 @@ func (i *InternalInstrumenter[REQUEST, RESPONSE]) doStart(parentContext context.
 	// extract span name
 	spanName := i.spanNameExtractor.Extract(request)
 	spanKind := i.spanKindExtractor.Extract(request)
-	options = append(options, trace.WithSpanKind(spanKind), trace.WithTimestamp(timestamp))
-	newCtx, span := i.tracer.Start(parentContext, spanName, options...)
 	attrs := make([]attribute.KeyValue, 0, 20)
-	// extract span attrs
+	// extract span attrs before the span starts, so that the sampler can make
+	// decisions on them, e.g. http.route and rpc.method
 	for _, extractor := range i.attributesExtractors {
-		attrs, newCtx = extractor.OnStart(attrs, newCtx, request)
+		attrs, parentContext = extractor.OnStart(attrs, parentContext, request)
 	}
+	options = append(options, trace.WithSpanKind(spanKind), trace.WithTimestamp(timestamp),
+		trace.WithAttributes(attrs...))
+	newCtx, span := i.tracer.Start(parentContext, spanName, options...)
 	// execute context customizer hook
 	for _, customizer := range i.contextCustomizers {
 		newCtx = customizer.OnStart(newCtx, request, attrs)
@@ -119,7 +125,6 @@ func (i *InternalInstrumenter[REQUEST, RESPONSE]) doStart(parentContext context.
 	for _, listener := range i.operationListeners {
 		newCtx = listener.OnBeforeEnd(newCtx, attrs, timestamp)
 	}
-	span.SetAttributes(attrs...)
 	return i.spanSuppressor.StoreInContext(newCtx, spanKind, span)
 }
 
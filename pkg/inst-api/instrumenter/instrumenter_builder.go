@@ -116,6 +116,7 @@ func (b *Builder[REQUEST, RESPONSE]) BuildInstrumenter() *InternalInstrumenter[R
 		spanSuppressor:       b.buildSpanSuppressor(),
 		tracer:               tracer,
 		instVersion:          b.InstVersion,
+		scope:                b.Scope.Name,
 	}
 }
 
@@ -131,6 +132,7 @@ func (b *Builder[REQUEST, RESPONSE]) BuildInstrumenterWithTracer(tracer trace.Tr
 		spanSuppressor:       b.buildSpanSuppressor(),
 		tracer:               tracer,
 		instVersion:          b.InstVersion,
+		scope:                b.Scope.Name,
 	}
 }
 
@@ -151,6 +153,7 @@ func (b *Builder[REQUEST, RESPONSE]) BuildPropagatingToDownstreamInstrumenter(ca
 			spanSuppressor:       b.buildSpanSuppressor(),
 			tracer:               tracer,
 			instVersion:          b.InstVersion,
+			scope:                b.Scope.Name,
 		},
 		carrierGetter: carrierGetter,
 		prop:          prop,
@@ -173,6 +176,7 @@ func (b *Builder[REQUEST, RESPONSE]) BuildPropagatingFromUpstreamInstrumenter(ca
 			spanSuppressor:       b.buildSpanSuppressor(),
 			tracer:               tracer,
 			instVersion:          b.InstVersion,
+			scope:                b.Scope.Name,
 		},
 		carrierGetter: carrierGetter,
 		prop:          prop,
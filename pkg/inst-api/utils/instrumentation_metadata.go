@@ -25,6 +25,7 @@ const (
 	CategoryDB        InstrumentationCategory = "db"
 	CategoryMessaging InstrumentationCategory = "messaging"
 	CategoryAI        InstrumentationCategory = "ai"
+	CategoryLog       InstrumentationCategory = "log"
 	CategoryOther     InstrumentationCategory = "other"
 )
 
@@ -82,6 +83,39 @@ var InstrumentationRegistry = map[string]*InstrumentationMetadata{
 		ServerKey: HTTP_SERVER_KEY,
 	},
 
+	// HTTP routers, they only enrich the server spans of the underlying
+	// framework and never start spans on their own
+	"loongsuite.instrumentation.gin": {
+		ScopeName: "loongsuite.instrumentation.gin",
+		Category:  CategoryHTTP,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.echo": {
+		ScopeName: "loongsuite.instrumentation.echo",
+		Category:  CategoryHTTP,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.mux": {
+		ScopeName: "loongsuite.instrumentation.mux",
+		Category:  CategoryHTTP,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.iris": {
+		ScopeName: "loongsuite.instrumentation.iris",
+		Category:  CategoryHTTP,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.gorestful": {
+		ScopeName: "loongsuite.instrumentation.gorestful",
+		Category:  CategoryHTTP,
+		ClientKey: "",
+		ServerKey: "",
+	},
+
 	// RPC
 	"loongsuite.instrumentation.grpc": {
 		ScopeName: "loongsuite.instrumentation.grpc",
@@ -175,6 +209,12 @@ var InstrumentationRegistry = map[string]*InstrumentationMetadata{
 		ClientKey: DB_CLIENT_KEY,
 		ServerKey: "",
 	},
+	"loongsuite.instrumentation.rueidis": {
+		ScopeName: "loongsuite.instrumentation.rueidis",
+		Category:  CategoryDB,
+		ClientKey: DB_CLIENT_KEY,
+		ServerKey: "",
+	},
 
 	// Messaging
 	"loongsuite.instrumentation.amqp091": {
@@ -210,6 +250,44 @@ var InstrumentationRegistry = map[string]*InstrumentationMetadata{
 		ServerKey: "",
 	},
 
+	// Logging, they only inject the trace context into the log records
+	"loongsuite.instrumentation.zap": {
+		ScopeName: "loongsuite.instrumentation.zap",
+		Category:  CategoryLog,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.logrus": {
+		ScopeName: "loongsuite.instrumentation.logrus",
+		Category:  CategoryLog,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.zerolog": {
+		ScopeName: "loongsuite.instrumentation.zerolog",
+		Category:  CategoryLog,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.goslog": {
+		ScopeName: "loongsuite.instrumentation.goslog",
+		Category:  CategoryLog,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.glog": {
+		ScopeName: "loongsuite.instrumentation.glog",
+		Category:  CategoryLog,
+		ClientKey: "",
+		ServerKey: "",
+	},
+	"loongsuite.instrumentation.gokitlog": {
+		ScopeName: "loongsuite.instrumentation.gokitlog",
+		Category:  CategoryLog,
+		ClientKey: "",
+		ServerKey: "",
+	},
+
 	// Other
 	"loongsuite.instrumentation.sentinel": {
 		ScopeName: "loongsuite.instrumentation.sentinel",
@@ -217,6 +295,12 @@ var InstrumentationRegistry = map[string]*InstrumentationMetadata{
 		ClientKey: "",
 		ServerKey: "",
 	},
+	"loongsuite.instrumentation.tracer": {
+		ScopeName: "loongsuite.instrumentation.tracer",
+		Category:  CategoryOther,
+		ClientKey: "",
+		ServerKey: "",
+	},
 }
 
 // GetInstrumentationMetadata returns metadata for a given scope name
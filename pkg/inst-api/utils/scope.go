@@ -51,8 +51,21 @@ const ROCKETMQGO_PRODUCER_SCOPE_NAME = "loongsuite.instrumentation.rocketmq"
 const ROCKETMQGO_CONSUMER_SCOPE_NAME = "loongsuite.instrumentation.rocketmq"
 const GOPG_SCOPE_NAME = "loongsuite.instrumentation.gopg"
 const SENTINEL_SCOPE_NAME = "loongsuite.instrumentation.sentinel"
+const TRACER_SCOPE_NAME = "loongsuite.instrumentation.tracer"
 const GOCQL_SCOPE_NAME = "loongsuite.instrumentation.gocql"
 const SQLX_SCOPE_NAME = "loongsuite.instrumentation.sqlx"
 const RPCXGO_CLIENT_SCOPE_NAME = "loongsuite.instrumentation.rpcx"
 const RPCXGO_SERVER_SCOPE_NAME = "loongsuite.instrumentation.rpcx"
 const OLLAMA_SCOPE_NAME = "loongsuite.instrumentation.ollama"
+const RUEIDIS_SCOPE_NAME = "loongsuite.instrumentation.rueidis"
+const GIN_SCOPE_NAME = "loongsuite.instrumentation.gin"
+const ECHO_SCOPE_NAME = "loongsuite.instrumentation.echo"
+const MUX_SCOPE_NAME = "loongsuite.instrumentation.mux"
+const IRIS_SCOPE_NAME = "loongsuite.instrumentation.iris"
+const GORESTFUL_SCOPE_NAME = "loongsuite.instrumentation.gorestful"
+const ZAP_SCOPE_NAME = "loongsuite.instrumentation.zap"
+const LOGRUS_SCOPE_NAME = "loongsuite.instrumentation.logrus"
+const ZEROLOG_SCOPE_NAME = "loongsuite.instrumentation.zerolog"
+const GOSLOG_SCOPE_NAME = "loongsuite.instrumentation.goslog"
+const GLOG_SCOPE_NAME = "loongsuite.instrumentation.glog"
+const GOKITLOG_SCOPE_NAME = "loongsuite.instrumentation.gokitlog"
@@ -0,0 +1,274 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/config"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// The SDK components are built from the config file if it's specified via
+// OTEL_EXPERIMENTAL_CONFIG_FILE, otherwise from the environment variables.
+// Each section of the config file overrides the corresponding environment
+// variables as a whole, sections absent from the config file still honor the
+// environment variables.
+
+func millis(ms int) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+func headersOf(otlp *config.OTLPExporter) map[string]string {
+	headers := make(map[string]string, len(otlp.Headers))
+	for _, h := range otlp.Headers {
+		if h != nil {
+			headers[h.Name] = h.Value
+		}
+	}
+	return headers
+}
+
+func isGrpcProtocol(protocol string) (bool, error) {
+	switch protocol {
+	case "grpc":
+		return true, nil
+	case "", "http/protobuf":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported otlp protocol: %s", protocol)
+	}
+}
+
+func newOTLPTraceExporter(ctx context.Context, otlp *config.OTLPExporter) (trace.SpanExporter, error) {
+	grpc, err := isGrpcProtocol(otlp.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	if grpc {
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithHeaders(headersOf(otlp))}
+		if otlp.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(otlp.Endpoint))
+		}
+		if otlp.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if otlp.Timeout != nil {
+			opts = append(opts, otlptracegrpc.WithTimeout(millis(*otlp.Timeout)))
+		}
+		return otlptrace.New(ctx, otlptracegrpc.NewClient(opts...))
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithHeaders(headersOf(otlp))}
+	if otlp.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(otlp.Endpoint))
+	}
+	if otlp.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if otlp.Timeout != nil {
+		opts = append(opts, otlptracehttp.WithTimeout(millis(*otlp.Timeout)))
+	}
+	return otlptrace.New(ctx, otlptracehttp.NewClient(opts...))
+}
+
+func newSpanExporterFromConfig(ctx context.Context, exporter *config.SpanExporter) (trace.SpanExporter, error) {
+	switch {
+	case exporter == nil:
+		return nil, errors.New("no exporter is specified")
+	case exporter.OTLP != nil:
+		return newOTLPTraceExporter(ctx, exporter.OTLP)
+	case exporter.Console != nil:
+		return stdouttrace.New()
+	case exporter.Zipkin != nil:
+		return zipkin.New(exporter.Zipkin.Endpoint)
+	default:
+		return nil, errors.New("unknown span exporter")
+	}
+}
+
+func newSpanProcessorsFromConfig(ctx context.Context, tp *config.TracerProvider) []trace.SpanProcessor {
+	var processors []trace.SpanProcessor
+	for i, p := range tp.Processors {
+		if p.Simple != nil {
+			exporter, err := newSpanExporterFromConfig(ctx, p.Simple.Exporter)
+			if err != nil {
+				log.Printf("Failed to create span exporter of processor %d: %v", i, err)
+				continue
+			}
+			spanExporters = append(spanExporters, exporter)
+			processors = append(processors, newSimpleSpanProcessor(exporter))
+			continue
+		}
+		exporter, err := newSpanExporterFromConfig(ctx, p.Batch.Exporter)
+		if err != nil {
+			log.Printf("Failed to create span exporter of processor %d: %v", i, err)
+			continue
+		}
+		spanExporters = append(spanExporters, exporter)
+		var opts []trace.BatchSpanProcessorOption
+		if p.Batch.ScheduleDelay != nil {
+			opts = append(opts, trace.WithBatchTimeout(millis(*p.Batch.ScheduleDelay)))
+		}
+		if p.Batch.ExportTimeout != nil {
+			opts = append(opts, trace.WithExportTimeout(millis(*p.Batch.ExportTimeout)))
+		}
+		if p.Batch.MaxQueueSize != nil {
+			opts = append(opts, trace.WithMaxQueueSize(*p.Batch.MaxQueueSize))
+		}
+		if p.Batch.MaxExportBatchSize != nil {
+			opts = append(opts, trace.WithMaxExportBatchSize(*p.Batch.MaxExportBatchSize))
+		}
+		processors = append(processors, newBatchSpanProcessor(exporter, opts...))
+	}
+	return processors
+}
+
+// newSpanLimitsFromConfig applies the general attribute limits first, then the
+// span specific limits on top of the limits from environment variables
+func newSpanLimitsFromConfig(conf *config.Config) (trace.SpanLimits, bool) {
+	limits := trace.NewSpanLimits()
+	changed := false
+	set := func(dst *int, src *int) {
+		if src != nil {
+			*dst = *src
+			changed = true
+		}
+	}
+	if al := conf.AttributeLimits; al != nil {
+		set(&limits.AttributeValueLengthLimit, al.AttributeValueLengthLimit)
+		set(&limits.AttributeCountLimit, al.AttributeCountLimit)
+	}
+	if conf.TracerProvider != nil && conf.TracerProvider.Limits != nil {
+		sl := conf.TracerProvider.Limits
+		set(&limits.AttributeValueLengthLimit, sl.AttributeValueLengthLimit)
+		set(&limits.AttributeCountLimit, sl.AttributeCountLimit)
+		set(&limits.EventCountLimit, sl.EventCountLimit)
+		set(&limits.LinkCountLimit, sl.LinkCountLimit)
+		set(&limits.AttributePerEventCountLimit, sl.EventAttributeCountLimit)
+		set(&limits.AttributePerLinkCountLimit, sl.LinkAttributeCountLimit)
+	}
+	return limits, changed
+}
+
+func newOTLPMetricExporter(ctx context.Context, otlp *config.OTLPExporter) (metric.Exporter, error) {
+	grpc, err := isGrpcProtocol(otlp.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	selector := parseTemporalitySelector(otlp.TemporalityPreference)
+	if grpc {
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithHeaders(headersOf(otlp)),
+			otlpmetricgrpc.WithTemporalitySelector(selector),
+		}
+		if otlp.Endpoint != "" {
+			opts = append(opts, otlpmetricgrpc.WithEndpointURL(otlp.Endpoint))
+		}
+		if otlp.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		if otlp.Timeout != nil {
+			opts = append(opts, otlpmetricgrpc.WithTimeout(millis(*otlp.Timeout)))
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	}
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithHeaders(headersOf(otlp)),
+		otlpmetrichttp.WithTemporalitySelector(selector),
+	}
+	if otlp.Endpoint != "" {
+		opts = append(opts, otlpmetrichttp.WithEndpointURL(otlp.Endpoint))
+	}
+	if otlp.Insecure {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if otlp.Timeout != nil {
+		opts = append(opts, otlpmetrichttp.WithTimeout(millis(*otlp.Timeout)))
+	}
+	return otlpmetrichttp.New(ctx, opts...)
+}
+
+func newMetricReaderFromConfig(ctx context.Context, r *config.MetricReader) (metric.Reader, metric.Exporter, error) {
+	if r.Pull != nil {
+		if r.Pull.Exporter == nil || r.Pull.Exporter.Prometheus == nil {
+			return nil, nil, errors.New("no pull exporter is specified")
+		}
+		reader, err := prometheus.New()
+		if err != nil {
+			return nil, nil, err
+		}
+		prom := r.Pull.Exporter.Prometheus
+		port := default_prometheus_exporter_port
+		if prom.Port != nil {
+			port = fmt.Sprint(*prom.Port)
+		}
+		go serveMetrics(prom.Host + ":" + port)
+		return reader, nil, nil
+	}
+	var exporter metric.Exporter
+	var err error
+	switch e := r.Periodic.Exporter; {
+	case e == nil:
+		return nil, nil, errors.New("no exporter is specified")
+	case e.OTLP != nil:
+		exporter, err = newOTLPMetricExporter(ctx, e.OTLP)
+	case e.Console != nil:
+		exporter, err = stdoutmetric.New(
+			stdoutmetric.WithTemporalitySelector(getTemporalitySelector()))
+	default:
+		return nil, nil, errors.New("unknown metric exporter")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var opts []metric.PeriodicReaderOption
+	if r.Periodic.Interval != nil {
+		opts = append(opts, metric.WithInterval(millis(*r.Periodic.Interval)))
+	}
+	if r.Periodic.Timeout != nil {
+		opts = append(opts, metric.WithTimeout(millis(*r.Periodic.Timeout)))
+	}
+	return metric.NewPeriodicReader(exporter, opts...), exporter, nil
+}
+
+func newMetricReadersFromConfig(ctx context.Context, mp *config.MeterProvider) []metric.Reader {
+	var readers []metric.Reader
+	for i, r := range mp.Readers {
+		reader, exporter, err := newMetricReaderFromConfig(ctx, r)
+		if err != nil {
+			log.Printf("Failed to create metric reader %d: %v", i, err)
+			continue
+		}
+		if exporter != nil {
+			metricExporters = append(metricExporters, exporter)
+		}
+		readers = append(readers, reader)
+	}
+	return readers
+}
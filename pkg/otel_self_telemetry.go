@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/selftelemetry"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+type batchProcessor struct {
+	exporter  string
+	processor trace.SpanProcessor
+}
+
+// Batch span processors created so far, their dropped spans are reported by
+// the self telemetry
+var batchProcessors []batchProcessor
+
+func spanExporterName(exporter trace.SpanExporter) string {
+	switch exporter.(type) {
+	case *otlptrace.Exporter:
+		return "otlp"
+	case *stdouttrace.Exporter:
+		return "console"
+	case *zipkin.Exporter:
+		return "zipkin"
+	default:
+		return fmt.Sprintf("%T", exporter)
+	}
+}
+
+func newSimpleSpanProcessor(exporter trace.SpanExporter) trace.SpanProcessor {
+	name := spanExporterName(exporter)
+	return trace.NewSimpleSpanProcessor(
+		selftelemetry.WrapSpanExporter(name, exporter))
+}
+
+func newBatchSpanProcessor(exporter trace.SpanExporter,
+	opts ...trace.BatchSpanProcessorOption) trace.SpanProcessor {
+	name := spanExporterName(exporter)
+	processor := trace.NewBatchSpanProcessor(
+		selftelemetry.WrapSpanExporter(name, exporter), opts...)
+	batchProcessors = append(batchProcessors, batchProcessor{name, processor})
+	return processor
+}
+
+func queueDroppedSpans() map[string]int64 {
+	dropped := make(map[string]int64)
+	for _, p := range batchProcessors {
+		dropped[p.exporter] += trace.DroppedSpansOf(p.processor)
+	}
+	return dropped
+}
+
+func initSelfTelemetry(mp otelmetric.MeterProvider) {
+	err := selftelemetry.Init(mp, selftelemetry.SDKStats{
+		TraceContextDropped: trace.TraceContextDroppedSpans,
+		QueueDropped:        queueDroppedSpans,
+	})
+	if err != nil {
+		log.Printf("Failed to init self telemetry: %v", err)
+	}
+}
@@ -22,10 +22,14 @@ import (
 	http2 "net/http"
 	"os"
 	"runtime"
-	"strconv"
 	"strings"
 
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/admin"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/config"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logbridge"
 	"github.com/alibaba/loongsuite-go-agent/pkg/core/meter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/sampler"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/tailsampling"
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/ai"
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/db"
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/experimental"
@@ -39,18 +43,22 @@ import (
 	// The version of the following packages/modules must be fixed
 	"go.opentelemetry.io/otel"
 	_ "go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/exporters/zipkin"
 	otelmetric "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/trace"
@@ -65,12 +73,13 @@ const report_protocol = "OTEL_EXPORTER_OTLP_PROTOCOL"
 const trace_report_protocol = "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"
 const metrics_exporter = "OTEL_METRICS_EXPORTER"
 const trace_exporter = "OTEL_TRACES_EXPORTER"
+const trace_processor = "OTEL_EXPERIMENTAL_TRACES_PROCESSOR"
+const logs_exporter = "OTEL_LOGS_EXPORTER"
+const logs_report_protocol = "OTEL_EXPORTER_OTLP_LOGS_PROTOCOL"
 const prometheus_exporter_port = "OTEL_EXPORTER_PROMETHEUS_PORT"
 const default_prometheus_exporter_port = "9464"
 const metrics_temporality_preference = "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"
 
-const trace_sampler = "OTEL_TRACE_SAMPLER"
-
 var (
 	metricExporters    []metric.Exporter
 	spanExporters      []trace.SpanExporter
@@ -78,6 +87,8 @@ var (
 	metricsProvider    otelmetric.MeterProvider
 	spanProcessors     []trace.SpanProcessor
 	spanSampler        trace.Sampler
+	loggerProvider     *sdklog.LoggerProvider
+	adminServer        *admin.Server
 )
 
 func init() {
@@ -102,6 +113,12 @@ func init() {
 	if err = initOpenTelemetry(ctx); err != nil {
 		log.Fatalf("%s: %v", "Failed to initialize opentelemetry resource", err)
 	}
+	if endpoint := os.Getenv(admin.EnvAdminEndpoint); endpoint != "" {
+		adminServer, err = admin.Start(endpoint)
+		if err != nil {
+			log.Printf("Failed to start admin endpoint %s: %v", endpoint, err)
+		}
+	}
 }
 
 func newSpanProcessors(ctx context.Context) []trace.SpanProcessor {
@@ -110,6 +127,18 @@ func newSpanProcessors(ctx context.Context) []trace.SpanProcessor {
 		simpleProcessor := trace.NewSimpleSpanProcessor(traceExporter)
 		return []trace.SpanProcessor{simpleProcessor}
 	}
+	if conf := config.Get(); conf != nil && conf.Disabled {
+		return nil
+	}
+	if conf := config.Get(); conf != nil && conf.TracerProvider != nil &&
+		len(conf.TracerProvider.Processors) > 0 {
+		processors := newSpanProcessorsFromConfig(ctx, conf.TracerProvider)
+		if len(processors) == 0 {
+			log.Fatalf("No valid span processor configured")
+		}
+		spanProcessors = withTailSampling(processors)
+		return spanProcessors
+	}
 
 	exporterNames := parseExporterNames(os.Getenv(trace_exporter), "otlp")
 	var processors []trace.SpanProcessor
@@ -128,9 +157,9 @@ func newSpanProcessors(ctx context.Context) []trace.SpanProcessor {
 		spanExporters = append(spanExporters, exporter)
 
 		if name == "console" {
-			processors = append(processors, trace.NewSimpleSpanProcessor(exporter))
+			processors = append(processors, newSimpleSpanProcessor(exporter))
 		} else {
-			processor := trace.NewBatchSpanProcessor(exporter)
+			processor := newBatchSpanProcessor(exporter)
 			processors = append(processors, processor)
 		}
 	}
@@ -139,8 +168,26 @@ func newSpanProcessors(ctx context.Context) []trace.SpanProcessor {
 		log.Fatalf("No valid trace exporter configured")
 	}
 
-	spanProcessors = processors
-	return processors
+	spanProcessors = withTailSampling(processors)
+	return spanProcessors
+}
+
+// withTailSampling puts the tail sampling processor in front of the processors
+// if it's selected by OTEL_EXPERIMENTAL_TRACES_PROCESSOR, so that only the
+// kept traces reach the exporters
+func withTailSampling(processors []trace.SpanProcessor) []trace.SpanProcessor {
+	name := strings.ToLower(strings.TrimSpace(os.Getenv(trace_processor)))
+	switch name {
+	case "":
+		return processors
+	case "tail_sampling":
+		return []trace.SpanProcessor{
+			tailsampling.NewProcessor(tailsampling.ConfigFromEnv(), processors...),
+		}
+	default:
+		log.Printf("Unsupported %s value: %s, ignored", trace_processor, name)
+		return processors
+	}
 }
 
 func parseExporterNames(envValue, defaultValue string) []string {
@@ -181,29 +228,23 @@ func createTraceExporter(ctx context.Context, name string) (trace.SpanExporter,
 }
 
 func newSpanSampler() trace.Sampler {
-	samplerStr := os.Getenv(trace_sampler)
-	samplerStr = strings.TrimSpace(samplerStr)
-	if samplerStr == "" {
-		return trace.ParentBased(trace.AlwaysSample())
-	}
-
-	sampler, err := strconv.ParseFloat(samplerStr, 64)
-	if err != nil {
-		log.Printf("Invalid OTEL_TRACE_SAMPLER value: %s, fallback to parent based sampler", samplerStr)
-		return trace.ParentBased(trace.AlwaysSample())
-	}
-
-	if sampler <= 0 {
-		return trace.NeverSample()
-	} else if sampler >= 1 {
-		return trace.AlwaysSample()
-	} else {
-		return trace.ParentBased(trace.TraceIDRatioBased(sampler))
+	if conf := config.Get(); conf != nil {
+		if conf.Disabled {
+			return trace.NeverSample()
+		}
+		if conf.TracerProvider != nil && conf.TracerProvider.Sampler != nil {
+			return sampler.FromConfig(conf.TracerProvider.Sampler)
+		}
 	}
+	return sampler.FromEnv()
 }
 
 func getTemporalitySelector() metric.TemporalitySelector {
-	pref := strings.ToLower(strings.TrimSpace(os.Getenv(metrics_temporality_preference)))
+	return parseTemporalitySelector(os.Getenv(metrics_temporality_preference))
+}
+
+func parseTemporalitySelector(pref string) metric.TemporalitySelector {
+	pref = strings.ToLower(strings.TrimSpace(pref))
 	
 	switch pref {
 	case "cumulative":
@@ -258,6 +299,9 @@ func lowMemoryTemporalitySelector(ik metric.InstrumentKind) metricdata.Temporali
 }
 
 func initOpenTelemetry(ctx context.Context) error {
+	if _, err := config.Load(); err != nil {
+		log.Printf("Failed to load config file, fallback to environment variables: %v", err)
+	}
 	processors := newSpanProcessors(ctx)
 	spanSampler = newSpanSampler()
 
@@ -268,13 +312,64 @@ func initOpenTelemetry(ctx context.Context) error {
 		}
 	}
 	options = append(options, trace.WithSampler(spanSampler))
+	if conf := config.Get(); conf != nil {
+		if limits, ok := newSpanLimitsFromConfig(conf); ok {
+			options = append(options, trace.WithRawSpanLimits(limits))
+		}
+	}
 
 	traceProvider = trace.NewTracerProvider(options...)
 	otel.SetTracerProvider(traceProvider)
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	initLogs(ctx)
 	return initMetrics()
 }
 
+// initLogs sets up the logger provider the log rules bridge the records to,
+// logs are not exported unless OTEL_LOGS_EXPORTER is specified
+func initLogs(ctx context.Context) {
+	if conf := config.Get(); conf != nil && conf.Disabled {
+		return
+	}
+	var options []sdklog.LoggerProviderOption
+	for _, name := range parseExporterNames(os.Getenv(logs_exporter), "none") {
+		if name == "none" {
+			continue
+		}
+		exporter, err := createLogExporter(ctx, name)
+		if err != nil {
+			log.Printf("Failed to create log exporter %s: %v", name, err)
+			continue
+		}
+		if name == "console" {
+			options = append(options,
+				sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+		} else {
+			options = append(options,
+				sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+		}
+	}
+	if len(options) == 0 {
+		return
+	}
+	loggerProvider = sdklog.NewLoggerProvider(options...)
+	logbridge.SetLoggerProvider(loggerProvider)
+}
+
+func createLogExporter(ctx context.Context, name string) (sdklog.Exporter, error) {
+	switch name {
+	case "console":
+		return stdoutlog.New()
+	case "otlp":
+		if os.Getenv(report_protocol) == "grpc" || os.Getenv(logs_report_protocol) == "grpc" {
+			return otlploggrpc.New(ctx)
+		}
+		return otlploghttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown log exporter: %s", name)
+	}
+}
+
 func initMetrics() error {
 	ctx := context.Background()
 
@@ -282,6 +377,20 @@ func initMetrics() error {
 		metricsProvider = metric.NewMeterProvider(
 			metric.WithReader(testaccess.ManualReader),
 		)
+	} else if conf := config.Get(); conf != nil && conf.Disabled {
+		metricsProvider = noop.NewMeterProvider()
+	} else if conf != nil && conf.MeterProvider != nil &&
+		len(conf.MeterProvider.Readers) > 0 {
+		readers := newMetricReadersFromConfig(ctx, conf.MeterProvider)
+		if len(readers) == 0 {
+			metricsProvider = noop.NewMeterProvider()
+		} else {
+			var options []metric.Option
+			for _, reader := range readers {
+				options = append(options, metric.WithReader(reader))
+			}
+			metricsProvider = metric.NewMeterProvider(options...)
+		}
 	} else {
 		exporterNames := parseExporterNames(os.Getenv(metrics_exporter), "otlp")
 		var readers []metric.Reader
@@ -303,7 +412,11 @@ func initMetrics() error {
 			readers = append(readers, reader)
 
 			if name == "prometheus" {
-				go serveMetrics()
+				port := os.Getenv(prometheus_exporter_port)
+				if port == "" {
+					port = default_prometheus_exporter_port
+				}
+				go serveMetrics(":" + port)
 			}
 		}
 
@@ -331,6 +444,7 @@ func initMetrics() error {
 	ai.InitAIMetrics(m)
 	experimental.InitNacosExperimentalMetrics(m)
 	experimental.InitSentinelExperimentalMetrics(m)
+	initSelfTelemetry(metricsProvider)
 	return otelruntime.Start(otelruntime.WithMeterProvider(metricsProvider))
 }
 
@@ -368,19 +482,15 @@ func createMetricReader(ctx context.Context, name string) (metric.Reader, metric
 	}
 }
 
-func serveMetrics() {
+func serveMetrics(addr string) {
 	http2.Handle("/metrics", promhttp.HandlerFor(
 		prometheus_client.DefaultGatherer,
 		promhttp.HandlerOpts{
 			EnableOpenMetrics: true,
 		},
 	))
-	port := os.Getenv(prometheus_exporter_port)
-	if port == "" {
-		port = default_prometheus_exporter_port
-	}
-	log.Printf("serving serveMetrics at localhost:%s/metrics", port)
-	err := http2.ListenAndServe(fmt.Sprintf(":%s", port), nil)
+	log.Printf("serving serveMetrics at %s/metrics", addr)
+	err := http2.ListenAndServe(addr, nil)
 	if err != nil {
 		fmt.Printf("error serving serveMetrics: %v", err)
 		return
@@ -388,6 +498,9 @@ func serveMetrics() {
 }
 
 func gracefullyShutdown(ctx context.Context) {
+	if adminServer != nil {
+		_ = adminServer.Close()
+	}
 	if metricsProvider != nil {
 		mp, ok := metricsProvider.(*metric.MeterProvider)
 		if ok {
@@ -397,6 +510,9 @@ func gracefullyShutdown(ctx context.Context) {
 	if traceProvider != nil {
 		_ = traceProvider.Shutdown(ctx)
 	}
+	if loggerProvider != nil {
+		_ = loggerProvider.Shutdown(ctx)
+	}
 	for _, exporter := range spanExporters {
 		if exporter != nil {
 			_ = exporter.Shutdown(ctx)
@@ -15,6 +15,9 @@
 package amqp091
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/message"
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
@@ -73,9 +76,27 @@ func (RabbitMQGetter) GetBatchMessageCount(request RabbitRequest, response any)
 	return 0
 }
 
+// GetMessageHeader returns the value of the header, the name is matched
+// case-insensitively since the captured header names are lower-cased
 func (RabbitMQGetter) GetMessageHeader(request RabbitRequest, name string) []string {
-	return []string{}
+	var headerValues []string
+	for key, value := range request.headers {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			headerValues = append(headerValues, v)
+		case []byte:
+			headerValues = append(headerValues, string(v))
+		case nil:
+		default:
+			headerValues = append(headerValues, fmt.Sprint(v))
+		}
+	}
+	return headerValues
 }
+
 func (RabbitMQGetter) GetDestinationPartitionId(request RabbitRequest) string {
 	return ""
 }
@@ -17,32 +17,31 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/db"
 	"github.com/xwb1989/sqlparser"
 )
 
-func extractSQLMetadata(request databaseSqlRequest) {
+func extractSQLMetadata(request databaseSqlRequest) SQLMeta {
 	sql := request.sql
 
-	if sqlCache.Contains(sql) {
-		return
+	if meta, found := sqlCache.Get(sql); found {
+		return meta
 	}
 
-	collection := extractCollection(sql)
+	summary := db.SummarizeSql(sql)
+	if summary.Operation == "" {
+		// Not a SQL statement, e.g. ping
+		summary.Operation = request.opType
+	}
 	sqlMeta := SQLMeta{
 		stmt:       request.sql,
-		operation:  request.opType,
-		collection: collection,
+		sanitized:  db.SanitizeStatement(databaseSqlAttrsGetter{}.GetSystem(request), sql),
+		operation:  summary.Operation,
+		collection: summary.Collection,
 	}
 
 	sqlCache.Add(sql, sqlMeta)
-}
-
-func getCollection(sql string) string {
-	if meta, found := sqlCache.Get(sql); found {
-		return meta.collection
-	}
-	// Attempt to retrieve the collection again.
-	return extractCollection(sql)
+	return sqlMeta
 }
 
 func getParams(sql string) []any {
@@ -66,45 +65,6 @@ func getParams(sql string) []any {
 	return params
 }
 
-func extractCollection(query string) string {
-	stmt, err := sqlparser.Parse(query)
-	if err != nil {
-		return ""
-	}
-	// Only support DML currently
-	switch stmt := stmt.(type) {
-	case *sqlparser.Select:
-		return getTableName(stmt.From)
-	case *sqlparser.Update:
-		return getTableName(stmt.TableExprs)
-	case *sqlparser.Insert:
-		return stmt.Table.Name.String()
-	case *sqlparser.Delete:
-		return getTableName(stmt.TableExprs)
-	default:
-		return ""
-	}
-}
-
-func getTableName(node sqlparser.SQLNode) string {
-	switch n := node.(type) {
-	case sqlparser.TableName:
-		return n.Name.String()
-	case sqlparser.TableExprs:
-		for _, expr := range n {
-			aliasedExpr, ok := expr.(*sqlparser.AliasedTableExpr)
-			if !ok {
-				continue
-			}
-			tableName, ok := aliasedExpr.Expr.(sqlparser.TableName)
-			if ok {
-				return tableName.Name.String()
-			}
-		}
-	}
-	return ""
-}
-
 // Extract SQL parameters
 func extractSQLParams(query string) (map[string]string, error) {
 	stmt, err := sqlparser.Parse(query)
@@ -26,21 +26,20 @@ type databaseSqlAttrsGetter struct {
 }
 
 func (d databaseSqlAttrsGetter) GetSystem(request databaseSqlRequest) string {
-	switch request.driverName {
-	case "mysql":
-		return "mysql"
-	case "postgres":
-		fallthrough
-	case "postgresql":
-		return "postgresql"
-	}
-	return "database"
+	return lookupDSN(request.driverName, request.dsn).system
 }
 
 func (d databaseSqlAttrsGetter) GetServerAddress(request databaseSqlRequest) string {
+	if host := lookupDSN(request.driverName, request.dsn).host; host != "" {
+		return host
+	}
 	return request.endpoint
 }
 
+func (d databaseSqlAttrsGetter) GetServerPort(request databaseSqlRequest) int {
+	return lookupDSN(request.driverName, request.dsn).port
+}
+
 func (d databaseSqlAttrsGetter) GetStatement(request databaseSqlRequest) string {
 	// Fetch metadata along with the SQL
 	// Retrieve db collection only, not the params which is an experimental feature that will introduce some overhead.
@@ -48,12 +47,18 @@ func (d databaseSqlAttrsGetter) GetStatement(request databaseSqlRequest) string
 	return request.sql
 }
 
+// GetSanitizedStatement returns the sanitized statement cached along with the
+// other metadata of the SQL, so that each SQL is sanitized only once
+func (d databaseSqlAttrsGetter) GetSanitizedStatement(request databaseSqlRequest) string {
+	return extractSQLMetadata(request).sanitized
+}
+
 func (d databaseSqlAttrsGetter) GetOperation(request databaseSqlRequest) string {
-	return request.opType
+	return extractSQLMetadata(request).operation
 }
 
 func (d databaseSqlAttrsGetter) GetCollection(request databaseSqlRequest) string {
-	return getCollection(request.sql)
+	return extractSQLMetadata(request).collection
 }
 
 func (d databaseSqlAttrsGetter) GetParameters(request databaseSqlRequest) []any {
@@ -67,7 +72,7 @@ func (d databaseSqlAttrsGetter) GetParameters(request databaseSqlRequest) []any
 }
 
 func (d databaseSqlAttrsGetter) GetDbNamespace(request databaseSqlRequest) string {
-	return ""
+	return lookupDSN(request.driverName, request.dsn).namespace
 }
 
 func (d databaseSqlAttrsGetter) GetBatchSize(request databaseSqlRequest) int {
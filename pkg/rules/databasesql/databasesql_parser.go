@@ -15,52 +15,472 @@
 package databasesql
 
 import (
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"log"
+	"net"
 	nurl "net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// The kinds of the drivers, each kind has its own DSN format
+const (
+	kindMySQL      = "mysql"
+	kindTiDB       = "tidb"
+	kindPostgres   = "postgres"
+	kindSQLite     = "sqlite"
+	kindSQLServer  = "sqlserver"
+	kindClickHouse = "clickhouse"
+	kindOracle     = "oracle"
+	kindSnowflake  = "snowflake"
 )
 
-func parseDSN(driverName, dsn string) (addr string, err error) {
-	// TODO: need a more delegate DFA
-	switch driverName {
-	case "mysql":
-		return parseMySQL(dsn)
-	case "postgres":
-		fallthrough
-	case "postgresql":
-		return parsePostgres(dsn)
+// envDriverAliases maps the custom driver names to the driver kinds, e.g.
+// "mysql-wrapped=mysql,pg=postgres", for the drivers registered under custom
+// names that can not be recognized by their packages either
+const envDriverAliases = "OTEL_INSTRUMENTATION_DATABASESQL_DRIVER_ALIASES"
+
+var driverAliases = map[string]string{
+	"mysql":            kindMySQL,
+	"tidb":             kindTiDB,
+	"postgres":         kindPostgres,
+	"postgresql":       kindPostgres,
+	"pgx":              kindPostgres,
+	"pgx/v4":           kindPostgres,
+	"pgx/v5":           kindPostgres,
+	"cloudsqlpostgres": kindPostgres,
+	"sqlite":           kindSQLite,
+	"sqlite3":          kindSQLite,
+	"sqlserver":        kindSQLServer,
+	"mssql":            kindSQLServer,
+	"azuresql":         kindSQLServer,
+	"clickhouse":       kindClickHouse,
+	"chhttp":           kindClickHouse,
+	"oracle":           kindOracle,
+	"godror":           kindOracle,
+	"snowflake":        kindSnowflake,
+}
+
+// driverPackages recognizes the kinds of the drivers registered under custom
+// names by the packages of the driver types
+var driverPackages = []struct {
+	prefix string
+	kind   string
+}{
+	{"github.com/go-sql-driver/mysql", kindMySQL},
+	{"github.com/lib/pq", kindPostgres},
+	{"github.com/jackc/pgx", kindPostgres},
+	{"github.com/mattn/go-sqlite3", kindSQLite},
+	{"modernc.org/sqlite", kindSQLite},
+	{"github.com/glebarez/go-sqlite", kindSQLite},
+	{"github.com/microsoft/go-mssqldb", kindSQLServer},
+	{"github.com/denisenkom/go-mssqldb", kindSQLServer},
+	{"github.com/ClickHouse/clickhouse-go", kindClickHouse},
+	{"github.com/sijms/go-ora", kindOracle},
+	{"github.com/godror/godror", kindOracle},
+	{"github.com/snowflakedb/gosnowflake", kindSnowflake},
+}
+
+// The db.system.name of the driver kinds
+var driverSystems = map[string]string{
+	kindMySQL:      "mysql",
+	kindTiDB:       "tidb",
+	kindPostgres:   "postgresql",
+	kindSQLite:     "sqlite",
+	kindSQLServer:  "microsoft.sql_server",
+	kindClickHouse: "clickhouse",
+	kindOracle:     "oracle.db",
+	kindSnowflake:  "snowflake",
+}
+
+// The default ports of the driver kinds, used if the DSN omits the port
+var defaultPorts = map[string]int{
+	kindMySQL:      3306,
+	kindTiDB:       4000,
+	kindPostgres:   5432,
+	kindSQLServer:  1433,
+	kindClickHouse: 9000,
+	kindOracle:     1521,
+	kindSnowflake:  443,
+}
+
+func init() {
+	parseDriverAliases(os.Getenv(envDriverAliases))
+}
+
+// parseDriverAliases adds the custom driver names of the comma separated
+// name=kind pairs to the driver aliases, the kind can be any known driver name
+func parseDriverAliases(aliases string) {
+	for _, pair := range strings.Split(aliases, ",") {
+		name, kind, ok := strings.Cut(pair, "=")
+		name, kind = strings.TrimSpace(name), strings.TrimSpace(kind)
+		if !ok || name == "" {
+			continue
+		}
+		if k, known := driverAliases[kind]; known {
+			driverAliases[name] = k
+		} else {
+			log.Printf("unknown kind %q of database driver %q", kind, name)
+		}
+	}
+}
+
+// dsnInfo is the connection information parsed from the DSN
+type dsnInfo struct {
+	system    string
+	host      string
+	port      int
+	namespace string
+}
+
+// endpoint returns the address in the form of host:port, as recorded by the
+// injected Endpoint fields
+func (info dsnInfo) endpoint() string {
+	if info.host == "" || info.port == 0 {
+		return info.host
 	}
+	return net.JoinHostPort(info.host, strconv.Itoa(info.port))
+}
+
+const dsnCacheSize = 128
+
+// The DSNs are parsed when the databases are opened, and cached so that the
+// spans of the statements don't parse them again
+var dsnCache, _ = lru.New[string, dsnInfo](dsnCacheSize)
 
-	return "", errors.New("invalid DSN")
+func dsnCacheKey(driverName, dsn string) string {
+	return driverName + "\x00" + dsn
 }
 
-func parsePostgres(url string) (addr string, err error) {
-	u, err := nurl.Parse(url)
+// driverKind resolves the kind of the driver by the driver name, and by the
+// package of the driver type if the driver is registered under a custom name
+func driverKind(driverName string, drv driver.Driver) string {
+	if kind, ok := driverAliases[driverName]; ok {
+		return kind
+	}
+	if drv == nil {
+		return ""
+	}
+	t := reflect.TypeOf(drv)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return driverPackageKind(t.PkgPath())
+}
+
+// driverPackageKind returns the kind of the driver defined in the package
+func driverPackageKind(pkgPath string) string {
+	for _, pkg := range driverPackages {
+		if strings.HasPrefix(pkgPath, pkg.prefix) {
+			return pkg.kind
+		}
+	}
+	return ""
+}
+
+// resolveDSN parses the DSN of the opened database and caches the result, the
+// DSNs of the unknown drivers are left unparsed silently
+func resolveDSN(driverName, dsn string, drv driver.Driver) dsnInfo {
+	kind := driverKind(driverName, drv)
+	info, err := parseDSN(kind, dsn)
+	if err != nil && kind != "" {
+		log.Printf("failed to parse dsn of driver %s: %v", driverName, err)
+	}
+	dsnCache.Add(dsnCacheKey(driverName, dsn), info)
+	return info
+}
+
+// lookupDSN returns the cached connection information, the DSN is parsed by the
+// driver name if it's not cached, e.g. it's evicted or the database is not
+// opened by sql.Open
+func lookupDSN(driverName, dsn string) dsnInfo {
+	key := dsnCacheKey(driverName, dsn)
+	if info, ok := dsnCache.Get(key); ok {
+		return info
+	}
+	info, _ := parseDSN(driverKind(driverName, nil), dsn)
+	dsnCache.Add(key, info)
+	return info
+}
+
+func parseDSN(kind, dsn string) (info dsnInfo, err error) {
+	switch kind {
+	case kindMySQL, kindTiDB:
+		info, err = parseMySQL(dsn)
+	case kindPostgres:
+		info, err = parsePostgres(dsn)
+	case kindSQLite:
+		info, err = parseSQLite(dsn)
+	case kindSQLServer:
+		info, err = parseSQLServer(dsn)
+	case kindClickHouse:
+		info, err = parseClickHouse(dsn)
+	case kindOracle:
+		info, err = parseOracle(dsn)
+	case kindSnowflake:
+		info, err = parseSnowflake(dsn)
+	default:
+		return dsnInfo{system: "database"}, errors.New("unknown driver")
+	}
+	info.system = driverSystems[kind]
+	if info.host != "" && info.port == 0 && !strings.HasPrefix(info.host, "/") {
+		info.port = defaultPorts[kind]
+	}
+	return info, err
+}
+
+// splitHostPort splits the address into the host and the port, the port is 0
+// if it's absent
+func splitHostPort(addr string) (string, int) {
+	host, port, err := net.SplitHostPort(addr)
 	if err != nil {
-		return "", err
+		return strings.Trim(addr, "[]"), 0
 	}
+	p, _ := strconv.Atoi(port)
+	return host, p
+}
 
-	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
-		return "", fmt.Errorf("invalid connection protocol: %s", u.Scheme)
+// firstHost returns the first one of the comma separated hosts
+func firstHost(hosts string) string {
+	host, _, _ := strings.Cut(hosts, ",")
+	return strings.TrimSpace(host)
+}
+
+// parseKeyValues parses the key=value pairs separated by sep, the values may
+// be quoted by single or double quotes
+func parseKeyValues(dsn string, sep byte) map[string]string {
+	kvs := map[string]string{}
+	for i, n := 0, len(dsn); i < n; {
+		for i < n && (dsn[i] == sep || dsn[i] == ' ') {
+			i++
+		}
+		j := strings.IndexByte(dsn[i:], '=')
+		if j < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(dsn[i : i+j]))
+		i += j + 1
+		for i < n && dsn[i] == ' ' {
+			i++
+		}
+		var value strings.Builder
+		if i < n && (dsn[i] == '\'' || dsn[i] == '"') {
+			quote := dsn[i]
+			for i++; i < n; i++ {
+				if dsn[i] == '\\' && i+1 < n {
+					i++
+				} else if dsn[i] == quote {
+					i++
+					break
+				}
+				value.WriteByte(dsn[i])
+			}
+		} else {
+			for ; i < n && dsn[i] != sep && !(sep == ' ' && dsn[i] == '\t'); i++ {
+				value.WriteByte(dsn[i])
+			}
+		}
+		kvs[key] = strings.TrimSpace(value.String())
+	}
+	return kvs
+}
+
+// parseMySQL parses the DSN of go-sql-driver/mysql, i.e.
+// [user[:password]@][net[(addr)]]/dbname[?param1=value1&paramN=valueN]
+func parseMySQL(dsn string) (dsnInfo, error) {
+	slash := strings.LastIndexByte(dsn, '/')
+	if slash < 0 {
+		return dsnInfo{}, errors.New("invalid MySQL DSN")
+	}
+	info := dsnInfo{}
+	info.namespace, _, _ = strings.Cut(dsn[slash+1:], "?")
+	netAddr := dsn[:slash]
+	if at := strings.LastIndexByte(netAddr, '@'); at >= 0 {
+		netAddr = netAddr[at+1:]
+	}
+	network, addr := netAddr, ""
+	if i := strings.IndexByte(netAddr, '('); i >= 0 && strings.HasSuffix(netAddr, ")") {
+		network, addr = netAddr[:i], netAddr[i+1:len(netAddr)-1]
 	}
+	switch {
+	case network == "unix":
+		info.host = addr
+	case addr == "":
+		info.host = "127.0.0.1"
+	default:
+		info.host, info.port = splitHostPort(addr)
+	}
+	return info, nil
+}
+
+// parsePostgres parses both the URL and the key=value DSN of lib/pq and pgx
+func parsePostgres(dsn string) (dsnInfo, error) {
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		kvs := parseKeyValues(dsn, ' ')
+		info := dsnInfo{host: firstHost(kvs["host"]), namespace: kvs["dbname"]}
+		info.port, _ = strconv.Atoi(firstHost(kvs["port"]))
+		if info.host == "" {
+			info.host = "localhost"
+		}
+		return info, nil
+	}
+	// The URL may contain multiple hosts, which can not be parsed by url.Parse
+	rest := dsn[strings.Index(dsn, "://")+3:]
+	end := strings.IndexAny(rest, "/?")
+	if end < 0 {
+		end = len(rest)
+	}
+	hosts := rest[:end]
+	if at := strings.LastIndexByte(hosts, '@'); at >= 0 {
+		hosts = hosts[at+1:]
+	}
+	if strings.Contains(hosts, ",") {
+		dsn = strings.Replace(dsn, hosts, firstHost(hosts), 1)
+	}
+	u, err := nurl.Parse(dsn)
+	if err != nil {
+		return dsnInfo{}, err
+	}
+	info := dsnInfo{host: u.Hostname(), namespace: strings.TrimPrefix(u.Path, "/")}
+	info.port, _ = strconv.Atoi(u.Port())
+	if info.host == "" {
+		info.host = firstHost(u.Query().Get("host"))
+	}
+	if info.host == "" {
+		info.host = "localhost"
+	}
+	if info.namespace == "" {
+		info.namespace = u.Query().Get("dbname")
+	}
+	return info, nil
+}
 
-	return u.Host + ":" + u.Port(), nil
+// parseSQLite takes the database file as the namespace, there's no server
+func parseSQLite(dsn string) (dsnInfo, error) {
+	file, _, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	if file == "" {
+		return dsnInfo{}, errors.New("invalid SQLite DSN")
+	}
+	return dsnInfo{namespace: file}, nil
 }
 
-func parseMySQL(dsn string) (addr string, err error) {
-	n := len(dsn)
-	i, j := -1, -1
-	for k := 0; k < n; k++ {
-		if dsn[k] == '(' {
-			i = k
+// parseSQLServer parses the URL, ADO and ODBC DSN of go-mssqldb
+func parseSQLServer(dsn string) (dsnInfo, error) {
+	if strings.HasPrefix(dsn, "sqlserver://") {
+		u, err := nurl.Parse(dsn)
+		if err != nil {
+			return dsnInfo{}, err
 		}
-		if dsn[k] == ')' {
-			j = k
+		info := dsnInfo{host: u.Hostname(), namespace: u.Query().Get("database")}
+		info.port, _ = strconv.Atoi(u.Port())
+		return info, nil
+	}
+	kvs := parseKeyValues(strings.TrimPrefix(dsn, "odbc:"), ';')
+	server := ""
+	for _, key := range []string{"server", "data source", "address", "addr", "network address"} {
+		if server = kvs[key]; server != "" {
 			break
 		}
 	}
-	if i >= 0 && j > i {
-		return dsn[i+1 : j], nil
+	if server == "" {
+		return dsnInfo{}, errors.New("invalid SQL Server DSN")
+	}
+	server = strings.TrimPrefix(server, "tcp:")
+	// The server is in the form of host[\instance][,port]
+	server, port, _ := strings.Cut(server, ",")
+	host, _, _ := strings.Cut(server, `\`)
+	info := dsnInfo{host: strings.TrimSpace(host), namespace: kvs["database"]}
+	if port == "" {
+		port = kvs["port"]
+	}
+	info.port, _ = strconv.Atoi(strings.TrimSpace(port))
+	if info.namespace == "" {
+		info.namespace = kvs["initial catalog"]
+	}
+	return info, nil
+}
+
+// parseClickHouse parses the DSN of clickhouse-go, the database is either the
+// path or the database parameter
+func parseClickHouse(dsn string) (dsnInfo, error) {
+	u, err := nurl.Parse(dsn)
+	if err != nil {
+		return dsnInfo{}, err
+	}
+	if u.Host == "" {
+		return dsnInfo{}, fmt.Errorf("invalid connection protocol: %s", u.Scheme)
+	}
+	info := dsnInfo{namespace: strings.TrimPrefix(u.Path, "/")}
+	info.host, info.port = splitHostPort(firstHost(u.Host))
+	if info.namespace == "" {
+		info.namespace = u.Query().Get("database")
+	}
+	if info.port == 0 {
+		switch u.Scheme {
+		case "http":
+			info.port = 8123
+		case "https":
+			info.port = 8443
+		}
+	}
+	return info, nil
+}
+
+// parseOracle parses the URL DSN of go-ora, and the easy connect strings of
+// godror, i.e. user/password@host:port/service or connectString="..."
+func parseOracle(dsn string) (dsnInfo, error) {
+	if strings.HasPrefix(dsn, "oracle://") {
+		u, err := nurl.Parse(dsn)
+		if err != nil {
+			return dsnInfo{}, err
+		}
+		info := dsnInfo{host: u.Hostname(), namespace: strings.TrimPrefix(u.Path, "/")}
+		info.port, _ = strconv.Atoi(u.Port())
+		return info, nil
+	}
+	connect := dsn
+	if kvs := parseKeyValues(dsn, ' '); kvs["connectstring"] != "" {
+		connect = kvs["connectstring"]
+	} else if at := strings.LastIndexByte(dsn, '@'); at >= 0 {
+		connect = dsn[at+1:]
+	}
+	connect = strings.TrimPrefix(connect, "//")
+	if connect == "" || strings.HasPrefix(connect, "(") {
+		// The TNS descriptors are not supported
+		return dsnInfo{}, errors.New("invalid Oracle DSN")
+	}
+	addr, service, _ := strings.Cut(connect, "/")
+	info := dsnInfo{namespace: service}
+	info.namespace, _, _ = strings.Cut(info.namespace, "?")
+	info.host, info.port = splitHostPort(addr)
+	return info, nil
+}
+
+// parseSnowflake parses the DSN of gosnowflake, i.e.
+// user[:password]@account/database[/schema][?param1=value1&paramN=valueN] or
+// user[:password]@host:port/database[/schema]?account=account
+func parseSnowflake(dsn string) (dsnInfo, error) {
+	at := strings.LastIndexByte(dsn, '@')
+	if at < 0 {
+		return dsnInfo{}, errors.New("invalid Snowflake DSN")
+	}
+	rest, query, _ := strings.Cut(dsn[at+1:], "?")
+	addr, path, _ := strings.Cut(rest, "/")
+	info := dsnInfo{}
+	info.namespace, _, _ = strings.Cut(path, "/")
+	info.host, info.port = splitHostPort(addr)
+	if info.port == 0 && !strings.HasSuffix(info.host, ".snowflakecomputing.com") {
+		// The host is the account identifier, optionally with the region
+		info.host += ".snowflakecomputing.com"
+	}
+	if params, err := nurl.ParseQuery(query); err == nil && params.Get("host") != "" {
+		info.host = params.Get("host")
 	}
-	return "", errors.New("invalid MySQL DSN")
+	return info, nil
 }
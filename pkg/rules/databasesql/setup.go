@@ -24,19 +24,14 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 )
 
 var databaseSqlInstrumenter = BuildDatabaseSqlOtelInstrumenter()
 
-type dbSqlInnerEnabler struct {
-	enabled bool
-}
-
-func (d dbSqlInnerEnabler) Enable() bool {
-	return d.enabled
-}
-
-var dbSqlEnabler = dbSqlInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_DATABASESQL_ENABLED") != "false"}
+var dbSqlEnabler = instrumenter.RegisterInstrumentEnabler(utils.DATABASE_SQL_SCOPE_NAME, "databasesql",
+	os.Getenv("OTEL_INSTRUMENTATION_DATABASESQL_ENABLED") != "false")
 
 const (
 	cacheUpperBound = 1024
@@ -57,22 +52,14 @@ func beforeOpenInstrumentation(call api.CallContext, driverName, dataSourceName
 	if !dbSqlEnabler.Enable() {
 		return
 	}
-	addr, err := parseDSN(driverName, dataSourceName)
-	if err != nil {
-		log.Printf("failed to parse dsn: %v", err)
-	}
 	call.SetData(map[string]string{
-		"endpoint": addr,
-		"driver":   driverName,
-		"dsn":      dataSourceName,
+		"driver": driverName,
+		"dsn":    dataSourceName,
 	})
 }
 
 //go:linkname afterOpenInstrumentation database/sql.afterOpenInstrumentation
 func afterOpenInstrumentation(call api.CallContext, db *sql.DB, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	if db == nil {
 		return
 	}
@@ -80,10 +67,6 @@ func afterOpenInstrumentation(call api.CallContext, db *sql.DB, err error) {
 	if !ok {
 		return
 	}
-	endpoint, ok := data["endpoint"]
-	if ok {
-		db.Endpoint = endpoint
-	}
 	driver, ok := data["driver"]
 	if ok {
 		db.DriverName = driver
@@ -92,6 +75,8 @@ func afterOpenInstrumentation(call api.CallContext, db *sql.DB, err error) {
 	if ok {
 		db.DSN = dsn
 	}
+	// The driver is recognized by its type if it's registered under a custom name
+	db.Endpoint = resolveDSN(db.DriverName, db.DSN, db.Driver()).endpoint()
 }
 
 //go:linkname beforePingContextInstrumentation database/sql.beforePingContextInstrumentation
@@ -107,9 +92,6 @@ func beforePingContextInstrumentation(call api.CallContext, db *sql.DB, ctx cont
 
 //go:linkname afterPingContextInstrumentation database/sql.afterPingContextInstrumentation
 func afterPingContextInstrumentation(call api.CallContext, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -131,9 +113,6 @@ func beforePrepareContextInstrumentation(call api.CallContext, db *sql.DB, ctx c
 
 //go:linkname afterPrepareContextInstrumentation database/sql.afterPrepareContextInstrumentation
 func afterPrepareContextInstrumentation(call api.CallContext, stmt *sql.Stmt, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	if stmt == nil {
 		return
 	}
@@ -162,9 +141,6 @@ func beforeExecContextInstrumentation(call api.CallContext, db *sql.DB, ctx cont
 
 //go:linkname afterExecContextInstrumentation database/sql.afterExecContextInstrumentation
 func afterExecContextInstrumentation(call api.CallContext, result sql.Result, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -181,9 +157,6 @@ func beforeQueryContextInstrumentation(call api.CallContext, db *sql.DB, ctx con
 
 //go:linkname afterQueryContextInstrumentation database/sql.afterQueryContextInstrumentation
 func afterQueryContextInstrumentation(call api.CallContext, rows *sql.Rows, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -200,9 +173,6 @@ func beforeTxInstrumentation(call api.CallContext, db *sql.DB, ctx context.Conte
 
 //go:linkname afterTxInstrumentation database/sql.afterTxInstrumentation
 func afterTxInstrumentation(call api.CallContext, tx *sql.Tx, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	if tx == nil {
 		return
 	}
@@ -237,9 +207,6 @@ func beforeConnInstrumentation(call api.CallContext, db *sql.DB, ctx context.Con
 
 //go:linkname afterConnInstrumentation database/sql.afterConnInstrumentation
 func afterConnInstrumentation(call api.CallContext, conn *sql.Conn, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	if conn == nil {
 		return
 	}
@@ -274,9 +241,6 @@ func beforeConnPingContextInstrumentation(call api.CallContext, conn *sql.Conn,
 
 //go:linkname afterConnPingContextInstrumentation database/sql.afterConnPingContextInstrumentation
 func afterConnPingContextInstrumentation(call api.CallContext, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -298,9 +262,6 @@ func beforeConnPrepareContextInstrumentation(call api.CallContext, conn *sql.Con
 
 //go:linkname afterConnPrepareContextInstrumentation database/sql.afterConnPrepareContextInstrumentation
 func afterConnPrepareContextInstrumentation(call api.CallContext, stmt *sql.Stmt, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	if stmt == nil {
 		return
 	}
@@ -329,9 +290,6 @@ func beforeConnExecContextInstrumentation(call api.CallContext, conn *sql.Conn,
 
 //go:linkname afterConnExecContextInstrumentation database/sql.afterConnExecContextInstrumentation
 func afterConnExecContextInstrumentation(call api.CallContext, result sql.Result, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -348,9 +306,6 @@ func beforeConnQueryContextInstrumentation(call api.CallContext, conn *sql.Conn,
 
 //go:linkname afterConnQueryContextInstrumentation database/sql.afterConnQueryContextInstrumentation
 func afterConnQueryContextInstrumentation(call api.CallContext, rows *sql.Rows, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -367,9 +322,6 @@ func beforeConnTxInstrumentation(call api.CallContext, conn *sql.Conn, ctx conte
 
 //go:linkname afterConnTxInstrumentation database/sql.afterConnTxInstrumentation
 func afterConnTxInstrumentation(call api.CallContext, tx *sql.Tx, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -391,9 +343,6 @@ func beforeTxPrepareContextInstrumentation(call api.CallContext, tx *sql.Tx, ctx
 
 //go:linkname afterTxPrepareContextInstrumentation database/sql.afterTxPrepareContextInstrumentation
 func afterTxPrepareContextInstrumentation(call api.CallContext, stmt *sql.Stmt, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	if stmt == nil {
 		return
 	}
@@ -426,9 +375,6 @@ func beforeTxStmtContextInstrumentation(call api.CallContext, tx *sql.Tx, ctx co
 
 //go:linkname afterTxStmtContextInstrumentation database/sql.afterTxStmtContextInstrumentation
 func afterTxStmtContextInstrumentation(call api.CallContext, stmt *sql.Stmt) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	if stmt == nil {
 		return
 	}
@@ -464,9 +410,6 @@ func beforeTxExecContextInstrumentation(call api.CallContext, tx *sql.Tx, ctx co
 
 //go:linkname afterTxExecContextInstrumentation database/sql.afterTxExecContextInstrumentation
 func afterTxExecContextInstrumentation(call api.CallContext, result sql.Result, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -483,9 +426,6 @@ func beforeTxQueryContextInstrumentation(call api.CallContext, tx *sql.Tx, ctx c
 
 //go:linkname afterTxQueryContextInstrumentation database/sql.afterTxQueryContextInstrumentation
 func afterTxQueryContextInstrumentation(call api.CallContext, rows *sql.Rows, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -502,9 +442,6 @@ func beforeTxCommitInstrumentation(call api.CallContext, tx *sql.Tx) {
 
 //go:linkname afterTxCommitInstrumentation database/sql.afterTxCommitInstrumentation
 func afterTxCommitInstrumentation(call api.CallContext, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -521,9 +458,6 @@ func beforeTxRollbackInstrumentation(call api.CallContext, tx *sql.Tx) {
 
 //go:linkname afterTxRollbackInstrumentation database/sql.afterTxRollbackInstrumentation
 func afterTxRollbackInstrumentation(call api.CallContext, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -544,9 +478,6 @@ func beforeStmtExecContextInstrumentation(call api.CallContext, stmt *sql.Stmt,
 
 //go:linkname afterStmtExecContextInstrumentation database/sql.afterStmtExecContextInstrumentation
 func afterStmtExecContextInstrumentation(call api.CallContext, result sql.Result, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 
@@ -567,9 +498,6 @@ func beforeStmtQueryContextInstrumentation(call api.CallContext, stmt *sql.Stmt,
 
 //go:linkname afterStmtQueryContextInstrumentation database/sql.afterStmtQueryContextInstrumentation
 func afterStmtQueryContextInstrumentation(call api.CallContext, rows *sql.Rows, err error) {
-	if !dbSqlEnabler.Enable() {
-		return
-	}
 	instrumentEnd(call, err)
 }
 func instrumentStart(call api.CallContext, ctx context.Context, spanName, query, endpoint, driverName, dsn string, args ...any) {
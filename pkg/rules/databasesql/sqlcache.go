@@ -21,6 +21,7 @@ import (
 
 type SQLMeta struct {
 	stmt       string
+	sanitized  string
 	operation  string
 	collection string
 	params     []any
@@ -35,6 +35,7 @@ func dubboConsumerGracefulShutdownFilterInvokeOnEnter(call api.CallContext, _ in
 		methodName:    invocation.MethodName(),
 		serviceKey:    invoker.GetURL().ServiceKey(),
 		serverAddress: invoker.GetURL().Address(),
+		attachments:   invocation.Attachments(),
 	}
 
 	ctx = dubboClientInstrumenter.Start(ctx, req)
@@ -57,10 +58,10 @@ func dubboConsumerGracefulShutdownFilterInvokeOnEnter(call api.CallContext, _ in
 
 //go:linkname dubboConsumerGracefulShutdownFilterInvokeOnExit dubbo.apache.org/dubbo-go/v3/filter/graceful_shutdown.dubboConsumerGracefulShutdownFilterInvokeOnExit
 func dubboConsumerGracefulShutdownFilterInvokeOnExit(call api.CallContext, res protocol.Result) {
-	if !dubboEnabler.Enable() {
+	data, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-	data := call.GetData().(map[string]interface{})
 	ctx, ok := data["ctx"].(context.Context)
 	if !ok {
 		return
@@ -70,7 +71,9 @@ func dubboConsumerGracefulShutdownFilterInvokeOnExit(call api.CallContext, res p
 		return
 	}
 
-	resp := dubboResponse{}
+	resp := dubboResponse{
+		attachments: res.Attachments(),
+	}
 	if res.Error() != nil {
 		resp.hasError = true
 		resp.errorMsg = res.Error().Error()
@@ -22,6 +22,7 @@ type dubboRequest struct {
 }
 
 type dubboResponse struct {
-	hasError bool
-	errorMsg string
+	hasError    bool
+	errorMsg    string
+	attachments map[string]any
 }
@@ -26,15 +26,8 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-type dubboEnable struct {
-	enable bool
-}
-
-func (d *dubboEnable) Enable() bool {
-	return d.enable
-}
-
-var dubboEnabler = dubboEnable{os.Getenv("OTEL_INSTRUMENTATION_DUBBO_ENABLED") != "false"}
+var dubboEnabler = instrumenter.RegisterInstrumentEnabler(utils.DUBBO_SERVER_SCOPE_NAME, "dubbo",
+	os.Getenv("OTEL_INSTRUMENTATION_DUBBO_ENABLED") != "false")
 
 type dubboAttrsGetter struct{}
 
@@ -54,6 +47,14 @@ func (g dubboAttrsGetter) GetServerAddress(request dubboRequest) string {
 	return request.serverAddress
 }
 
+func (g dubboAttrsGetter) GetRequestMetadata(request dubboRequest, name string) []string {
+	return attachmentValues(request.attachments, name)
+}
+
+func (g dubboAttrsGetter) GetResponseMetadata(request dubboRequest, response dubboResponse, name string) []string {
+	return attachmentValues(response.attachments, name)
+}
+
 type dubboStatusExtractor[REQUEST dubboRequest, RESPONSE dubboResponse] struct{}
 
 func (g dubboStatusExtractor[REQUEST, RESPONSE]) Extract(span trace.Span, request dubboRequest, response dubboResponse, err error) {
@@ -55,11 +55,11 @@ func dubboProviderGracefulShutdownFilterInvokeOnEnter(call api.CallContext, _ in
 
 //go:linkname dubboProviderGracefulShutdownFilterInvokeOnExit dubbo.apache.org/dubbo-go/v3/filter/graceful_shutdown.dubboProviderGracefulShutdownFilterInvokeOnExit
 func dubboProviderGracefulShutdownFilterInvokeOnExit(call api.CallContext, res protocol.Result) {
-	if !dubboEnabler.Enable() {
+
+	data, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-
-	data := call.GetData().(map[string]interface{})
 	ctx, ok := data["ctx"].(context.Context)
 	if !ok {
 		return
@@ -69,7 +69,9 @@ func dubboProviderGracefulShutdownFilterInvokeOnExit(call api.CallContext, res p
 		return
 	}
 
-	resp := dubboResponse{}
+	resp := dubboResponse{
+		attachments: res.Attachments(),
+	}
 	if res.Error() != nil {
 		resp.hasError = true
 		resp.errorMsg = res.Error().Error()
@@ -16,6 +16,8 @@ package dubbo
 
 import (
 	"context"
+	"fmt"
+	"strings"
 )
 
 import (
@@ -71,3 +73,28 @@ func extract(ctx context.Context, metadata map[string]any, propagators propagati
 	})
 	return baggage.FromContext(ctx), trace.SpanContextFromContext(ctx)
 }
+
+// attachmentValues returns the values of the attachment, the name is matched
+// case-insensitively since the captured names are lower-cased
+func attachmentValues(attachments map[string]any, name string) []string {
+	value, ok := attachments[name]
+	if !ok {
+		for key, v := range attachments {
+			if strings.EqualFold(key, name) {
+				value, ok = v, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
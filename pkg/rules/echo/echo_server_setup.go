@@ -19,19 +19,14 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	echo "github.com/labstack/echo/v4"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-type echoInnerEnabler struct {
-	enabled bool
-}
-
-func (e echoInnerEnabler) Enable() bool {
-	return e.enabled
-}
-
-var echoEnabler = echoInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_ECHO_ENABLED") != "false"}
+var echoEnabler = instrumenter.RegisterInstrumentEnabler(utils.ECHO_SCOPE_NAME, "echo",
+	os.Getenv("OTEL_INSTRUMENTATION_ECHO_ENABLED") != "false")
 
 func otelTraceMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
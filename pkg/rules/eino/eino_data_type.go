@@ -17,18 +17,13 @@ package eino
 import (
 	"os"
 
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/cloudwego/eino/schema"
 )
 
-type einoInnerEnabler struct {
-	enabled bool
-}
-
-func (l einoInnerEnabler) Enable() bool {
-	return l.enabled
-}
-
-var einoEnabler = einoInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_EINO_ENABLED") != "false"}
+var einoEnabler = instrumenter.RegisterInstrumentEnabler(utils.EINO_SCOPE_NAME, "eino",
+	os.Getenv("OTEL_INSTRUMENTATION_EINO_ENABLED") != "false")
 
 type (
 	promptRequestKey    struct{}
@@ -22,21 +22,16 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/elastic/elastic-transport-go/v8/elastictransport"
 	elasticsearch "github.com/elastic/go-elasticsearch/v8"
 )
 
 var esInstrumenter = BuildElasticSearchInstrumenter()
 
-type esInnerEnabler struct {
-	enabled bool
-}
-
-func (g esInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var esEnabler = esInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_ELASTICSEARCH_ENABLED") != "false"}
+var esEnabler = instrumenter.RegisterInstrumentEnabler(utils.ELASTICSEARCH_SCOPE_NAME, "elasticsearch",
+	os.Getenv("OTEL_INSTRUMENTATION_ELASTICSEARCH_ENABLED") != "false")
 
 //go:linkname beforeElasticSearchPerform github.com/elastic/go-elasticsearch/v8.beforeElasticSearchPerform
 func beforeElasticSearchPerform(call api.CallContext, client *elasticsearch.BaseClient, request *http.Request) {
@@ -61,11 +56,14 @@ func beforeElasticSearchPerform(call api.CallContext, client *elasticsearch.Base
 
 //go:linkname afterElasticSearchPerform github.com/elastic/go-elasticsearch/v8.afterElasticSearchPerform
 func afterElasticSearchPerform(call api.CallContext, response *http.Response, err error) {
-	if !esEnabler.Enable() {
+	newCtx, ok := call.GetKeyData("ctx").(context.Context)
+	if !ok {
+		return
+	}
+	er, ok := call.GetKeyData("request").(*esRequest)
+	if !ok {
 		return
 	}
-	newCtx := call.GetKeyData("ctx").(context.Context)
-	er := call.GetKeyData("request").(*esRequest)
 	esInstrumenter.End(newCtx, er, response, err)
 }
 
@@ -30,15 +30,8 @@ import (
 
 var emptyFastHttpResponse = fastHttpResponse{}
 
-type fastHttpInnerEnabler struct {
-	enabled bool
-}
-
-func (g fastHttpInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var fastHttpEnabler = fastHttpInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_FASTHTTP_ENABLED") != "false"}
+var fastHttpEnabler = instrumenter.RegisterInstrumentEnabler(utils.FAST_HTTP_SERVER_SCOPE_NAME, "fasthttp",
+	os.Getenv("OTEL_INSTRUMENTATION_FASTHTTP_ENABLED") != "false")
 
 type fastHttpClientAttrsGetter struct {
 }
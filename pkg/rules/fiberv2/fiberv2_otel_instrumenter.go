@@ -31,15 +31,8 @@ import (
 
 var emptyFiberv2Response = fiberv2Response{}
 
-type fiberV2InnerEnabler struct {
-	enabled bool
-}
-
-func (g fiberV2InnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var fiberV2Enabler = fiberV2InnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_FIBERV2_ENABLED") != "false"}
+var fiberV2Enabler = instrumenter.RegisterInstrumentEnabler(utils.FIBER_V2_SERVER_SCOPE_NAME, "fiberv2",
+	os.Getenv("OTEL_INSTRUMENTATION_FIBERV2_ENABLED") != "false")
 
 type fiberv2ServerAttrsGetter struct {
 }
@@ -16,14 +16,10 @@ package gin
 
 import (
 	"os"
-)
-
-type ginInnerEnabler struct {
-	enabled bool
-}
 
-func (g ginInnerEnabler) Enable() bool {
-	return g.enabled
-}
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+)
 
-var ginEnabler = ginInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_GIN_ENABLED") != "false"}
+var ginEnabler = instrumenter.RegisterInstrumentEnabler(utils.GIN_SCOPE_NAME, "gin",
+	os.Getenv("OTEL_INSTRUMENTATION_GIN_ENABLED") != "false")
@@ -15,44 +15,35 @@
 package log
 
 import (
+	"fmt"
 	"os"
+	"time"
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logbridge"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logcorrelation"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+	otellog "go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-type kitlogInnerEnabler struct {
-	enabled bool
-}
-
-func (g kitlogInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var kitlogEnabler = kitlogInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_GOKITLOG_ENABLED") != "false"}
+var kitlogEnabler = instrumenter.RegisterInstrumentEnabler(utils.GOKITLOG_SCOPE_NAME, "gokitlog",
+	os.Getenv("OTEL_INSTRUMENTATION_GOKITLOG_ENABLED") != "false")
 
 //go:linkname logfmtLoggerLogOnEnter github.com/go-kit/log.logfmtLoggerLogOnEnter
 func logfmtLoggerLogOnEnter(call api.CallContext, _ interface{}, keyVals ...interface{}) {
 	if !kitlogEnabler.Enable() {
 		return
 	}
-
-	traceId, spanId := trace.GetTraceAndSpanId()
-	if traceId == "" && spanId == "" {
-		return
+	if logbridge.Enabled() {
+		emitKitLogRecord(keyVals)
 	}
 
-	newKeyVals := make([]interface{}, 0, len(keyVals)+4)
-	newKeyVals = append(newKeyVals, keyVals...)
-	if traceId != "" {
-		newKeyVals = append(newKeyVals, "trace_id", traceId)
+	if newKeyVals := appendCorrelation(keyVals); newKeyVals != nil {
+		call.SetParam(1, newKeyVals)
 	}
-	if spanId != "" {
-		newKeyVals = append(newKeyVals, "span_id", spanId)
-	}
-
-	call.SetParam(1, newKeyVals)
 }
 
 //go:linkname jsonLoggerLogOnEnter github.com/go-kit/log.jsonLoggerLogOnEnter
@@ -60,20 +51,70 @@ func jsonLoggerLogOnEnter(call api.CallContext, _ interface{}, keyVals ...interf
 	if !kitlogEnabler.Enable() {
 		return
 	}
+	if logbridge.Enabled() {
+		emitKitLogRecord(keyVals)
+	}
 
-	traceId, spanId := trace.GetTraceAndSpanId()
-	if traceId == "" && spanId == "" {
-		return
+	if newKeyVals := appendCorrelation(keyVals); newKeyVals != nil {
+		call.SetParam(1, newKeyVals)
 	}
+}
 
-	newKeyVals := make([]interface{}, 0, len(keyVals))
+// appendCorrelation appends the correlation fields absent from the key values,
+// nil is returned if nothing is appended
+func appendCorrelation(keyVals []interface{}) []interface{} {
+	correlation := logcorrelation.SpanFields(trace.SpanFromGLS())
+	if len(correlation) == 0 {
+		return nil
+	}
+	present := make(map[string]bool, len(keyVals)/2)
+	for i := 0; i < len(keyVals); i += 2 {
+		present[fmt.Sprint(keyVals[i])] = true
+	}
+	newKeyVals := make([]interface{}, 0, len(keyVals)+2*len(correlation))
 	newKeyVals = append(newKeyVals, keyVals...)
-	if traceId != "" {
-		newKeyVals = append(newKeyVals, "trace_id", traceId)
+	for _, field := range correlation {
+		if !present[field.Key] {
+			newKeyVals = append(newKeyVals, field.Key, field.Value)
+		}
 	}
-	if spanId != "" {
-		newKeyVals = append(newKeyVals, "span_id", spanId)
+	if len(newKeyVals) == len(keyVals) {
+		return nil
 	}
+	return newKeyVals
+}
+
+var kitLogSeverities = map[string]otellog.Severity{
+	"debug": otellog.SeverityDebug,
+	"info":  otellog.SeverityInfo,
+	"warn":  otellog.SeverityWarn,
+	"error": otellog.SeverityError,
+}
 
-	call.SetParam(1, newKeyVals)
+// emitKitLogRecord bridges the key values to the logs signal, go-kit log has
+// no dedicated message and level, so the conventional "msg" and "level" keys
+// are taken as the body and the severity, and the rest as the attributes
+func emitKitLogRecord(keyVals []interface{}) {
+	var record otellog.Record
+	record.SetTimestamp(time.Now())
+	attrs := make([]otellog.KeyValue, 0, len(keyVals)/2)
+	for i := 0; i < len(keyVals); i += 2 {
+		key := fmt.Sprint(keyVals[i])
+		var value interface{}
+		if i+1 < len(keyVals) {
+			value = keyVals[i+1]
+		}
+		switch key {
+		case "msg":
+			record.SetBody(logbridge.Value(value))
+		case "level":
+			text := fmt.Sprint(value)
+			record.SetSeverity(kitLogSeverities[text])
+			record.SetSeverityText(text)
+		default:
+			attrs = append(attrs, logbridge.KeyValue(key, value))
+		}
+	}
+	record.AddAttributes(attrs...)
+	logbridge.Emit(utils.GOKITLOG_SCOPE_NAME, trace.SpanFromGLS(), record)
 }
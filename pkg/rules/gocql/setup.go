@@ -17,23 +17,16 @@ package gocql
 import (
 	"context"
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/gocql/gocql"
 	"os"
 	"strings"
 	_ "unsafe"
 )
 
-type gocqlInnerEnabler struct {
-	enabled bool
-}
-
-func (g gocqlInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var gocqlEnabler = gocqlInnerEnabler{
-	enabled: os.Getenv("OTEL_INSTRUMENTATION_GOCQL_ENABLED") != "false",
-}
+var gocqlEnabler = instrumenter.RegisterInstrumentEnabler(utils.GOCQL_SCOPE_NAME, "gocql",
+	os.Getenv("OTEL_INSTRUMENTATION_GOCQL_ENABLED") != "false")
 
 var gocqlInstrumenter = BuildGocqlInstrumenter()
 
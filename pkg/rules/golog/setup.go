@@ -18,43 +18,54 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logbridge"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logcorrelation"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+	otellog "go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-type glogInnerEnabler struct {
-	enabled bool
-}
-
-func (g glogInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var glogEnabler = glogInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_GLOG_ENABLED") != "false"}
+var glogEnabler = instrumenter.RegisterInstrumentEnabler(utils.GLOG_SCOPE_NAME, "glog",
+	os.Getenv("OTEL_INSTRUMENTATION_GLOG_ENABLED") != "false")
 
 //go:linkname goLogWriteOnEnter log.goLogWriteOnEnter
 func goLogWriteOnEnter(call api.CallContext, ce *log.Logger, pc uintptr, calldepth int, appendOutput func([]byte) []byte) {
 	if !glogEnabler.Enable() {
 		return
 	}
-	traceId, spanId := trace.GetTraceAndSpanId()
+	correlation := logcorrelation.SpanFields(trace.SpanFromGLS())
 	newAppendOutput := func(bytes []byte) []byte {
 		sb := strings.Builder{}
-		if traceId != "" {
-			sb.WriteString(" trace_id=")
-			sb.WriteString(traceId)
-		}
-		if spanId != "" {
-			sb.WriteString(" span_id=")
-			sb.WriteString(spanId)
+		for _, field := range correlation {
+			sb.WriteString(" ")
+			sb.WriteString(field.Key)
+			sb.WriteString("=")
+			sb.WriteString(field.Value)
 		}
 		bytes = append(bytes, []byte(sb.String())...)
+		start := len(bytes)
 		bytes = appendOutput(bytes)
+		if logbridge.Enabled() {
+			emitGoLogMessage(string(bytes[start:]))
+		}
 		sb.Reset()
 		return bytes
 	}
 	call.SetParam(3, newAppendOutput)
 	return
 }
+
+// emitGoLogMessage bridges the message to the logs signal, the standard log
+// package has neither levels nor structured fields
+func emitGoLogMessage(msg string) {
+	var record otellog.Record
+	record.SetTimestamp(time.Now())
+	record.SetBody(otellog.StringValue(strings.TrimSuffix(msg, "\n")))
+	record.SetSeverity(otellog.SeverityInfo)
+	logbridge.Emit(utils.GLOG_SCOPE_NAME, trace.SpanFromGLS(), record)
+}
@@ -16,14 +16,10 @@ package gomicro
 
 import (
 	"os"
-)
-
-type goMicroInnerEnabler struct {
-	enabled bool
-}
 
-func (g goMicroInnerEnabler) Enable() bool {
-	return g.enabled
-}
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+)
 
-var goMicroEnabler = goMicroInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_GOMICRO_ENABLED") != "false"}
+var goMicroEnabler = instrumenter.RegisterInstrumentEnabler(utils.GOMICRO_SERVER_SCOPE_NAME, "gomicro",
+	os.Getenv("OTEL_INSTRUMENTATION_GOMICRO_ENABLED") != "false")
@@ -17,6 +17,8 @@ package gopg
 import (
 	"context"
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/go-pg/pg/v10"
 	"github.com/go-pg/pg/v10/orm"
 	"os"
@@ -25,17 +27,8 @@ import (
 
 var requestKey = "otel-request"
 
-type gopgInnerEnabler struct {
-	enabled bool
-}
-
-func (g gopgInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var gopgEnabler = gopgInnerEnabler{
-	enabled: os.Getenv("OTEL_INSTRUMENTATION_GOPG_ENABLED") != "false",
-}
+var gopgEnabler = instrumenter.RegisterInstrumentEnabler(utils.GOPG_SCOPE_NAME, "gopg",
+	os.Getenv("OTEL_INSTRUMENTATION_GOPG_ENABLED") != "false")
 
 var gopgInstrumenter = BuildGopgInstrumenter()
 
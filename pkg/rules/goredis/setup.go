@@ -22,6 +22,8 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"go.opentelemetry.io/otel/trace"
 
 	redis "github.com/redis/go-redis/v9"
@@ -29,15 +31,8 @@ import (
 
 var goRedisInstrumenter = BuildGoRedisOtelInstrumenter()
 
-type redisV9InnerEnabler struct {
-	enabled bool
-}
-
-func (r redisV9InnerEnabler) Enable() bool {
-	return r.enabled
-}
-
-var rv9Enabler = redisV9InnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_REDISV9_ENABLED") != "false"}
+var rv9Enabler = instrumenter.RegisterInstrumentEnabler(utils.GO_REDIS_V9_SCOPE_NAME, "redisv9",
+	os.Getenv("OTEL_INSTRUMENTATION_REDISV9_ENABLED") != "false")
 
 var redisV9StartOptions = []trace.SpanStartOption{}
 
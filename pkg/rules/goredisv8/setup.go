@@ -8,21 +8,16 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	redis "github.com/go-redis/redis/v8"
 	"go.opentelemetry.io/otel/trace"
 )
 
 var redisv8Instrumenter = BuildRedisv8Instrumenter()
 
-type redisV8InnerEnabler struct {
-	enabled bool
-}
-
-func (g redisV8InnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var rv8Enabler = redisV8InnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_REDISV8_ENABLED") != "false"}
+var rv8Enabler = instrumenter.RegisterInstrumentEnabler(utils.GO_REDIS_V8_SCOPE_NAME, "redisv8",
+	os.Getenv("OTEL_INSTRUMENTATION_REDISV8_ENABLED") != "false")
 
 var redisV8StartOptions = []trace.SpanStartOption{}
 
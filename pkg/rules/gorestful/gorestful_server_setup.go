@@ -20,19 +20,14 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	restful "github.com/emicklei/go-restful/v3"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-type goRestfulInnerEnabler struct {
-	enabled bool
-}
-
-func (g goRestfulInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var goRestfulEnabler = goRestfulInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_GORESTFUL_ENABLED") != "false"}
+var goRestfulEnabler = instrumenter.RegisterInstrumentEnabler(utils.GORESTFUL_SCOPE_NAME, "gorestful",
+	os.Getenv("OTEL_INSTRUMENTATION_GORESTFUL_ENABLED") != "false")
 
 //go:linkname restContainerAddOnEnter github.com/emicklei/go-restful/v3.restContainerAddOnEnter
 func restContainerAddOnEnter(call api.CallContext, c *restful.Container, service *restful.WebService) {
@@ -20,6 +20,8 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	driver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -28,15 +30,8 @@ import (
 var contextKey = "otel-context"
 var requestKey = "otel-request"
 
-type gormInnerEnabler struct {
-	enabled bool
-}
-
-func (g gormInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var gormEnabler = gormInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_GORM_ENABLED") != "false"}
+var gormEnabler = instrumenter.RegisterInstrumentEnabler(utils.GORM_SCOPE_NAME, "gorm",
+	os.Getenv("OTEL_INSTRUMENTATION_GORM_ENABLED") != "false")
 
 var gormInstrumenter = BuildGormInstrumenter()
 
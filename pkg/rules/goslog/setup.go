@@ -18,34 +18,65 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"time"
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logbridge"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logcorrelation"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+	otellog "go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-type goSlogInnerEnabler struct {
-	enabled bool
-}
-
-func (g goSlogInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var goSlogEnabler = goSlogInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_GOSLOG_ENABLED") != "false"}
+var goSlogEnabler = instrumenter.RegisterInstrumentEnabler(utils.GOSLOG_SCOPE_NAME, "goslog",
+	os.Getenv("OTEL_INSTRUMENTATION_GOSLOG_ENABLED") != "false")
 
 //go:linkname goSlogWriteOnEnter log/slog.goSlogWriteOnEnter
 func goSlogWriteOnEnter(call api.CallContext, ce *slog.Logger, ctx context.Context, level slog.Level, msg string, args ...any) {
 	if !goSlogEnabler.Enable() {
 		return
 	}
-	traceId, spanId := trace.GetTraceAndSpanId()
-	if traceId != "" {
-		msg = msg + " trace_id=" + traceId
+	if ce != nil && logbridge.Enabled() && ce.Enabled(ctx, level) {
+		emitSlogRecord(level, msg, args)
 	}
-	if spanId != "" {
-		msg = msg + " span_id=" + spanId
+	for _, field := range logcorrelation.SpanFields(trace.SpanFromGLS()) {
+		msg = msg + " " + field.Key + "=" + field.Value
 	}
 	call.SetParam(3, msg)
 	return
 }
+
+// emitSlogRecord bridges the record to the logs signal, the severity follows
+// the offsets between the slog levels and the severity numbers, e.g. slog.Info
+// is mapped to SeverityInfo, and the arguments are converted to the attributes
+func emitSlogRecord(level slog.Level, msg string, args []any) {
+	var record otellog.Record
+	record.SetTimestamp(time.Now())
+	record.SetBody(otellog.StringValue(msg))
+	record.SetSeverity(otellog.Severity(level + 9))
+	record.SetSeverityText(level.String())
+	r := slog.NewRecord(time.Time{}, level, msg, 0)
+	r.Add(args...)
+	attrs := make([]otellog.KeyValue, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, slogKeyValue(attr))
+		return true
+	})
+	record.AddAttributes(attrs...)
+	logbridge.Emit(utils.GOSLOG_SCOPE_NAME, trace.SpanFromGLS(), record)
+}
+
+func slogKeyValue(attr slog.Attr) otellog.KeyValue {
+	value := attr.Value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		return logbridge.KeyValue(attr.Key, value.Any())
+	}
+	group := value.Group()
+	kvs := make([]otellog.KeyValue, 0, len(group))
+	for _, attr := range group {
+		kvs = append(kvs, slogKeyValue(attr))
+	}
+	return otellog.Map(attr.Key, kvs...)
+}
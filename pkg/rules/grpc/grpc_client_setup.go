@@ -20,6 +20,7 @@ import (
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
 )
 
@@ -61,9 +62,11 @@ func (h *clientHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) cont
 	if info.FullMethodName == grpcTraceExporterPath || info.FullMethodName == grpcMetricExporterPath {
 		return ctx
 	}
+	md, _ := metadata.FromOutgoingContext(ctx)
 	nCtx := grpcClientInstrument.Start(ctx, grpcRequest{
 		methodName:    info.FullMethodName,
 		serverAddress: h.serverAddr,
+		metadata:      md,
 	})
 	gCtx := gRPCContext{
 		methodName: info.FullMethodName,
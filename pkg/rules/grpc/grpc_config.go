@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.19.0"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
 	"google.golang.org/grpc/status"
 )
@@ -79,17 +80,31 @@ func (c *grpcOtelConfig) handleRPC(ctx context.Context, rs stats.RPCStats, isSer
 				),
 			)
 		}
+	case *stats.InHeader:
+		if !isServer && gCtx != nil {
+			gCtx.responseMetadata = rs.Header
+		}
+	case *stats.OutHeader:
+		if isServer && gCtx != nil {
+			gCtx.responseMetadata = rs.Header
+		}
 	case *stats.OutTrailer:
 	case *stats.End:
+		var responseMetadata metadata.MD
+		if gCtx != nil {
+			responseMetadata = gCtx.responseMetadata
+		}
 		if rs.Error != nil {
 			s, _ := status.FromError(rs.Error)
 			if isServer {
 				grpcServerInstrument.End(ctx, grpcRequest{}, grpcResponse{
 					statusCode: int(s.Code()),
+					metadata:   responseMetadata,
 				}, rs.Error)
 			} else {
 				grpcClientInstrument.End(ctx, grpcRequest{}, grpcResponse{
 					statusCode: int(s.Code()),
+					metadata:   responseMetadata,
 				}, rs.Error)
 			}
 
@@ -103,12 +118,14 @@ func (c *grpcOtelConfig) handleRPC(ctx context.Context, rs stats.RPCStats, isSer
 					methodName: methodName,
 				}, grpcResponse{
 					statusCode: 200,
+					metadata:   responseMetadata,
 				}, nil)
 			} else {
 				grpcClientInstrument.End(ctx, grpcRequest{
 					methodName: methodName,
 				}, grpcResponse{
 					statusCode: 200,
+					metadata:   responseMetadata,
 				}, nil)
 			}
 
@@ -16,6 +16,7 @@ package grpc
 
 import (
 	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
@@ -29,14 +30,18 @@ type grpcRequest struct {
 	methodName    string
 	serverAddress string
 	propagators   propagation.TextMapCarrier
+	metadata      metadata.MD
 }
 
 type grpcResponse struct {
 	statusCode int
+	metadata   metadata.MD
 }
 
 type gRPCContextKey struct{}
 
 type gRPCContext struct {
 	methodName string
+	// The header metadata received by the client or sent by the server
+	responseMetadata metadata.MD
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
 )
 
@@ -59,9 +60,11 @@ func (h *clientNewHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) c
 	if filter != nil && *filter {
 		return ctx
 	}
+	md, _ := metadata.FromOutgoingContext(ctx)
 	nCtx := grpcClientInstrument.Start(ctx, grpcRequest{
 		methodName:    info.FullMethodName,
 		serverAddress: h.serverAddr,
+		metadata:      md,
 	})
 	gCtx := gRPCContext{
 		methodName: info.FullMethodName,
@@ -28,15 +28,8 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-type grpcInnerEnabler struct {
-	enabled bool
-}
-
-func (g grpcInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
-var grpcEnabler = grpcInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_GRPC_ENABLED") != "false"}
+var grpcEnabler = instrumenter.RegisterInstrumentEnabler(utils.GRPC_SERVER_SCOPE_NAME, "grpc",
+	os.Getenv("OTEL_INSTRUMENTATION_GRPC_ENABLED") != "false")
 
 type grpcAttrsGetter struct {
 }
@@ -67,6 +60,14 @@ func (g grpcAttrsGetter) GetServerAddress(request grpcRequest) string {
 	return request.serverAddress
 }
 
+func (g grpcAttrsGetter) GetRequestMetadata(request grpcRequest, name string) []string {
+	return request.metadata.Get(name)
+}
+
+func (g grpcAttrsGetter) GetResponseMetadata(request grpcRequest, response grpcResponse, name string) []string {
+	return response.metadata.Get(name)
+}
+
 type grpcStatusCodeExtractor[REQUEST grpcRequest, RESPONSE grpcResponse] struct {
 }
 
@@ -83,6 +83,7 @@ func (h *serverHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) cont
 		propagators: &grpcMetadataSupplier{
 			metadata: &md,
 		},
+		metadata: md,
 	})
 
 	gCtx := gRPCContext{
@@ -20,19 +20,14 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/cloudwego/hertz/pkg/app/client"
 	"github.com/cloudwego/hertz/pkg/protocol"
 )
 
-type hertzClientInnerEnabler struct {
-	enabled bool
-}
-
-func (h hertzClientInnerEnabler) Enable() bool {
-	return h.enabled
-}
-
-var hertzClientEnabler = hertzClientInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_HERTZ_ENABLED") != "false"}
+var hertzClientEnabler = instrumenter.RegisterInstrumentEnabler(utils.HERTZ_HTTP_CLIENT_SCOPE_NAME, "hertz",
+	os.Getenv("OTEL_INSTRUMENTATION_HERTZ_ENABLED") != "false")
 
 var hertzClientInstrumenter = BuildHertzClientInstrumenter()
 
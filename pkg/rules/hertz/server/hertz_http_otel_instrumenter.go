@@ -44,11 +44,11 @@ func (n hertzHttpServerAttrsGetter) GetRequestMethod(request *protocol.Request)
 }
 
 func (n hertzHttpServerAttrsGetter) GetHttpRequestHeader(request *protocol.Request, name string) []string {
-	keys := make([]string, 0)
-	request.Header.VisitAll(func(key, value []byte) {
-		keys = append(keys, string(key))
-	})
-	return keys
+	all := make([]string, 0)
+	for _, header := range request.Header.PeekAll(name) {
+		all = append(all, string(header))
+	}
+	return all
 }
 
 func (n hertzHttpServerAttrsGetter) GetHttpResponseStatusCode(request *protocol.Request, response *protocol.Response, err error) int {
@@ -56,11 +56,11 @@ func (n hertzHttpServerAttrsGetter) GetHttpResponseStatusCode(request *protocol.
 }
 
 func (n hertzHttpServerAttrsGetter) GetHttpResponseHeader(request *protocol.Request, response *protocol.Response, name string) []string {
-	keys := make([]string, 0)
-	response.Header.VisitAll(func(key, value []byte) {
-		keys = append(keys, string(key))
-	})
-	return keys
+	all := make([]string, 0)
+	for _, header := range response.Header.PeekAll(name) {
+		all = append(all, string(header))
+	}
+	return all
 }
 
 func (n hertzHttpServerAttrsGetter) GetErrorType(request *protocol.Request, response *protocol.Response, err error) string {
@@ -20,6 +20,8 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/cloudwego/hertz/pkg/common/config"
@@ -27,15 +29,8 @@ import (
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-type hertzServerInnerEnabler struct {
-	enabled bool
-}
-
-func (h hertzServerInnerEnabler) Enable() bool {
-	return h.enabled
-}
-
-var hertzServerEnabler = hertzServerInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_HERTZ_ENABLED") != "false"}
+var hertzServerEnabler = instrumenter.RegisterInstrumentEnabler(utils.HERTZ_HTTP_SERVER_SCOPE_NAME, "hertz",
+	os.Getenv("OTEL_INSTRUMENTATION_HERTZ_ENABLED") != "false")
 
 var hertzInstrumenter = BuildHertzServerInstrumenter()
 
@@ -29,15 +29,8 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 )
 
-type netHttpInnerEnabler struct {
-	enabled bool
-}
-
-func (n netHttpInnerEnabler) Enable() bool {
-	return n.enabled
-}
-
-var netHttpEnabler = netHttpInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_NETHTTP_ENABLED") != "false"}
+var netHttpEnabler = instrumenter.RegisterInstrumentEnabler(utils.NET_HTTP_SERVER_SCOPE_NAME, "nethttp",
+	os.Getenv("OTEL_INSTRUMENTATION_NETHTTP_ENABLED") != "false")
 
 var emptyHttpResponse = netHttpResponse{}
 
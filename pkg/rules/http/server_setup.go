@@ -57,9 +57,6 @@ func serverOnEnter(call api.CallContext, _ interface{}, w http.ResponseWriter, r
 
 //go:linkname serverOnExit net/http.serverOnExit
 func serverOnExit(call api.CallContext) {
-	if !netHttpEnabler.Enable() {
-		return
-	}
 	data, ok := call.GetData().(map[string]interface{})
 	if !ok || data == nil || data["ctx"] == nil {
 		return
@@ -73,6 +70,7 @@ func serverOnExit(call api.CallContext) {
 		if w1, ok := p.(*writerWrapper); ok {
 			netHttpServerInstrumenter.End(ctx, request, &netHttpResponse{
 				statusCode: w1.statusCode,
+				header:     w1.Header(),
 			}, nil)
 		}
 	}
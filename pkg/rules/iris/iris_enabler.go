@@ -16,14 +16,10 @@ package iris
 
 import (
 	"os"
-)
-
-type irisInnerEnabler struct {
-	enabled bool
-}
 
-func (k irisInnerEnabler) Enable() bool {
-	return k.enabled
-}
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+)
 
-var irisEnabler = irisInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_IRIS_ENABLED") != "false"}
+var irisEnabler = instrumenter.RegisterInstrumentEnabler(utils.IRIS_SCOPE_NAME, "iris",
+	os.Getenv("OTEL_INSTRUMENTATION_IRIS_ENABLED") != "false")
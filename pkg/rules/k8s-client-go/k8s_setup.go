@@ -45,10 +45,10 @@ func ProcessDeltasOnEnter(call api.CallContext, handler cache.ResourceEventHandl
 
 //go:linkname ProcessDeltasOnExit k8s.io/client-go/tools/cache.ProcessDeltasOnExit
 func ProcessDeltasOnExit(call api.CallContext, err error) {
-	if !k8sEnabler.Enable() {
+	m, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-	m := call.GetData().(map[string]interface{})
 	ctx := m["ctx"].(context.Context)
 	eventsInfo := m["eventsInfo"].(k8sEventsInfo)
 	if err != nil {
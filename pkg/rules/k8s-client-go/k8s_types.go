@@ -17,19 +17,13 @@ package k8s_client_go
 import (
 	"os"
 	"time"
-)
-
-type K8sClientGoInnerEnabler struct {
-	enabled bool
-}
 
-var k8sEnabler = K8sClientGoInnerEnabler{
-	enabled: os.Getenv("OTEL_INSTRUMENTATION_K8S_CLIENT_GO_ENABLED") != "false",
-}
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+)
 
-func (k K8sClientGoInnerEnabler) Enable() bool {
-	return k.enabled
-}
+var k8sEnabler = instrumenter.RegisterInstrumentEnabler(utils.K8S_CLIENT_GO_SCOPE_NAME, "k8s_client_go",
+	os.Getenv("OTEL_INSTRUMENTATION_K8S_CLIENT_GO_ENABLED") != "false")
 
 type k8sEventInfo struct {
 	eventType       string
@@ -21,54 +21,72 @@ import (
 	"github.com/cloudwego/kitex/pkg/rpcinfo"
 	"go.opentelemetry.io/otel/sdk/instrumentation"
 	"os"
+	"strings"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/rpc"
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
 )
 
-type kitexInnerEnabler struct {
-	enabled bool
-}
+var kitexEnabler = instrumenter.RegisterInstrumentEnabler(utils.KITEX_SERVER_SCOPE_NAME, "kitex",
+	os.Getenv("OTEL_INSTRUMENTATION_KITEX_ENABLED") != "false")
 
-func (k kitexInnerEnabler) Enable() bool {
-	return k.enabled
+// kitexRequest is the rpc info along with the metadata of the request, i.e. the
+// values of metainfo and the gRPC metadata
+type kitexRequest struct {
+	rpcinfo.RPCInfo
+	metadata map[string]string
 }
 
-var kitexEnabler = kitexInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_KITEX_ENABLED") != "false"}
-
 type kitexAttrsGetter struct{}
 
-func (g kitexAttrsGetter) GetSystem(request rpcinfo.RPCInfo) string {
+func (g kitexAttrsGetter) GetSystem(request kitexRequest) string {
 	return "kitex"
 }
 
-func (g kitexAttrsGetter) GetService(ri rpcinfo.RPCInfo) string {
+func (g kitexAttrsGetter) GetService(ri kitexRequest) string {
 	if ri.Invocation().PackageName() != "" {
 		return ri.Invocation().PackageName() + "." + ri.Invocation().ServiceName()
 	}
 	return ri.Invocation().ServiceName()
 }
 
-func (g kitexAttrsGetter) GetMethod(ri rpcinfo.RPCInfo) string {
+func (g kitexAttrsGetter) GetMethod(ri kitexRequest) string {
 	if ri.Invocation().PackageName() != "" {
 		return ri.Invocation().PackageName() + "." + ri.Invocation().ServiceName() + "/" + ri.Invocation().MethodName()
 	}
 	return ri.Invocation().ServiceName() + "/" + ri.Invocation().MethodName()
 }
 
-func (g kitexAttrsGetter) GetServerAddress(request rpcinfo.RPCInfo) string {
+func (g kitexAttrsGetter) GetServerAddress(request kitexRequest) string {
 	if request.To() != nil && request.To().Address() != nil {
 		return request.To().Address().String()
 	}
 	return ""
 }
 
-func BuildKitexClientInstrumenter() instrumenter.Instrumenter[rpcinfo.RPCInfo, rpcinfo.RPCInfo] {
-	builder := instrumenter.Builder[rpcinfo.RPCInfo, rpcinfo.RPCInfo]{}
+func (g kitexAttrsGetter) GetRequestMetadata(request kitexRequest, name string) []string {
+	if value, ok := request.metadata[name]; ok {
+		return []string{value}
+	}
+	for key, value := range request.metadata {
+		if strings.EqualFold(key, name) {
+			return []string{value}
+		}
+	}
+	return nil
+}
+
+// The response metadata is not captured, kitex doesn't expose it to the tracer
+func (g kitexAttrsGetter) GetResponseMetadata(request kitexRequest, response rpcinfo.RPCInfo, name string) []string {
+	return nil
+}
+
+func BuildKitexClientInstrumenter() instrumenter.Instrumenter[kitexRequest, rpcinfo.RPCInfo] {
+	builder := instrumenter.Builder[kitexRequest, rpcinfo.RPCInfo]{}
 	clientGetter := kitexAttrsGetter{}
-	return builder.Init().SetSpanNameExtractor(&rpc.RpcSpanNameExtractor[rpcinfo.RPCInfo]{Getter: clientGetter}).
-		SetSpanKindExtractor(&instrumenter.AlwaysClientExtractor[rpcinfo.RPCInfo]{}).
-		AddAttributesExtractor(&rpc.ClientRpcAttrsExtractor[rpcinfo.RPCInfo, rpcinfo.RPCInfo, kitexAttrsGetter]{Base: rpc.RpcAttrsExtractor[rpcinfo.RPCInfo, rpcinfo.RPCInfo, kitexAttrsGetter]{Getter: clientGetter}}).
+	return builder.Init().SetSpanNameExtractor(&rpc.RpcSpanNameExtractor[kitexRequest]{Getter: clientGetter}).
+		SetSpanKindExtractor(&instrumenter.AlwaysClientExtractor[kitexRequest]{}).
+		AddAttributesExtractor(&rpc.ClientRpcAttrsExtractor[kitexRequest, rpcinfo.RPCInfo, kitexAttrsGetter]{Base: rpc.RpcAttrsExtractor[kitexRequest, rpcinfo.RPCInfo, kitexAttrsGetter]{Getter: clientGetter}}).
 		AddOperationListeners(rpc.RpcClientMetrics("kitex.client")).
 		SetInstrumentationScope(instrumentation.Scope{
 			Name:    utils.KITEX_CLIENT_SCOPE_NAME,
@@ -77,13 +95,13 @@ func BuildKitexClientInstrumenter() instrumenter.Instrumenter[rpcinfo.RPCInfo, r
 		BuildInstrumenter()
 }
 
-func BuildKitexServerInstrumenter() instrumenter.Instrumenter[rpcinfo.RPCInfo, rpcinfo.RPCInfo] {
-	builder := instrumenter.Builder[rpcinfo.RPCInfo, rpcinfo.RPCInfo]{}
+func BuildKitexServerInstrumenter() instrumenter.Instrumenter[kitexRequest, rpcinfo.RPCInfo] {
+	builder := instrumenter.Builder[kitexRequest, rpcinfo.RPCInfo]{}
 	serverGetter := kitexAttrsGetter{}
-	return builder.Init().SetSpanNameExtractor(&rpc.RpcSpanNameExtractor[rpcinfo.RPCInfo]{Getter: serverGetter}).
-		SetSpanKindExtractor(&instrumenter.AlwaysServerExtractor[rpcinfo.RPCInfo]{}).
+	return builder.Init().SetSpanNameExtractor(&rpc.RpcSpanNameExtractor[kitexRequest]{Getter: serverGetter}).
+		SetSpanKindExtractor(&instrumenter.AlwaysServerExtractor[kitexRequest]{}).
 		AddOperationListeners(rpc.RpcServerMetrics("kitex.server")).
-		AddAttributesExtractor(&rpc.ServerRpcAttrsExtractor[rpcinfo.RPCInfo, rpcinfo.RPCInfo, kitexAttrsGetter]{Base: rpc.RpcAttrsExtractor[rpcinfo.RPCInfo, rpcinfo.RPCInfo, kitexAttrsGetter]{Getter: serverGetter}}).
+		AddAttributesExtractor(&rpc.ServerRpcAttrsExtractor[kitexRequest, rpcinfo.RPCInfo, kitexAttrsGetter]{Base: rpc.RpcAttrsExtractor[kitexRequest, rpcinfo.RPCInfo, kitexAttrsGetter]{Getter: serverGetter}}).
 		SetInstrumentationScope(instrumentation.Scope{
 			Name:    utils.KITEX_SERVER_SCOPE_NAME,
 			Version: version.Tag,
@@ -30,7 +30,7 @@ type clientTracer struct{}
 
 func (c *clientTracer) Start(ctx context.Context) context.Context {
 	ri := rpcinfo.GetRPCInfo(ctx)
-	ctx = kitexClientInstrumenter.Start(ctx, ri)
+	ctx = kitexClientInstrumenter.Start(ctx, kitexRequest{RPCInfo: ri, metadata: metainfo.GetAllValues(ctx)})
 	return ctx
 }
 
@@ -52,7 +52,7 @@ func (c *clientTracer) Finish(ctx context.Context) {
 		s.AddEvent(semconv.ExceptionEventName, opts...)
 		ctx = sdktrace.ContextWithSpan(ctx, s)
 	}
-	kitexClientInstrumenter.End(ctx, ri, ri, nil)
+	kitexClientInstrumenter.End(ctx, kitexRequest{RPCInfo: ri}, ri, nil)
 }
 
 func ClientMiddleware() endpoint.Middleware {
@@ -55,7 +55,7 @@ func (s *serverTracer) Finish(ctx context.Context) {
 		span.AddEvent(semconv.ExceptionEventName, opts...)
 		ctx = sdktrace.ContextWithSpan(ctx, span)
 	}
-	kitexServerInstrumenter.End(ctx, ri, ri, nil)
+	kitexServerInstrumenter.End(ctx, kitexRequest{RPCInfo: ri}, ri, nil)
 }
 
 func ServerMiddleware() endpoint.Middleware {
@@ -79,7 +79,7 @@ func ServerMiddleware() endpoint.Middleware {
 			}
 			ctx = Extract(ctx, md)
 			ri := rpcinfo.GetRPCInfo(ctx)
-			ctx = kitexServerInstrumenter.Start(ctx, ri)
+			ctx = kitexServerInstrumenter.Start(ctx, kitexRequest{RPCInfo: ri, metadata: md})
 			tc.SetSpan(sdktrace.SpanFromContext(ctx))
 			return next(ctx, req, resp)
 		}
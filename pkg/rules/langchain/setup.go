@@ -16,6 +16,9 @@ package langchain
 
 import (
 	"os"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 )
 
 const (
@@ -27,14 +30,7 @@ const (
 	MRelevantDoc       = "relevantDocuments"
 )
 
-type langChainInnerEnabler struct {
-	enabled bool
-}
-
-func (l langChainInnerEnabler) Enable() bool {
-	return l.enabled
-}
-
-var langChainEnabler = langChainInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_LANGCHAIN_ENABLED") != "false"}
+var langChainEnabler = instrumenter.RegisterInstrumentEnabler(utils.LANGCHAIN_SCOPE_NAME, "langchain",
+	os.Getenv("OTEL_INSTRUMENTATION_LANGCHAIN_ENABLED") != "false")
 
 var langChainCommonInstrument = BuildCommonLangchainOtelInstrumenter()
@@ -15,23 +15,22 @@
 package logrus
 
 import (
+	"os"
+	"sync"
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logbridge"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logcorrelation"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/sirupsen/logrus"
+	otellog "go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/sdk/trace"
-	"os"
 )
 
-type logrusInnerEnabler struct {
-	enabled bool
-}
-
-func (l logrusInnerEnabler) Enable() bool {
-	return l.enabled
-}
-
-var logrusEnabler = logrusInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_LOGRUS_ENABLED") != "false"}
+var logrusEnabler = instrumenter.RegisterInstrumentEnabler(utils.LOGRUS_SCOPE_NAME, "logrus",
+	os.Getenv("OTEL_INSTRUMENTATION_LOGRUS_ENABLED") != "false")
 
 //go:linkname withFieldOnExit github.com/sirupsen/logrus.withFieldOnExit
 func withFieldOnExit(call api.CallContext, e *logrus.Entry) {
@@ -41,7 +40,7 @@ func withFieldOnExit(call api.CallContext, e *logrus.Entry) {
 	if e.Logger.Hooks == nil {
 		e.Logger.Hooks = make(logrus.LevelHooks)
 	}
-	e.Logger.AddHook(&logHook{})
+	addHook(e.Logger)
 	return
 }
 
@@ -51,7 +50,7 @@ func logStandNewOnExit(call api.CallContext) {
 		return
 	}
 	std := logrus.StandardLogger()
-	std.AddHook(&logHook{})
+	addHook(std)
 	return
 }
 
@@ -60,7 +59,7 @@ func logNewOnExit(call api.CallContext, log *logrus.Logger) {
 	if !logrusEnabler.Enable() {
 		return
 	}
-	log.AddHook(&logHook{})
+	addHook(log)
 	return
 }
 
@@ -69,7 +68,7 @@ func standardLoggerOnExit(call api.CallContext, log *logrus.Logger) {
 	if !logrusEnabler.Enable() {
 		return
 	}
-	log.AddHook(&logHook{})
+	addHook(log)
 	return
 }
 
@@ -83,6 +82,17 @@ func logNewOnEnter(call api.CallContext, log *logrus.Logger, formatter logrus.Fo
 
 type logHook struct{}
 
+// hookedLoggers records the loggers with the hook added, so that each record
+// is handled only once no matter how many times the hooking functions are
+// called
+var hookedLoggers sync.Map
+
+func addHook(logger *logrus.Logger) {
+	if _, loaded := hookedLoggers.LoadOrStore(logger, struct{}{}); !loaded {
+		logger.AddHook(&logHook{})
+	}
+}
+
 func (hook *logHook) Levels() []logrus.Level {
 	return logrus.AllLevels
 }
@@ -91,13 +101,40 @@ func (hook *logHook) Fire(entry *logrus.Entry) error {
 	if !logrusEnabler.Enable() {
 		return nil
 	}
-	// Modify log content
-	traceId, spanId := trace.GetTraceAndSpanId()
-	if traceId != "" {
-		entry.Data["trace_id"] = traceId
+	if logbridge.Enabled() {
+		emitLogrusEntry(entry)
 	}
-	if spanId != "" {
-		entry.Data["span_id"] = spanId
+	// Modify log content
+	for _, field := range logcorrelation.SpanFields(trace.SpanFromGLS()) {
+		if _, ok := entry.Data[field.Key]; !ok {
+			entry.Data[field.Key] = field.Value
+		}
 	}
 	return nil
 }
+
+var logrusSeverities = map[logrus.Level]otellog.Severity{
+	logrus.TraceLevel: otellog.SeverityTrace,
+	logrus.DebugLevel: otellog.SeverityDebug,
+	logrus.InfoLevel:  otellog.SeverityInfo,
+	logrus.WarnLevel:  otellog.SeverityWarn,
+	logrus.ErrorLevel: otellog.SeverityError,
+	logrus.FatalLevel: otellog.SeverityFatal,
+	logrus.PanicLevel: otellog.SeverityFatal4,
+}
+
+// emitLogrusEntry bridges the entry to the logs signal, the data fields are
+// converted to the attributes
+func emitLogrusEntry(entry *logrus.Entry) {
+	var record otellog.Record
+	record.SetTimestamp(entry.Time)
+	record.SetBody(otellog.StringValue(entry.Message))
+	record.SetSeverity(logrusSeverities[entry.Level])
+	record.SetSeverityText(entry.Level.String())
+	attrs := make([]otellog.KeyValue, 0, len(entry.Data))
+	for key, value := range entry.Data {
+		attrs = append(attrs, logbridge.KeyValue(key, value))
+	}
+	record.AddAttributes(attrs...)
+	logbridge.Emit(utils.LOGRUS_SCOPE_NAME, trace.SpanFromGLS(), record)
+}
@@ -24,21 +24,16 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var mongoInstrumenter = BuildMongoOtelInstrumenter()
 
-type mongoInnerEnabler struct {
-	enabled bool
-}
-
-func (m mongoInnerEnabler) Enable() bool {
-	return m.enabled
-}
-
-var mongoEnabler = mongoInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_MONGO_ENABLED") != "false"}
+var mongoEnabler = instrumenter.RegisterInstrumentEnabler(utils.MONGO_SCOPE_NAME, "mongo",
+	os.Getenv("OTEL_INSTRUMENTATION_MONGO_ENABLED") != "false")
 
 //go:linkname mongoOnEnter go.mongodb.org/mongo-driver/mongo.mongoOnEnter
 func mongoOnEnter(call api.CallContext, opts ...*options.ClientOptions) {
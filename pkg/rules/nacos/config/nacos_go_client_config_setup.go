@@ -133,11 +133,11 @@ func beforeCallConfigServer(call api.CallContext, server *nacos_server.NacosServ
 
 //go:linkname afterCallConfigServer github.com/nacos-group/nacos-sdk-go/v2/common/nacos_server.afterCallConfigServer
 func afterCallConfigServer(call api.CallContext, result string, err error) {
-	if !experimental.NacosEnabler.Enable() {
+	t, ok := call.GetKeyData("ts").(int64)
+	if !ok {
 		return
 	}
 	method := call.GetKeyData("method").(string)
-	t := call.GetKeyData("ts").(int64)
 	tpe := call.GetKeyData("type").(string)
 	code := "200"
 	if err != nil {
@@ -169,10 +169,10 @@ func beforeRequestProxy(call api.CallContext, cp *config_client.ConfigProxy, rpc
 
 //go:linkname afterRequestProxy github.com/nacos-group/nacos-sdk-go/v2/clients/config_client.afterRequestProxy
 func afterRequestProxy(call api.CallContext, resp rpc_response.IResponse, err error) {
-	if !experimental.NacosEnabler.Enable() {
+	t, ok := call.GetKeyData("ts").(int64)
+	if !ok {
 		return
 	}
-	t := call.GetKeyData("ts").(int64)
 	req := call.GetKeyData("request").(rpc_request.IRequest)
 	code := "NA"
 	if resp != nil {
@@ -101,10 +101,10 @@ func beforeRequestToServer(call api.CallContext, proxy *naming_grpc.NamingGrpcPr
 
 //go:linkname afterRequestToServer github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client/naming_grpc.afterRequestToServer
 func afterRequestToServer(call api.CallContext, resp rpc_response.IResponse, err error) {
-	if !experimental.NacosEnabler.Enable() {
+	t, ok := call.GetKeyData("ts").(int64)
+	if !ok {
 		return
 	}
-	t := call.GetKeyData("ts").(int64)
 	req := call.GetKeyData("request").(rpc_request.IRequest)
 	code := "NA"
 	if resp != nil {
@@ -135,11 +135,11 @@ func beforeCallServer(call api.CallContext, server *nacos_server.NacosServer, ap
 
 //go:linkname afterCallServer github.com/nacos-group/nacos-sdk-go/v2/common/nacos_server.afterCallServer
 func afterCallServer(call api.CallContext, result string, err error) {
-	if !experimental.NacosEnabler.Enable() {
+	t, ok := call.GetKeyData("ts").(int64)
+	if !ok {
 		return
 	}
 	method := call.GetKeyData("method").(string)
-	t := call.GetKeyData("ts").(int64)
 	tpe := call.GetKeyData("type").(string)
 	code := "200"
 	if err != nil {
@@ -23,6 +23,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/config"
 )
 
 type Currency string
@@ -225,6 +227,14 @@ func init() {
 	}
 	
 	pricingDB.loadCustomPricing()
+	// The config file may be loaded or changed after the package is initialized
+	config.OnReload(func(*config.Config) {
+		if configPath := configuredCostConfig(); configPath != "" {
+			if err := pricingDB.LoadFromFile(configPath); err != nil {
+				fmt.Printf("Warning: Failed to load custom pricing from %s: %v\n", configPath, err)
+			}
+		}
+	})
 
 	enabledStr := "true"
 	if val := os.Getenv("OLLAMA_ENABLE_COST_TRACKING"); val != "" {
@@ -438,8 +448,17 @@ func (c *CostCalculator) ResetGlobalMetrics() {
 
 
 
+// configuredCostConfig returns the pricing file specified by the config file,
+// which takes precedence over OLLAMA_COST_CONFIG
+func configuredCostConfig() string {
+	if ic := config.LookupInstrumentation("ollama"); ic != nil && ic.CostConfig != "" {
+		return ic.CostConfig
+	}
+	return os.Getenv("OLLAMA_COST_CONFIG")
+}
+
 func (db *PricingDatabase) loadCustomPricing() {
-	if configPath := os.Getenv("OLLAMA_COST_CONFIG"); configPath != "" {
+	if configPath := configuredCostConfig(); configPath != "" {
 		if err := db.LoadFromFile(configPath); err != nil {
 			fmt.Printf("Warning: Failed to load custom pricing from %s: %v\n", configPath, err)
 		} else {
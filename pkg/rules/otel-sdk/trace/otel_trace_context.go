@@ -140,6 +140,7 @@ func traceContextAddSpan(span trace.Span) {
 	tc := getOrInitTraceContext()
 	if !tc.add(span) {
 		fmt.Println("Failed to add span to TraceContext")
+		traceContextDropped.Add(1)
 	}
 }
 
@@ -0,0 +1,37 @@
+//go:build ignore
+
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace_context
+
+import "sync/atomic"
+
+// The number of spans not tracked by the trace context since it's full
+var traceContextDropped atomic.Int64
+
+// TraceContextDroppedSpans returns the number of spans not tracked by the trace
+// context of goroutines because it reaches maxSpans
+func TraceContextDroppedSpans() int64 {
+	return traceContextDropped.Load()
+}
+
+// DroppedSpansOf returns the number of spans dropped by the span processor
+// because its queue is full, only batch span processors drop spans
+func DroppedSpansOf(p SpanProcessor) int64 {
+	if bsp, ok := p.(*batchSpanProcessor); ok {
+		return int64(atomic.LoadUint32(&bsp.dropped))
+	}
+	return 0
+}
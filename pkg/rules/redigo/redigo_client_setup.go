@@ -20,18 +20,13 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/gomodule/redigo/redis"
 )
 
-type redigoInnerEnabler struct {
-	enabled bool
-}
-
-func (r redigoInnerEnabler) Enable() bool {
-	return r.enabled
-}
-
-var redigoEnabler = redigoInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_REDIGO_ENABLED") != "false"}
+var redigoEnabler = instrumenter.RegisterInstrumentEnabler(utils.REDIGO_SCOPE_NAME, "redigo",
+	os.Getenv("OTEL_INSTRUMENTATION_REDIGO_ENABLED") != "false")
 
 //go:linkname onBeforeDialContext github.com/gomodule/redigo/redis.onBeforeDialContext
 func onBeforeDialContext(call api.CallContext, ctx context.Context, network, address string, options ...redis.DialOption) {
@@ -46,9 +41,6 @@ func onBeforeDialContext(call api.CallContext, ctx context.Context, network, add
 
 //go:linkname onExitDialContext github.com/gomodule/redigo/redis.onExitDialContext
 func onExitDialContext(call api.CallContext, conn redis.Conn, err error) {
-	if !redigoEnabler.Enable() {
-		return
-	}
 	d := call.GetData()
 	data, ok := d.(map[string]interface{})
 	if !ok {
@@ -18,6 +18,7 @@ import (
 	"container/list"
 	"context"
 	"fmt"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/config"
 	"github.com/gomodule/redigo/redis"
 	"os"
 	"strconv"
@@ -161,10 +162,14 @@ func pop(queue *list.List) *redigoRequest {
 }
 
 func getMaxQueueLength() int {
+	// The config file takes precedence and may change at runtime
+	if ic := config.LookupInstrumentation("redigo"); ic != nil && ic.MaxQueueLength != nil {
+		return *ic.MaxQueueLength
+	}
 	if configuredQueueLength == 0 {
 		var e = os.Getenv("MAX_REDIGO_QUEUE_LENGTH")
-		if e != "" {
-			configuredQueueLength, _ = strconv.Atoi(os.Getenv(e))
+		if n, err := strconv.Atoi(e); err == nil && n > 0 {
+			configuredQueueLength = n
 		} else {
 			configuredQueueLength = max_queue_length
 		}
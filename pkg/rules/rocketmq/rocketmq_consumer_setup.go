@@ -47,9 +47,6 @@ func consumerConsumeInnerOnEnter(call api.CallContext, _ interface{}, ctx contex
 
 //go:linkname consumerConsumeInnerOnExit github.com/apache/rocketmq-client-go/v2/consumer.consumerConsumeInnerOnExit
 func consumerConsumeInnerOnExit(call api.CallContext, consumeResult consumer.ConsumeResult, err error) {
-	if !rocketmqEnabler.Enable() {
-		return
-	}
 
 	data, ok := call.GetData().(map[string]interface{})
 	if !ok {
@@ -37,21 +37,14 @@ import (
 
 // Instrumentation control
 var (
-	rocketmqEnabler           = rocketmqInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_ROCKETMQ_ENABLED") != "false"}
+	rocketmqEnabler = instrumenter.RegisterInstrumentEnabler(utils.ROCKETMQGO_PRODUCER_SCOPE_NAME, "rocketmq",
+		os.Getenv("OTEL_INSTRUMENTATION_ROCKETMQ_ENABLED") != "false")
 	producerInst              = newProducerInstrumenter()
 	singleProcessConsumerInst = newConsumerInstrumenter(message.PROCESS, false)
 	batchProcessConsumerInst  = newConsumerInstrumenter(message.PROCESS, true)
 	receiveConsumerInst       = newReceiveInstrumenter()
 )
 
-type rocketmqInnerEnabler struct {
-	enabled bool
-}
-
-func (g rocketmqInnerEnabler) Enable() bool {
-	return g.enabled
-}
-
 // sendStatusToString converts SendStatus to readable string
 func sendStatusToString(status primitive.SendStatus) string {
 	switch status {
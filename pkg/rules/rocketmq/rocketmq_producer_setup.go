@@ -42,9 +42,6 @@ func producerSendSyncOnEnter(call api.CallContext, _ interface{}, ctx context.Co
 
 //go:linkname producerSendSyncOnExit github.com/apache/rocketmq-client-go/v2/producer.producerSendSyncOnExit
 func producerSendSyncOnExit(call api.CallContext, err error) {
-	if !rocketmqEnabler.Enable() {
-		return
-	}
 
 	data, ok := call.GetData().(map[string]interface{})
 	if !ok {
@@ -132,9 +129,6 @@ func producerSendOneWayOnEnter(call api.CallContext, _ interface{}, ctx context.
 
 //go:linkname producerSendOneWayOnExit github.com/apache/rocketmq-client-go/v2/producer.producerSendOneWayOnExit
 func producerSendOneWayOnExit(call api.CallContext, err error) {
-	if !rocketmqEnabler.Enable() {
-		return
-	}
 
 	data, ok := call.GetData().(map[string]interface{})
 	if !ok {
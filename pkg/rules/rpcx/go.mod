@@ -11,3 +11,5 @@ require (
 	go.opentelemetry.io/otel/sdk v1.35.0
 	go.opentelemetry.io/otel/trace v1.35.0
 )
+
+require gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -21,6 +21,7 @@ import (
 
 	"github.com/smallnest/rpcx/client"
 	"github.com/smallnest/rpcx/protocol"
+	"github.com/smallnest/rpcx/share"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
 )
@@ -66,15 +67,15 @@ func clientRpcxCallOnEnter(call api.CallContext, cli *client.Client, ctx context
 //
 //go:linkname clientRpcxCallOnExit github.com/smallnest/rpcx/client.clientRpcxCallOnExit
 func clientRpcxCallOnExit(call api.CallContext, err error) {
-	if !rpcxEnabler.Enable() {
+	data, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-	data := call.GetData().(map[string]interface{})
 	ctx := data["ctx"].(context.Context)
 	request := data["request"].(rpcxReq)
 	logPrintf("Response", request.call.ServicePath, request.call.ServiceMethod, err)
 
-	rpcxClientInstrumenter.End(ctx, request, rpcxRes{}, err)
+	rpcxClientInstrumenter.End(ctx, request, responseOf(request.ctx), err)
 
 }
 
@@ -120,10 +121,10 @@ func clientRpcxGoOnEnter(call api.CallContext, cli *client.Client, ctx context.C
 //
 //go:linkname clientRpcxGoOnExit github.com/smallnest/rpcx/client.clientRpcxGoOnExit
 func clientRpcxGoOnExit(call api.CallContext, xcall *client.Call) {
-	if !rpcxEnabler.Enable() {
+	data, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-	data := call.GetData().(map[string]interface{})
 	ctx := data["ctx"].(context.Context)
 
 	if v := ctx.Value(iscallFuncKey); v != nil && v.(bool) {
@@ -136,7 +137,11 @@ func clientRpcxGoOnExit(call api.CallContext, xcall *client.Call) {
 	}
 	logPrintf("Response", request.call.ServicePath, request.call.ServiceMethod, err)
 
-	rpcxClientInstrumenter.End(ctx, request, rpcxRes{}, err)
+	res := responseOf(request.ctx)
+	if xcall != nil && xcall.ResMetadata != nil {
+		res.metadata = xcall.ResMetadata
+	}
+	rpcxClientInstrumenter.End(ctx, request, res, err)
 }
 
 // (client *Client) SendRaw(ctx context.Context, r *protocol.Message) (map[string]string, []byte, error)
@@ -173,15 +178,22 @@ func clientRpcxSendRawOnEnter(call api.CallContext, cli *client.Client, ctx cont
 // (client *Client) SendRaw(ctx context.Context, r *protocol.Message) (map[string]string, []byte, error)
 //
 //go:linkname clientRpcxSendRawOnExit github.com/smallnest/rpcx/client.clientRpcxSendRawOnExit
-func clientRpcxSendRawOnExit(call api.CallContext, _ map[string]string, _ []byte, err error) {
-	if !rpcxEnabler.Enable() {
+func clientRpcxSendRawOnExit(call api.CallContext, md map[string]string, _ []byte, err error) {
+	data, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-	data := call.GetData().(map[string]interface{})
 	ctx := data["ctx"].(context.Context)
 	request := data["request"].(rpcxReq)
 	logPrintf("Response", request.call.ServicePath, request.call.ServiceMethod, err)
-	rpcxClientInstrumenter.End(ctx, request, rpcxRes{}, err)
+	rpcxClientInstrumenter.End(ctx, request, rpcxRes{metadata: md}, err)
+}
+
+// responseOf returns the response with the metadata the server replied, which
+// is only filled by rpcx if the caller puts a map into the context
+func responseOf(ctx context.Context) rpcxRes {
+	md, _ := ctx.Value(share.ResMetaDataKey).(map[string]string)
+	return rpcxRes{metadata: md}
 }
 
 func logPrintf(state, servicePath, serviceMethod string, err error) {
@@ -28,4 +28,5 @@ type rpcxReq struct {
 }
 
 type rpcxRes struct {
+	metadata map[string]string
 }
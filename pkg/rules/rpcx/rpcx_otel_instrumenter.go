@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/smallnest/rpcx/client"
 	"github.com/smallnest/rpcx/share"
@@ -33,15 +34,8 @@ import (
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/version"
 )
 
-type rpcxInnerEnabler struct {
-	enabled bool
-}
-
-func (t rpcxInnerEnabler) Enable() bool {
-	return t.enabled
-}
-
-var rpcxEnabler = rpcxInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_RPCX_ENABLED") != "false"}
+var rpcxEnabler = instrumenter.RegisterInstrumentEnabler(utils.RPCXGO_SERVER_SCOPE_NAME, "rpcx",
+	os.Getenv("OTEL_INSTRUMENTATION_RPCX_ENABLED") != "false")
 
 type rpcxClientAttrsGetter struct {
 }
@@ -62,6 +56,15 @@ func (t rpcxClientAttrsGetter) GetServerAddress(request rpcxReq) string {
 	return request.addr
 }
 
+func (t rpcxClientAttrsGetter) GetRequestMetadata(request rpcxReq, name string) []string {
+	md, _ := request.ctx.Value(share.ReqMetaDataKey).(map[string]string)
+	return metadataValues(md, name)
+}
+
+func (t rpcxClientAttrsGetter) GetResponseMetadata(request rpcxReq, response rpcxRes, name string) []string {
+	return metadataValues(response.metadata, name)
+}
+
 type rpcxServerAttrsGetter struct {
 }
 
@@ -81,6 +84,28 @@ func (t rpcxServerAttrsGetter) GetServerAddress(request rpcxReq) string {
 	return request.addr
 }
 
+func (t rpcxServerAttrsGetter) GetRequestMetadata(request rpcxReq, name string) []string {
+	return metadataValues(request.call.Metadata, name)
+}
+
+func (t rpcxServerAttrsGetter) GetResponseMetadata(request rpcxReq, response rpcxRes, name string) []string {
+	return metadataValues(response.metadata, name)
+}
+
+// metadataValues returns the value of the metadata, the name is matched
+// case-insensitively since the captured names are lower-cased
+func metadataValues(md map[string]string, name string) []string {
+	if value, ok := md[name]; ok {
+		return []string{value}
+	}
+	for key, value := range md {
+		if strings.EqualFold(key, name) {
+			return []string{value}
+		}
+	}
+	return nil
+}
+
 type rpcxStatusCodeExtractor[REQUEST rpcxReq, RESPONSE rpcxRes] struct {
 }
 
@@ -58,11 +58,15 @@ func serverHandleRequestOnEnter(call api.CallContext, s *server.Server, ctx cont
 //
 //go:linkname serverHandleRequestOnExit github.com/smallnest/rpcx/server.serverHandleRequestOnExit
 func serverHandleRequestOnExit(call api.CallContext, res *protocol.Message, err error) {
-	if !rpcxEnabler.Enable() {
+	data, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-	data := call.GetData().(map[string]interface{})
 	ctx := data["ctx"].(context.Context)
 	request := data["request"].(rpcxReq)
-	rpcxServerInstrumenter.End(ctx, request, rpcxRes{}, err)
+	response := rpcxRes{}
+	if res != nil {
+		response.metadata = res.Metadata
+	}
+	rpcxServerInstrumenter.End(ctx, request, response, err)
 }
@@ -17,6 +17,8 @@ package rueidisgo
 import (
 	"context"
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/redis/rueidis"
 	"go.opentelemetry.io/otel/trace"
 	"os"
@@ -25,19 +27,12 @@ import (
 	_ "unsafe"
 )
 
-type rueidisInnerEnabler struct {
-	enabled bool
-}
-
-func (r rueidisInnerEnabler) Enable() bool {
-	return r.enabled
-}
-
 var goRueidisInstrumenter = BuildGoRueidisOtelInstrumenter()
 
 var rueidisStartOptions = []trace.SpanStartOption{}
 
-var rueidisEnabler = rueidisInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_REDIGO_ENABLED") != "false"}
+var rueidisEnabler = instrumenter.RegisterInstrumentEnabler(utils.RUEIDIS_SCOPE_NAME, "rueidis",
+	os.Getenv("OTEL_INSTRUMENTATION_RUEIDIS_ENABLED") != "false")
 
 //go:linkname rueidisNewClientOnEnter github.com/redis/rueidis.rueidisNewClientOnEnter
 func rueidisNewClientOnEnter(call api.CallContext, option rueidis.ClientOption) {
@@ -71,7 +66,7 @@ func clusterClientDoOnEnter(call api.CallContext, client interface{}, ctx1 conte
 
 //go:linkname clusterClientDoOnExit github.com/redis/rueidis.clusterClientDoOnExit
 func clusterClientDoOnExit(call api.CallContext, r rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -112,7 +107,7 @@ func clusterClientDoMultiOnEnter(call api.CallContext, client interface{}, ctx1
 
 //go:linkname clusterClientDoMultiOnExit github.com/redis/rueidis.clusterClientDoMultiOnExit
 func clusterClientDoMultiOnExit(call api.CallContext, resp []rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -154,7 +149,7 @@ func clusterClientReceiveOnEnter(call api.CallContext, client interface{}, ctx1
 
 //go:linkname clusterClientReceiveOnExit github.com/redis/rueidis.clusterClientReceiveOnExit
 func clusterClientReceiveOnExit(call api.CallContext, err error) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -195,7 +190,7 @@ func clusterClientDoCacheOnEnter(call api.CallContext, client interface{}, ctx1
 
 //go:linkname clusterClientDoCacheOnExit github.com/redis/rueidis.clusterClientDoCacheOnExit
 func clusterClientDoCacheOnExit(call api.CallContext, r rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -236,7 +231,7 @@ func clusterClientDoMultiCacheOnEnter(call api.CallContext, client interface{},
 
 //go:linkname clusterClientDoMultiCacheOnExit github.com/redis/rueidis.clusterClientDoMultiCacheOnExit
 func clusterClientDoMultiCacheOnExit(call api.CallContext, r []rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -277,7 +272,7 @@ func clusterClientDoStreamOnEnter(call api.CallContext, client interface{}, ctx1
 
 //go:linkname clusterClientDoStreamOnExit github.com/redis/rueidis.clusterClientDoStreamOnExit
 func clusterClientDoStreamOnExit(call api.CallContext, r rueidis.RedisResultStream) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -318,7 +313,7 @@ func clusterClientDoMultiStreamOnEnter(call api.CallContext, client interface{},
 
 //go:linkname clusterClientDoMultiStreamOnExit github.com/redis/rueidis.clusterClientDoMultiStreamOnExit
 func clusterClientDoMultiStreamOnExit(call api.CallContext, r rueidis.MultiRedisResultStream) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -359,7 +354,7 @@ func singleClientDoOnEnter(call api.CallContext, client interface{}, ctx1 contex
 
 //go:linkname singleClientDoOnExit github.com/redis/rueidis.singleClientDoOnExit
 func singleClientDoOnExit(call api.CallContext, r rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -400,7 +395,7 @@ func singleClientDoMultiOnEnter(call api.CallContext, client interface{}, ctx1 c
 
 //go:linkname singleClientDoMultiOnExit github.com/redis/rueidis.singleClientDoMultiOnExit
 func singleClientDoMultiOnExit(call api.CallContext, resp []rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -442,7 +437,7 @@ func singleClientReceiveOnEnter(call api.CallContext, client interface{}, ctx1 c
 
 //go:linkname singleClientReceiveOnExit github.com/redis/rueidis.singleClientReceiveOnExit
 func singleClientReceiveOnExit(call api.CallContext, err error) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -483,7 +478,7 @@ func singleClientDoCacheOnEnter(call api.CallContext, client interface{}, ctx1 c
 
 //go:linkname singleClientDoCacheOnExit github.com/redis/rueidis.singleClientDoCacheOnExit
 func singleClientDoCacheOnExit(call api.CallContext, r rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -524,7 +519,7 @@ func singleClientDoMultiCacheOnEnter(call api.CallContext, client interface{}, c
 
 //go:linkname singleClientDoMultiCacheOnExit github.com/redis/rueidis.singleClientDoMultiCacheOnExit
 func singleClientDoMultiCacheOnExit(call api.CallContext, r []rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -565,7 +560,7 @@ func singleClientDoStreamOnEnter(call api.CallContext, client interface{}, ctx1
 
 //go:linkname singleClientDoStreamOnExit github.com/redis/rueidis.singleClientDoStreamOnExit
 func singleClientDoStreamOnExit(call api.CallContext, r rueidis.RedisResultStream) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -606,7 +601,7 @@ func singleClientDoMultiStreamOnEnter(call api.CallContext, client interface{},
 
 //go:linkname singleClientDoMultiStreamOnExit github.com/redis/rueidis.singleClientDoMultiStreamOnExit
 func singleClientDoMultiStreamOnExit(call api.CallContext, r rueidis.MultiRedisResultStream) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -647,7 +642,7 @@ func sentinelClientDoOnEnter(call api.CallContext, client interface{}, ctx1 cont
 
 //go:linkname sentinelClientDoOnExit github.com/redis/rueidis.sentinelClientDoOnExit
 func sentinelClientDoOnExit(call api.CallContext, r rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -688,7 +683,7 @@ func sentinelClientDoMultiOnEnter(call api.CallContext, client interface{}, ctx1
 
 //go:linkname sentinelClientDoMultiOnExit github.com/redis/rueidis.sentinelClientDoMultiOnExit
 func sentinelClientDoMultiOnExit(call api.CallContext, resp []rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -730,7 +725,7 @@ func sentinelClientReceiveOnEnter(call api.CallContext, client interface{}, ctx1
 
 //go:linkname sentinelClientReceiveOnExit github.com/redis/rueidis.sentinelClientReceiveOnExit
 func sentinelClientReceiveOnExit(call api.CallContext, err error) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -771,7 +766,7 @@ func sentinelClientDoCacheOnEnter(call api.CallContext, client interface{}, ctx1
 
 //go:linkname sentinelClientDoCacheOnExit github.com/redis/rueidis.sentinelClientDoCacheOnExit
 func sentinelClientDoCacheOnExit(call api.CallContext, r rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -812,7 +807,7 @@ func sentinelClientDoMultiCacheOnEnter(call api.CallContext, client interface{},
 
 //go:linkname sentinelClientDoMultiCacheOnExit github.com/redis/rueidis.sentinelClientDoMultiCacheOnExit
 func sentinelClientDoMultiCacheOnExit(call api.CallContext, r []rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -853,7 +848,7 @@ func sentinelClientDoStreamOnEnter(call api.CallContext, client interface{}, ctx
 
 //go:linkname sentinelClientDoStreamOnExit github.com/redis/rueidis.sentinelClientDoStreamOnExit
 func sentinelClientDoStreamOnExit(call api.CallContext, r rueidis.RedisResultStream) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -894,7 +889,7 @@ func sentinelClientDoMultiStreamOnEnter(call api.CallContext, client interface{}
 
 //go:linkname sentinelClientDoMultiStreamOnExit github.com/redis/rueidis.sentinelClientDoMultiStreamOnExit
 func sentinelClientDoMultiStreamOnExit(call api.CallContext, r rueidis.MultiRedisResultStream) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -935,7 +930,7 @@ func standaloneDoOnEnter(call api.CallContext, client interface{}, ctx1 context.
 
 //go:linkname standaloneDoOnExit github.com/redis/rueidis.standaloneDoOnExit
 func standaloneDoOnExit(call api.CallContext, r rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -976,7 +971,7 @@ func standaloneDoMultiOnEnter(call api.CallContext, client interface{}, ctx1 con
 
 //go:linkname standaloneDoMultiOnExit github.com/redis/rueidis.standaloneDoMultiOnExit
 func standaloneDoMultiOnExit(call api.CallContext, resp []rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -1018,7 +1013,7 @@ func standaloneReceiveOnEnter(call api.CallContext, client interface{}, ctx1 con
 
 //go:linkname standaloneReceiveOnExit github.com/redis/rueidis.standaloneReceiveOnExit
 func standaloneReceiveOnExit(call api.CallContext, err error) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -1059,7 +1054,7 @@ func standaloneDoCacheOnEnter(call api.CallContext, client interface{}, ctx1 con
 
 //go:linkname standaloneDoCacheOnExit github.com/redis/rueidis.standaloneDoCacheOnExit
 func standaloneDoCacheOnExit(call api.CallContext, r rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -1100,7 +1095,7 @@ func standaloneDoMultiCacheOnEnter(call api.CallContext, client interface{}, ctx
 
 //go:linkname standaloneDoMultiCacheOnExit github.com/redis/rueidis.standaloneDoMultiCacheOnExit
 func standaloneDoMultiCacheOnExit(call api.CallContext, r []rueidis.RedisResult) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -1141,7 +1136,7 @@ func standaloneDoStreamOnEnter(call api.CallContext, client interface{}, ctx1 co
 
 //go:linkname standaloneDoStreamOnExit github.com/redis/rueidis.standaloneDoStreamOnExit
 func standaloneDoStreamOnExit(call api.CallContext, r rueidis.RedisResultStream) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -1182,7 +1177,7 @@ func standaloneDoMultiStreamOnEnter(call api.CallContext, client interface{}, ct
 
 //go:linkname standaloneDoMultiStreamOnExit github.com/redis/rueidis.standaloneDoMultiStreamOnExit
 func standaloneDoMultiStreamOnExit(call api.CallContext, r rueidis.MultiRedisResultStream) {
-	if !rueidisEnabler.Enable() || call.GetData() == nil {
+	if call.GetData() == nil {
 		return
 	}
 	data, ok := call.GetData().(map[string]interface{})
@@ -36,6 +36,9 @@ var otel_set_trace_context_to_gls = _otel_gls_set_trace_context_impl
 //go:linkname otel_set_baggage_container_to_gls otel_set_baggage_container_to_gls
 var otel_set_baggage_container_to_gls = _otel_gls_set_baggage_container_impl
 
+//go:linkname otel_swap_self_telemetry_busy otel_swap_self_telemetry_busy
+var otel_swap_self_telemetry_busy = _otel_gls_swap_self_telemetry_busy_impl
+
 //go:nosplit
 func _otel_gls_get_trace_context_impl() interface{} {
 	return getg().m.curg.otel_trace_context
@@ -56,6 +59,14 @@ func _otel_gls_set_baggage_container_impl(v interface{}) {
 	getg().m.curg.otel_baggage_container = v
 }
 
+//go:nosplit
+func _otel_gls_swap_self_telemetry_busy_impl(busy bool) bool {
+	gp := getg().m.curg
+	old := gp.otel_self_telemetry_busy
+	gp.otel_self_telemetry_busy = busy
+	return old
+}
+
 type ContextSnapshoter interface {
 	TakeSnapShot() interface{}
 }
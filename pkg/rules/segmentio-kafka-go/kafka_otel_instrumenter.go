@@ -29,10 +29,12 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
 	"go.opentelemetry.io/otel/trace"
 	"os"
+	"strings"
 )
 
 // Instrumentation enabler controller
-var kafkaEnabler = kafkaInnerEnabler{os.Getenv("OTEL_SEGMENTIO_KAFKA_ENABLED") != "false"}
+var kafkaEnabler = instrumenter.RegisterInstrumentEnabler(utils.KAFKAGO_PRODUCER_SCOPE_NAME, "segmentio_kafka",
+	os.Getenv("OTEL_SEGMENTIO_KAFKA_ENABLED") != "false")
 
 // Cache Instrumenter instances to avoid repeated creation
 var (
@@ -40,14 +42,6 @@ var (
 	consumerInstrumenter = buildKafkaConsumerInstrumenter()
 )
 
-type kafkaInnerEnabler struct {
-	enabled bool
-}
-
-func (k kafkaInnerEnabler) Enable() bool {
-	return k.enabled
-}
-
 // KafkaProducerCarrier implements OpenTelemetry propagator carrier interface for producers
 type kafkaProducerCarrier struct {
 	messages []*kafka.Message
@@ -173,7 +167,14 @@ func (getter kafkaMessageProducerAttrsGetter) GetBatchMessageCount(request kafka
 }
 
 func (getter kafkaMessageProducerAttrsGetter) GetMessageHeader(request kafkaProducerReq, name string) []string {
-	return []string{}
+	var headerValues []string
+	for _, msg := range request.msgs {
+		if msg == nil {
+			continue
+		}
+		headerValues = append(headerValues, headerValuesOf(msg.Headers, name)...)
+	}
+	return headerValues
 }
 
 // KafkaMessageConsumerAttributesGetter retrieves consumer message attributes
@@ -233,12 +234,15 @@ func (getter kafkaMessageConsumerAttrsGetter) GetBatchMessageCount(request kafka
 }
 
 func (getter kafkaMessageConsumerAttrsGetter) GetMessageHeader(request kafkaConsumerReq, name string) []string {
-	if request.msg.Headers == nil {
-		return nil
-	}
+	return headerValuesOf(request.msg.Headers, name)
+}
+
+// headerValuesOf returns the values of the header, the name is matched
+// case-insensitively since the captured header names are lower-cased
+func headerValuesOf(headers []kafka.Header, name string) []string {
 	var headerValues []string
-	for _, header := range request.msg.Headers {
-		if header.Key == name {
+	for _, header := range headers {
+		if strings.EqualFold(header.Key, name) {
 			headerValues = append(headerValues, string(header.Value))
 		}
 	}
@@ -65,12 +65,11 @@ func producerWriteMessagesOnEnter(call api.CallContext, writer *kafka.Writer, ct
 
 //go:linkname producerWriteMessagesOnExit github.com/segmentio/kafka-go.producerWriteMessagesOnExit
 func producerWriteMessagesOnExit(call api.CallContext, err error) {
-	if !kafkaEnabler.Enable() {
+	// Retrieve stored instrumentation data
+	instrumentationData, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-
-	// Retrieve stored instrumentation data
-	instrumentationData := call.GetData().(map[string]interface{})
 	instrumentedContext := instrumentationData["instrumentedContext"].(context.Context)
 	producerRequest := instrumentationData["producerRequest"].(kafkaProducerReq)
 
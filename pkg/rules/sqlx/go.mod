@@ -7,4 +7,5 @@ replace github.com/alibaba/loongsuite-go-agent/pkg => ../../../pkg
 require (
 	github.com/alibaba/loongsuite-go-agent/pkg v0.0.0-00010101000000-000000000000
 	github.com/jmoiron/sqlx v1.3.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -18,23 +18,16 @@ import (
 	"context"
 	"database/sql"
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/jmoiron/sqlx"
 	"log"
 	"os"
 	_ "unsafe"
 )
 
-type sqlxInnerEnabler struct {
-	enabled bool
-}
-
-func (s sqlxInnerEnabler) Enable() bool {
-	return s.enabled
-}
-
-var sqlxEnabler = sqlxInnerEnabler{
-	enabled: os.Getenv("OTEL_INSTRUMENTATION_SQLX_ENABLED") != "false",
-}
+var sqlxEnabler = instrumenter.RegisterInstrumentEnabler(utils.SQLX_SCOPE_NAME, "sqlx",
+	os.Getenv("OTEL_INSTRUMENTATION_SQLX_ENABLED") != "false")
 
 var sqlInstrumenter = BuildSqlxInstrumenter()
 
@@ -55,9 +48,6 @@ func beforeConnect(ctx api.CallContext, driverName, dataSourceName string) {
 
 //go:linkname afterConnect github.com/jmoiron/sqlx.afterConnect
 func afterConnect(ctx api.CallContext, db *sqlx.DB, err error) {
-	if !sqlxEnabler.Enable() {
-		return
-	}
 	if err != nil || db == nil {
 		return
 	}
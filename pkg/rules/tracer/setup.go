@@ -0,0 +1,270 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+var tracerEnabler = instrumenter.RegisterInstrumentEnabler(utils.TRACER_SCOPE_NAME, "tracer",
+	os.Getenv("OTEL_INSTRUMENTATION_TRACER_ENABLED") != "false")
+
+var tracerInstrumenter = BuildTracerInstrumenter()
+
+// traceRule is the span name template and the captured parameters of the
+// functions matched by a trace rule
+type traceRule struct {
+	importPath   string
+	function     *regexp.Regexp
+	receiverType *regexp.Regexp
+	spanName     string
+	params       map[int]string
+}
+
+// traceTarget is the resolved trace settings of an instrumented function
+type traceTarget struct {
+	spanName string
+	params   []int
+	keys     []attribute.Key
+	attrs    []attribute.KeyValue
+}
+
+type targetKey struct {
+	packagePath  string
+	receiverType string
+	funcName     string
+}
+
+var (
+	traceRulesLock sync.RWMutex
+	traceRules     = make([]*traceRule, 0)
+	traceTargets   sync.Map // targetKey -> *traceTarget
+)
+
+// Register registers the span name template and the captured parameters of
+// the trace rule, function and receiverType are the regular expressions of the
+// rule. It's called by the generated code before the main function starts
+func Register(importPath, function, receiverType, spanName string,
+	params map[int]string) {
+	rule := &traceRule{
+		importPath: importPath,
+		function:   regexp.MustCompile("^" + function + "$"),
+		spanName:   spanName,
+		params:     params,
+	}
+	if receiverType != "" {
+		rule.receiverType = regexp.MustCompile("^" + receiverType + "$")
+	}
+	traceRulesLock.Lock()
+	defer traceRulesLock.Unlock()
+	traceRules = append(traceRules, rule)
+	// Functions traced during package initialization may be resolved before
+	// the registration
+	traceTargets.Clear()
+}
+
+func matchImportPath(pattern, importPath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return importPath == prefix || strings.HasPrefix(importPath, prefix+"/")
+	}
+	return importPath == pattern
+}
+
+func (r *traceRule) match(key targetKey) bool {
+	if !matchImportPath(r.importPath, key.packagePath) ||
+		!r.function.MatchString(key.funcName) {
+		return false
+	}
+	if r.receiverType == nil {
+		return key.receiverType == ""
+	}
+	return r.receiverType.MatchString(key.receiverType)
+}
+
+func findTraceRule(key targetKey) *traceRule {
+	traceRulesLock.RLock()
+	defer traceRulesLock.RUnlock()
+	for _, rule := range traceRules {
+		if rule.match(key) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// formatSpanName replaces the placeholders of the span name template, the
+// default span name is "Receiver.Func" for methods and "package.Func" for
+// plain functions
+func formatSpanName(template string, call api.CallContext) string {
+	receiverType := strings.TrimPrefix(call.GetReceiverType(), "*")
+	if template == "" {
+		template = "{package}.{func}"
+		if receiverType != "" {
+			template = "{receiver}.{func}"
+		}
+	}
+	return strings.NewReplacer(
+		"{package}", call.GetPackageName(),
+		"{receiver}", receiverType,
+		"{func}", call.GetFuncName(),
+	).Replace(template)
+}
+
+func resolveTarget(call api.CallContext) *traceTarget {
+	key := targetKey{
+		packagePath:  call.GetPackagePath(),
+		receiverType: call.GetReceiverType(),
+		funcName:     call.GetFuncName(),
+	}
+	if target, ok := traceTargets.Load(key); ok {
+		return target.(*traceTarget)
+	}
+	rule := findTraceRule(key)
+	if rule == nil {
+		rule = &traceRule{}
+	}
+	namespace := key.packagePath
+	if key.receiverType != "" {
+		namespace += "." + strings.TrimPrefix(key.receiverType, "*")
+	}
+	target := &traceTarget{
+		spanName: formatSpanName(rule.spanName, call),
+		attrs: []attribute.KeyValue{
+			semconv.CodeFunctionName(key.funcName),
+			semconv.CodeNamespace(namespace),
+			semconv.CodeFilepath(call.GetSourceFile()),
+			semconv.CodeLineNumber(call.GetSourceLine()),
+		},
+	}
+	for idx := range rule.params {
+		target.params = append(target.params, idx)
+	}
+	sort.Ints(target.params)
+	for _, idx := range target.params {
+		target.keys = append(target.keys, attribute.Key(rule.params[idx]))
+	}
+	actual, _ := traceTargets.LoadOrStore(key, target)
+	return actual.(*traceTarget)
+}
+
+// paramAttribute converts the parameter to the attribute, types that have no
+// counterpart attribute type are formatted as strings
+func paramAttribute(key attribute.Key, val interface{}) (attribute.KeyValue, bool) {
+	switch v := val.(type) {
+	case nil:
+		return attribute.KeyValue{}, false
+	case string:
+		return key.String(v), true
+	case bool:
+		return key.Bool(v), true
+	case int:
+		return key.Int(v), true
+	case int8:
+		return key.Int64(int64(v)), true
+	case int16:
+		return key.Int64(int64(v)), true
+	case int32:
+		return key.Int64(int64(v)), true
+	case int64:
+		return key.Int64(v), true
+	case uint8:
+		return key.Int64(int64(v)), true
+	case uint16:
+		return key.Int64(int64(v)), true
+	case uint32:
+		return key.Int64(int64(v)), true
+	case float32:
+		return key.Float64(float64(v)), true
+	case float64:
+		return key.Float64(v), true
+	case []string:
+		return key.StringSlice(v), true
+	case error:
+		return key.String(v.Error()), true
+	case fmt.Stringer:
+		return key.String(v.String()), true
+	}
+	return key.String(fmt.Sprint(val)), true
+}
+
+func traceOnEnter(call api.CallContext) {
+	if !tracerEnabler.Enable() {
+		return
+	}
+	target := resolveTarget(call)
+	request := &tracerRequest{
+		SpanName: target.spanName,
+		Attrs:    make([]attribute.KeyValue, 0, len(target.attrs)+len(target.params)),
+	}
+	request.Attrs = append(request.Attrs, target.attrs...)
+	for i, idx := range target.params {
+		if idx < 0 || idx >= call.GetParamCount() {
+			continue
+		}
+		if attr, ok := paramAttribute(target.keys[i], call.GetParam(idx)); ok {
+			request.Attrs = append(request.Attrs, attr)
+		}
+	}
+	// The span is the child of the context parameter if any, otherwise the
+	// current span of the goroutine
+	parentCtx := context.Background()
+	for i := 0; i < call.GetParamCount(); i++ {
+		if ctx, ok := call.GetParam(i).(context.Context); ok && ctx != nil {
+			parentCtx = ctx
+			break
+		}
+	}
+	ctx := tracerInstrumenter.Start(parentCtx, request)
+	data := make(map[string]interface{}, 2)
+	data["ctx"] = ctx
+	data["request"] = request
+	call.SetData(data)
+}
+
+func traceOnExit(call api.CallContext) {
+	data, ok := call.GetData().(map[string]interface{})
+	if !ok || data == nil {
+		return
+	}
+	ctx, ok := data["ctx"].(context.Context)
+	if !ok {
+		return
+	}
+	request, ok := data["request"].(*tracerRequest)
+	if !ok {
+		return
+	}
+	// The returned error, if any, is recorded as the span status
+	var err error
+	for i := 0; i < call.GetReturnValCount(); i++ {
+		if e, ok := call.GetReturnVal(i).(error); ok && e != nil {
+			err = e
+			break
+		}
+	}
+	tracerInstrumenter.End(ctx, request, nil, err)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracer
+
+import (
+	"context"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+)
+
+type tracerSpanNameExtractor struct{}
+
+func (t *tracerSpanNameExtractor) Extract(request *tracerRequest) string {
+	return request.SpanName
+}
+
+type tracerAttrsExtractor struct{}
+
+func (t *tracerAttrsExtractor) OnStart(attributes []attribute.KeyValue, parentContext context.Context, request *tracerRequest) ([]attribute.KeyValue, context.Context) {
+	return append(attributes, request.Attrs...), parentContext
+}
+
+func (t *tracerAttrsExtractor) OnEnd(attributes []attribute.KeyValue, ctx context.Context, request *tracerRequest, response any, err error) ([]attribute.KeyValue, context.Context) {
+	return attributes, ctx
+}
+
+func BuildTracerInstrumenter() instrumenter.Instrumenter[*tracerRequest, any] {
+	builder := instrumenter.Builder[*tracerRequest, any]{}
+	return builder.Init().SetSpanNameExtractor(&tracerSpanNameExtractor{}).
+		SetSpanKindExtractor(&instrumenter.AlwaysInternalExtractor[*tracerRequest]{}).
+		AddAttributesExtractor(&tracerAttrsExtractor{}).
+		SetInstrumentationScope(instrumentation.Scope{
+			Name:    utils.TRACER_SCOPE_NAME,
+			Version: version.Tag,
+		}).
+		BuildInstrumenter()
+}
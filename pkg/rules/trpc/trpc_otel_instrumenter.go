@@ -17,6 +17,7 @@ package trpc
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api-semconv/instrumenter/rpc"
 	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
@@ -30,15 +31,8 @@ import (
 	"trpc.group/trpc-go/trpc-go/codec"
 )
 
-type trpcInnerEnabler struct {
-	enabled bool
-}
-
-func (t trpcInnerEnabler) Enable() bool {
-	return t.enabled
-}
-
-var trpcEnabler = trpcInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_TRPC_ENABLED") != "false"}
+var trpcEnabler = instrumenter.RegisterInstrumentEnabler(utils.TRPCGO_SERVER_SCOPE_NAME, "trpc",
+	os.Getenv("OTEL_INSTRUMENTATION_TRPC_ENABLED") != "false")
 
 type trpcClientAttrsGetter struct {
 }
@@ -59,6 +53,16 @@ func (t trpcClientAttrsGetter) GetServerAddress(request trpcReq) string {
 	return request.addr
 }
 
+func (t trpcClientAttrsGetter) GetRequestMetadata(request trpcReq, name string) []string {
+	return metaDataValues(request.msg.ClientMetaData(), name)
+}
+
+// The response metadata is not captured, trpc doesn't tell it apart from the
+// request metadata
+func (t trpcClientAttrsGetter) GetResponseMetadata(request trpcReq, response trpcRes, name string) []string {
+	return nil
+}
+
 type trpcServerAttrsGetter struct {
 }
 
@@ -81,6 +85,28 @@ func (t trpcServerAttrsGetter) GetServerAddress(request trpcReq) string {
 	return ""
 }
 
+func (t trpcServerAttrsGetter) GetRequestMetadata(request trpcReq, name string) []string {
+	return metaDataValues(request.msg.ServerMetaData(), name)
+}
+
+func (t trpcServerAttrsGetter) GetResponseMetadata(request trpcReq, response trpcRes, name string) []string {
+	return nil
+}
+
+// metaDataValues returns the value of the metadata, the name is matched
+// case-insensitively since the captured names are lower-cased
+func metaDataValues(md codec.MetaData, name string) []string {
+	if value, ok := md[name]; ok {
+		return []string{string(value)}
+	}
+	for key, value := range md {
+		if strings.EqualFold(key, name) {
+			return []string{string(value)}
+		}
+	}
+	return nil
+}
+
 type trpcStatusCodeExtractor[REQUEST trpcReq, RESPONSE trpcRes] struct {
 }
 
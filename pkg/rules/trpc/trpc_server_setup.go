@@ -44,10 +44,10 @@ func serverTrpcOnEnter(call api.CallContext, _ interface{}, ctx context.Context,
 
 //go:linkname serverTrpcOnExit trpc.group/trpc-go/trpc-go/server.serverTrpcOnExit
 func serverTrpcOnExit(call api.CallContext, _ interface{}, err error) {
-	if !trpcEnabler.Enable() {
+	data, ok := call.GetData().(map[string]interface{})
+	if !ok {
 		return
 	}
-	data := call.GetData().(map[string]interface{})
 	ctx := data["ctx"].(context.Context)
 	request := data["request"].(trpcReq)
 	statusCode := 0
@@ -19,46 +19,71 @@ import (
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logbridge"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logcorrelation"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
+	otellog "go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-type zapInnerEnabler struct {
-	enabled bool
-}
-
-func (z zapInnerEnabler) Enable() bool {
-	return z.enabled
-}
-
-var zapEnabler = zapInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_ZAP_ENABLED") != "false"}
+var zapEnabler = instrumenter.RegisterInstrumentEnabler(utils.ZAP_SCOPE_NAME, "zap",
+	os.Getenv("OTEL_INSTRUMENTATION_ZAP_ENABLED") != "false")
 
 //go:linkname zapLogWriteOnEnter go.uber.org/zap/zapcore.zapLogWriteOnEnter
 func zapLogWriteOnEnter(call api.CallContext, ce *zapcore.CheckedEntry, fields ...zap.Field) {
 	if !zapEnabler.Enable() {
 		return
 	}
-	var traceIdOk, spanIdOk bool
-	if fields != nil {
-		for _, v := range fields {
-			if v.Key == "trace_id" {
-				traceIdOk = true
-			} else if v.Key == "span_id" {
-				spanIdOk = true
-			}
-		}
+	if ce != nil && logbridge.Enabled() {
+		emitZapEntry(ce.Entry, fields)
 	}
-	if !traceIdOk {
-		traceId, spanId := trace.GetTraceAndSpanId()
-		if traceId != "" {
-			fields = append(fields, zap.String("trace_id", traceId))
-		}
-		if spanId != "" && !spanIdOk {
-			fields = append(fields, zap.String("span_id", spanId))
+	correlation := logcorrelation.SpanFields(trace.SpanFromGLS())
+	if len(correlation) == 0 {
+		return
+	}
+	present := make(map[string]bool, len(fields))
+	for _, v := range fields {
+		present[v.Key] = true
+	}
+	for _, field := range correlation {
+		if !present[field.Key] {
+			fields = append(fields, zap.String(field.Key, field.Value))
 		}
-		call.SetParam(1, fields)
 	}
+	call.SetParam(1, fields)
 
 	return
 }
+
+var zapSeverities = map[zapcore.Level]otellog.Severity{
+	zapcore.DebugLevel:  otellog.SeverityDebug,
+	zapcore.InfoLevel:   otellog.SeverityInfo,
+	zapcore.WarnLevel:   otellog.SeverityWarn,
+	zapcore.ErrorLevel:  otellog.SeverityError,
+	zapcore.DPanicLevel: otellog.SeverityFatal1,
+	zapcore.PanicLevel:  otellog.SeverityFatal2,
+	zapcore.FatalLevel:  otellog.SeverityFatal3,
+}
+
+// emitZapEntry bridges the entry to the logs signal, the fields are encoded
+// as the attributes
+func emitZapEntry(entry zapcore.Entry, fields []zap.Field) {
+	var record otellog.Record
+	record.SetTimestamp(entry.Time)
+	record.SetBody(otellog.StringValue(entry.Message))
+	record.SetSeverity(zapSeverities[entry.Level])
+	record.SetSeverityText(entry.Level.String())
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	attrs := make([]otellog.KeyValue, 0, len(enc.Fields))
+	for key, value := range enc.Fields {
+		attrs = append(attrs, logbridge.KeyValue(key, value))
+	}
+	record.AddAttributes(attrs...)
+	logbridge.Emit(utils.ZAP_SCOPE_NAME, trace.SpanFromGLS(), record)
+}
@@ -15,33 +15,102 @@
 package zerolog
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"reflect"
+	"time"
 	_ "unsafe"
 
 	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logbridge"
+	"github.com/alibaba/loongsuite-go-agent/pkg/core/logcorrelation"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/instrumenter"
+	"github.com/alibaba/loongsuite-go-agent/pkg/inst-api/utils"
 	"github.com/rs/zerolog"
+	otellog "go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-type zeroLogInnerEnabler struct {
-	enabled bool
-}
-
-func (z zeroLogInnerEnabler) Enable() bool {
-	return z.enabled
-}
-
-var zeroLogEnabler = zeroLogInnerEnabler{os.Getenv("OTEL_INSTRUMENTATION_ZEROLOG_ENABLED") != "false"}
+var zeroLogEnabler = instrumenter.RegisterInstrumentEnabler(utils.ZEROLOG_SCOPE_NAME, "zerolog",
+	os.Getenv("OTEL_INSTRUMENTATION_ZEROLOG_ENABLED") != "false")
 
 //go:linkname zeroLogWriteOnEnter github.com/rs/zerolog.zeroLogWriteOnEnter
 func zeroLogWriteOnEnter(call api.CallContext, ce *zerolog.Event, msg string) {
 	if !zeroLogEnabler.Enable() {
 		return
 	}
-	traceId, spanId := trace.GetTraceAndSpanId()
-	if traceId != "" && spanId != "" {
-		cer := ce.Str("trace_id", traceId).Str("span_id", spanId)
-		call.SetParam(0, cer)
+	if ce != nil && logbridge.Enabled() {
+		emitZeroLogEvent(ce, msg)
 	}
+	correlation := logcorrelation.SpanFields(trace.SpanFromGLS())
+	if len(correlation) == 0 {
+		return
+	}
+	for _, field := range correlation {
+		ce = ce.Str(field.Key, field.Value)
+	}
+	call.SetParam(0, ce)
 	return
 }
+
+var zeroLogSeverities = map[zerolog.Level]otellog.Severity{
+	zerolog.DebugLevel: otellog.SeverityDebug,
+	zerolog.InfoLevel:  otellog.SeverityInfo,
+	zerolog.WarnLevel:  otellog.SeverityWarn,
+	zerolog.ErrorLevel: otellog.SeverityError,
+	zerolog.FatalLevel: otellog.SeverityFatal,
+	zerolog.PanicLevel: otellog.SeverityFatal4,
+}
+
+// emitZeroLogEvent bridges the event to the logs signal. The event keeps its
+// level and the encoded fields unexported, so they are read by reflection, and
+// the fields are decoded as the attributes if they are encoded as JSON.
+func emitZeroLogEvent(ce *zerolog.Event, msg string) {
+	event := reflect.ValueOf(ce).Elem()
+	levelField, bufField := event.FieldByName("level"), event.FieldByName("buf")
+	if !levelField.IsValid() || !bufField.IsValid() {
+		return
+	}
+	level := zerolog.Level(levelField.Int())
+	var record otellog.Record
+	record.SetTimestamp(time.Now())
+	record.SetBody(otellog.StringValue(msg))
+	if level < zerolog.DebugLevel {
+		// The trace level is absent from the early versions
+		record.SetSeverity(otellog.SeverityTrace)
+	} else {
+		record.SetSeverity(zeroLogSeverities[level])
+	}
+	record.SetSeverityText(level.String())
+	record.AddAttributes(decodeZeroLogFields(bufField.Bytes())...)
+	logbridge.Emit(utils.ZEROLOG_SCOPE_NAME, trace.SpanFromGLS(), record)
+}
+
+// decodeZeroLogFields decodes the fields of the unfinished JSON object, the
+// level field is dropped since it's already the severity of the record
+func decodeZeroLogFields(buf []byte) []otellog.KeyValue {
+	data := make([]byte, 0, len(buf)+1)
+	data = append(append(data, buf...), '}')
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return nil
+	}
+	attrs := make([]otellog.KeyValue, 0, len(fields))
+	for key, value := range fields {
+		if key == zerolog.LevelFieldName {
+			continue
+		}
+		if number, ok := value.(json.Number); ok {
+			if i, err := number.Int64(); err == nil {
+				value = i
+			} else if f, err := number.Float64(); err == nil {
+				value = f
+			}
+		}
+		attrs = append(attrs, logbridge.KeyValue(key, value))
+	}
+	return attrs
+}
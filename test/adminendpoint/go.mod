@@ -0,0 +1,3 @@
+module adminendpoint
+
+go 1.23
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func request(addr, path string) {
+	resp, err := http.Get("http://" + addr + path)
+	if err != nil {
+		panic(err)
+	}
+	_ = resp.Body.Close()
+}
+
+// admin sends the request to the admin endpoint listening on the unix socket
+func admin(action string) {
+	sock := strings.TrimPrefix(os.Getenv("OTEL_EXPERIMENTAL_ADMIN_ENDPOINT"), "unix:")
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+	resp, err := client.Post("http://otel/instrumentations/"+
+		"loongsuite.instrumentation.nethttp/"+action, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		panic(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		panic(fmt.Sprintf("admin endpoint returns %d: %s", resp.StatusCode, body))
+	}
+	fmt.Printf("admin %s: %s", action, body)
+}
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	go func() { _ = http.Serve(listener, nil) }()
+	addr := listener.Addr().String()
+
+	request(addr, "/before")
+	// Turn off the net/http instrumentation at runtime through the admin
+	// endpoint, and turn it back on by dropping the runtime value
+	admin("disable")
+	request(addr, "/during")
+	admin("reset")
+	request(addr, "/after")
+	fmt.Println("Admin endpoint test completed")
+}
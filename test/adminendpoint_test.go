@@ -0,0 +1,41 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAdminEndpoint(t *testing.T) {
+	const AppName = "adminendpoint"
+	UseApp(AppName)
+	RunGoBuild(t, "go", "build")
+	env := []string{
+		"OTEL_EXPERIMENTAL_ADMIN_ENDPOINT=unix:" +
+			filepath.Join(t.TempDir(), "otel.sock"),
+		"OTEL_TRACES_EXPORTER=console",
+		"IN_OTEL_TEST=false", // Use the console exporter
+	}
+	stdout, stderr := RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, "Admin endpoint test completed")
+	ExpectContains(t, stderr, "Serving admin endpoint")
+	ExpectContains(t, stdout, `"enabled": false`)
+	ExpectContains(t, stdout, `"source": "runtime"`)
+	// Spans are not produced while the instrumentation is disabled
+	ExpectContains(t, stdout, `"/before"`)
+	ExpectContains(t, stdout, `"/after"`)
+	ExpectNotContains(t, stdout, `"/during"`)
+}
@@ -0,0 +1,3 @@
+module agentconfig
+
+go 1.23
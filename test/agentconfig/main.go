@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func request(addr, path string) {
+	resp, err := http.Get("http://" + addr + path)
+	if err != nil {
+		panic(err)
+	}
+	_ = resp.Body.Close()
+}
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	go func() { _ = http.Serve(listener, nil) }()
+	addr := listener.Addr().String()
+
+	request(addr, "/enabled")
+
+	// Turn off the net/http instrumentation at runtime by updating the config
+	// file, it should take effect without restarting the process
+	file := os.Getenv("OTEL_EXPERIMENTAL_CONFIG_FILE")
+	content, err := os.ReadFile(file)
+	if err != nil {
+		panic(err)
+	}
+	updated := strings.Replace(string(content), "enabled: true",
+		"enabled: false", 1)
+	err = os.WriteFile(file, []byte(updated), 0644)
+	if err != nil {
+		panic(err)
+	}
+	time.Sleep(1 * time.Second)
+
+	request(addr, "/disabled")
+	fmt.Println("Agent config test completed")
+}
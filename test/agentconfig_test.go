@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAgentConfigFile(t *testing.T) {
+	const AppName = "agentconfig"
+	UseApp(AppName)
+	RunGoBuild(t, "go", "build")
+
+	// The app modifies the config file, work on a copy of it
+	content, err := os.ReadFile("otel.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "otel.yaml")
+	err = os.WriteFile(file, content, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := []string{
+		"OTEL_EXPERIMENTAL_CONFIG_FILE=" + file,
+		"OTEL_EXPERIMENTAL_CONFIG_RELOAD_INTERVAL=100",
+		"IN_OTEL_TEST=false", // Use the exporters of the config file
+	}
+	stdout, stderr := RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, "Agent config test completed")
+	// Spans are exported by the console exporter of the config file, and the
+	// attribute values are truncated by the attribute limits
+	ExpectContains(t, stdout, `"/enabled"`)
+	ExpectNotContains(t, stdout, "http://127.0.0.1")
+	// The net/http instrumentation is disabled once the file is reloaded
+	ExpectContains(t, stderr, "Reloaded config file")
+	ExpectNotContains(t, stdout, `"/disable"`)
+}
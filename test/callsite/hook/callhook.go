@@ -0,0 +1,44 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+)
+
+func commandOnEnter(call api.CallContext) {
+	println("[CALL-TEST] enter", call.GetPackageName()+"."+call.GetFuncName(),
+		call.GetParamCount())
+	call.SetParam(1, []string{"world"})
+}
+
+func commandOnExit(call api.CallContext) {
+	println("[CALL-TEST] exit", call.GetFuncName(), call.GetReturnValCount())
+}
+
+func outputOnEnter(call api.CallContext) {
+	println("[CALL-TEST] enter", call.GetFuncName(), call.GetParam(0) != nil)
+	println("[CALL-TEST] describe", call.GetReceiverType(),
+		call.GetPackagePath(), filepath.Base(call.GetSourceFile()),
+		call.GetSourceLine() > 0, strings.Join(call.GetParamNames(), ","),
+		strings.Join(call.GetResultNames(), ","))
+}
+
+func writeStringOnExit(call api.CallContext) {
+	println("[CALL-TEST] exit WriteString", call.GetReturnVal(0).(int))
+}
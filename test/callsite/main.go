@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os/exec"
+	"strings"
+
+	"callsite/runner"
+)
+
+func main() {
+	var sb strings.Builder
+	sb.WriteString("hello")
+	// Same method name with a different signature
+	r := strings.NewReplacer("i", "I")
+	r.WriteString(&sb, "hi")
+	println(runner.Run(exec.Command("echo", sb.String())))
+}
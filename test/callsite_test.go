@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestCallRule(t *testing.T) {
+	const AppName = "callsite"
+	UseApp(AppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuild(t, "go", "build")
+	stdout, stderr := RunApp(t, AppName)
+	// The variadic parameter is changed by the onEnter hook
+	ExpectContains(t, stdout+stderr, "world")
+	ExpectContains(t, stderr, "[CALL-TEST] enter exec.Command 2")
+	ExpectContains(t, stderr, "[CALL-TEST] exit Command 1")
+	// The method is promoted by the embedded field
+	ExpectContains(t, stderr, "[CALL-TEST] enter Output true")
+	ExpectContains(t, stderr, "[CALL-TEST] describe *Cmd os/exec exec.go "+
+		"true c retVal0,retVal1")
+	ExpectContains(t, stderr, "[CALL-TEST] exit WriteString 5")
+	// Callees matched by the same rule have their own wrappers
+	ExpectContains(t, stderr, "[CALL-TEST] exit WriteString 2")
+	ExpectDebugLogContains(t, "Apply call rule")
+
+	// The manifest of applied rules is embedded into the binary
+	stdout = RunInspect(t, AppName)
+	ExpectContains(t, stdout, "Tool version:")
+	ExpectContains(t, stdout, "RuleJsonFiles:")
+	ExpectContains(t, stdout, "os/exec.Command")
+	stdout = RunInspect(t, "-json", AppName)
+	ExpectContains(t, stdout, `"Type": "call"`)
+}
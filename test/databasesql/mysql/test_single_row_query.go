@@ -60,10 +60,10 @@ func dbQuery() {
 		log.Fatal(err)
 	}
 	verifier.WaitAndAssertTraces(func(stubs []tracetest.SpanStubs) {
-		verifier.VerifyDbAttributes(stubs[0][0], "DROP", "mysql", "127.0.0.1", "DROP TABLE IF EXISTS users", "DROP", "", nil)
-		verifier.VerifyDbAttributes(stubs[1][0], "CREATE", "mysql", "127.0.0.1", "CREATE TABLE IF NOT EXISTS users (id char(255), name VARCHAR(255), age INTEGER)", "CREATE", "", nil)
+		verifier.VerifyDbAttributes(stubs[0][0], "DROP users", "mysql", "127.0.0.1", "DROP TABLE IF EXISTS users", "DROP", "users", nil)
+		verifier.VerifyDbAttributes(stubs[1][0], "CREATE users", "mysql", "127.0.0.1", "CREATE TABLE IF NOT EXISTS users (id char(?), name VARCHAR(?), age INTEGER)", "CREATE", "users", nil)
 		verifier.VerifyDbAttributes(stubs[2][0], "INSERT users", "mysql", "127.0.0.1", "INSERT INTO users (id, name, age) VALUES ( ?, ?, ?)", "INSERT", "users", []any{"0", "foo", 10})
-		verifier.VerifyDbAttributes(stubs[3][0], "select users", "mysql", "127.0.0.1", "select name from users where id = ?", "select", "users", []any{0})
-		verifier.VerifyDbAttributes(stubs[4][0], "select users", "mysql", "127.0.0.1", "select name from users where id = ?", "select", "users", []any{0})
+		verifier.VerifyDbAttributes(stubs[3][0], "SELECT users", "mysql", "127.0.0.1", "select name from users where id = ?", "SELECT", "users", []any{0})
+		verifier.VerifyDbAttributes(stubs[4][0], "SELECT users", "mysql", "127.0.0.1", "select name from users where id = ?", "SELECT", "users", []any{0})
 	}, 5)
 }
@@ -0,0 +1,5 @@
+module funcpattern
+
+go 1.22.0
+
+replace funcpatternhook => ./hook
@@ -0,0 +1,3 @@
+module funcpatternhook
+
+go 1.22
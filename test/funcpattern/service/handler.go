@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+type Handler struct {
+	count int
+}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+func (h *Handler) Hello(name string) string {
+	h.count++
+	return "hello " + name
+}
+
+func (h *Handler) Add(a, b int) (int, error) {
+	h.count++
+	return a + b, nil
+}
+
+func (h *Handler) Count() int {
+	return h.count
+}
+
+type Other struct{}
+
+func (Other) ByeAll() {}
+
+func (Other) Hello() {}
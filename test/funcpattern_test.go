@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+)
+
+func TestFuncPatternRule(t *testing.T) {
+	const AppName = "funcpattern"
+	UseApp(AppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuild(t, "go", "build")
+	stdout, stderr := RunApp(t, AppName)
+	ExpectContains(t, stdout+stderr, "hello otel")
+	// Methods of different signatures share the same hooks
+	ExpectContains(t, stderr, "[PATTERN-TEST] enter Hello 2")
+	ExpectContains(t, stderr, "[PATTERN-TEST] exit Hello 1")
+	ExpectContains(t, stderr, "[PATTERN-TEST] enter Add 3")
+	ExpectContains(t, stderr, "[PATTERN-TEST] exit Add 2")
+	ExpectContains(t, stderr, "[PATTERN-TEST] enter Count 1")
+	ExpectContains(t, stderr, "[PATTERN-TEST] enter other ByeAll")
+	ExpectNotContains(t, stderr, "[PATTERN-TEST] enter other Hello")
+	ExpectNotContains(t, stderr, "[PATTERN-TEST] enter NewHandler")
+	// The signature of Add mismatches the rule
+	ExpectDebugLogContains(t, "func (*Handler).Add(int, int) (int, error)")
+	// Instrumented functions are listed in the report, even if packages are
+	// reused from the build cache
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			RunGoBuild(t, "go", "build")
+		}
+		report := readLog(t, filepath.Join(util.TempBuildDir,
+			"instrumentation.json"))
+		ExpectContains(t, report, `"Target": "(*Handler).Hello"`)
+		ExpectContains(t, report, `"Target": "(Other).ByeAll"`)
+		ExpectContains(t, report, `"HookPackage": "funcpatternhook"`)
+		ExpectContains(t, report, `"ImportPath": "net/http"`)
+		ExpectNotContains(t, report, `"Target": "(Other).Hello"`)
+	}
+}
@@ -0,0 +1,5 @@
+module generics
+
+go 1.22.0
+
+replace genericshook => ./hook
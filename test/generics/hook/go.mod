@@ -0,0 +1,3 @@
+module genericshook
+
+go 1.22
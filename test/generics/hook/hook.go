@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	_ "unsafe"
+
+	"github.com/alibaba/loongsuite-go-agent/pkg/api"
+)
+
+// Parameters of the type parameter types are declared as interface{}
+
+//go:linkname repoGetOnEnter generics/service.repoGetOnEnter
+func repoGetOnEnter(call api.CallContext, recv interface{}, id int) {
+	println("[GENERICS-TEST] enter Get", fmt.Sprintf("%T", recv), id)
+	// Redirect to the existing item
+	call.SetParam(1, 1)
+}
+
+//go:linkname repoGetOnExit generics/service.repoGetOnExit
+func repoGetOnExit(call api.CallContext, v interface{}, err error) {
+	println("[GENERICS-TEST] exit Get", v.(string), err == nil)
+}
+
+func repoOnEnter(call api.CallContext) {
+	println("[GENERICS-TEST] enter", call.GetFuncName(), call.GetParamCount())
+	println("[GENERICS-TEST] describe", call.GetReceiverType(),
+		call.GetPackagePath(), call.GetPackageName(),
+		fmt.Sprintf("%s:%d", filepath.Base(call.GetSourceFile()),
+			call.GetSourceLine()),
+		strings.Join(call.GetParamNames(), ","))
+}
+
+//go:linkname setAddOnEnter generics/service.setAddOnEnter
+func setAddOnEnter(call api.CallContext, s interface{}, e interface{}) {
+	println("[GENERICS-TEST] enter Add", e.(int))
+}
+
+func funcOnExit(call api.CallContext) {
+	println("[GENERICS-TEST] exit", call.GetFuncName(), call.GetReturnValCount(),
+		strings.Join(call.GetResultNames(), ","))
+	if _, ok := call.GetReturnVal(0).(int); ok {
+		call.SetReturnVal(0, 42)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strconv"
+
+	"generics/service"
+)
+
+func main() {
+	repo := service.NewRepo[string]()
+	repo.Put(1, "one")
+	v, err := repo.Get(0)
+	println("get", v, err == nil)
+	println("len", repo.Len())
+	set := service.Set[int]{}
+	set.Add(7)
+	strs := service.Map([]int{1, 2}, strconv.Itoa)
+	println("map", len(strs))
+	println("zero", service.Zero[int](), service.Zero[string]() == "")
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import "errors"
+
+func (r *Repo[T]) Put(id int, v T) {
+	r.items[id] = v
+}
+
+func (r *Repo[T]) Get(id int) (T, error) {
+	v, ok := r.items[id]
+	if !ok {
+		return v, errors.New("not found")
+	}
+	return v, nil
+}
+
+func (r *Repo[_]) Len() int {
+	return len(r.items)
+}
+
+// The type parameter is named differently from the type declaration
+func (s Set[E]) Add(e E) {
+	s[e] = struct{}{}
+}
+
+func Map[S ~[]E, E, R any](s S, f func(E) R) []R {
+	result := make([]R, 0, len(s))
+	for _, e := range s {
+		result = append(result, f(e))
+	}
+	return result
+}
+
+// The type parameter can not be inferred from the arguments
+func Zero[T any]() T {
+	var zero T
+	return zero
+}
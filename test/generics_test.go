@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+)
+
+func TestGenericsRule(t *testing.T) {
+	const AppName = "generics"
+	UseApp(AppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuild(t, "go", "build")
+	stdout, stderr := RunApp(t, AppName)
+	// Methods of generic types, with exact and pattern hooks
+	ExpectContains(t, stderr, "[GENERICS-TEST] enter Put 3")
+	ExpectContains(t, stderr, "[GENERICS-TEST] enter Get *service.Repo[string] 0")
+	ExpectContains(t, stderr, "[GENERICS-TEST] exit Get one true")
+	ExpectContains(t, stderr, "get one true")
+	ExpectContains(t, stderr, "[GENERICS-TEST] enter Len 1")
+	// Information of the target function is reported to the shared hook
+	ExpectContains(t, stderr, "[GENERICS-TEST] describe *Repo "+
+		"generics/service service generic.go:19 r,id,v")
+	ExpectContains(t, stderr, "[GENERICS-TEST] describe *Repo "+
+		"generics/service service generic.go:31 r")
+	ExpectContains(t, stderr, "[GENERICS-TEST] enter Add 7")
+	// Generic functions, return values of type parameter types are modifiable
+	ExpectContains(t, stderr, "[GENERICS-TEST] exit Map 1 retVal0")
+	ExpectContains(t, stderr, "[GENERICS-TEST] exit Zero 1 retVal0")
+	ExpectContains(t, stderr, "map 2")
+	ExpectContains(t, stderr, "zero 42 true")
+	ExpectNotContains(t, stdout+stderr, "failed to exec")
+	report := readLog(t, filepath.Join(util.TempBuildDir,
+		"instrumentation.json"))
+	ExpectContains(t, report, `"Target": "(*Repo).Get"`)
+	ExpectContains(t, report, `"Target": "(Set).Add"`)
+	ExpectContains(t, report, `"Target": "Zero"`)
+}
@@ -0,0 +1,3 @@
+module gotest
+
+go 1.22.0
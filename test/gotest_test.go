@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+)
+
+const GoTestAppName = "gotest"
+
+var goTestEnv = []string{
+	"OTEL_TRACES_EXPORTER=console",
+	"OTEL_METRICS_EXPORTER=none",
+	"IN_OTEL_TEST=false",
+}
+
+func TestGoTest(t *testing.T) {
+	UseApp(GoTestAppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuildWithEnv(t, goTestEnv, "go", "test", "-v", "-count=1", "./...")
+	stdout := readStdoutLog(t)
+	// Both the internal and the external test packages are run
+	ExpectContains(t, stdout, "--- PASS: TestSum")
+	ExpectContains(t, stdout, "--- PASS: TestSquare")
+	ExpectContains(t, stdout, `"Name":"util.Add"`)
+	ExpectContains(t, stdout, `"Name":"util.Mul"`)
+}
+
+func TestGoTestCover(t *testing.T) {
+	UseApp(GoTestAppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuildWithEnv(t, goTestEnv, "go", "test", "-v", "-count=1",
+		"-cover", "./calc")
+	stdout := readStdoutLog(t)
+	ExpectContains(t, stdout, "coverage:")
+	// The covered package is compiled from generated sources, while its
+	// dependencies are still instrumented
+	ExpectContains(t, stdout, `"Name":"util.Add"`)
+	ExpectContains(t, stdout, `"Name":"util.Mul"`)
+}
+
+func TestGoTestNoTestFiles(t *testing.T) {
+	UseApp(GoTestAppName)
+	RunSet(t, "-rule=rule.json")
+	// Packages without test files are reported as usual rather than failing
+	RunGoBuildWithEnv(t, goTestEnv, "go", "test", "-count=1", "./util")
+	stdout := readStdoutLog(t)
+	ExpectContains(t, stdout, "[no test files]")
+}
+
+func TestGoTestBinary(t *testing.T) {
+	UseApp(GoTestAppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuild(t, "go", "test", "-c", "-o", "calc.test", "./calc")
+	stdout, _ := RunApp(t, "calc.test", goTestEnv...)
+	ExpectContains(t, stdout, "PASS")
+	ExpectContains(t, stdout, `"Name":"util.Add"`)
+	ExpectContains(t, stdout, `"Name":"util.Mul"`)
+}
+
+func TestGoRun(t *testing.T) {
+	UseApp(GoTestAppName)
+	RunSet(t, "-rule=rule.json")
+	// Arguments following the main package are passed to the program
+	RunGoBuildWithEnv(t, goTestEnv, "go", "run", ".", "hello", "-v")
+	stdout := readStdoutLog(t)
+	ExpectContains(t, stdout, "args [hello -v]")
+	ExpectContains(t, stdout, "sum 4")
+	ExpectContains(t, stdout, `"Name":"util.Add"`)
+	ExpectNotContains(t, stdout, `"Name":"util.Mul"`)
+}
@@ -97,6 +97,18 @@ func RunVersion(t *testing.T) {
 	}
 }
 
+func RunInspect(t *testing.T, args ...string) string {
+	util.Assert(pwd != "", "pwd is empty")
+	path := filepath.Join(filepath.Dir(pwd), getExecName())
+	cmd := runCmd(append([]string{path, "inspect"}, args...))
+	err := cmd.Run()
+	if err != nil {
+		t.Log(readStderrLog(t))
+		t.Fatal(err)
+	}
+	return readStdoutLog(t)
+}
+
 func RunSet(t *testing.T, args ...string) {
 	util.Assert(pwd != "", "pwd is empty")
 	path := filepath.Join(filepath.Dir(pwd), getExecName())
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+)
+
+func main() {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(err)
+	}
+	slogger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	zlogger := zerolog.New(os.Stderr)
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		logger.Warn("zap bridged", zap.String("user", "alice"))
+		slogger.Info("slog bridged", "order", 42)
+		slogger.Debug("slog disabled")
+		log.Printf("log bridged")
+		logrus.WithField("retry", true).Error("logrus bridged")
+		zlogger.Info().Str("region", "hz").Int("attempt", 3).Msg("zerolog bridged")
+		_, _ = w.Write([]byte("ok"))
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	go func() { _ = http.Serve(listener, nil) }()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/")
+	if err != nil {
+		panic(err)
+	}
+	_ = resp.Body.Close()
+	_ = logger.Sync()
+	fmt.Println("Log bridge test completed")
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogBridge(t *testing.T) {
+	const AppName = "logbridge"
+	UseApp(AppName)
+	RunGoBuild(t, "go", "build")
+	env := []string{
+		"OTEL_LOGS_EXPORTER=console",
+		"OTEL_TRACES_EXPORTER=console",
+		"OTEL_METRICS_EXPORTER=none",
+		"IN_OTEL_TEST=false", // Use the console exporter
+	}
+	stdout, _ := RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, "Log bridge test completed")
+	ExpectNotContains(t, stdout, "slog disabled")
+	for _, expect := range []string{
+		`"SeverityText":"warn","Body":{"Type":"String","Value":"zap bridged"},"Attributes":[{"Key":"user"`,
+		`"SeverityText":"INFO","Body":{"Type":"String","Value":"slog bridged"},"Attributes":[{"Key":"order"`,
+		`"Body":{"Type":"String","Value":"log bridged"}`,
+		`"SeverityText":"error","Body":{"Type":"String","Value":"logrus bridged"},"Attributes":[{"Key":"retry","Value":{"Type":"Bool","Value":true}}]`,
+		`"Body":{"Type":"String","Value":"zerolog bridged"}`,
+		`{"Key":"attempt","Value":{"Type":"Int64","Value":3}}`,
+		`{"Key":"region","Value":{"Type":"String","Value":"hz"}}`,
+	} {
+		ExpectContains(t, stdout, expect)
+	}
+	// The records are correlated with the server span
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.Contains(line, " bridged\"}") {
+			ExpectNotContains(t, line, `"TraceID":"00000000000000000000000000000000"`)
+		}
+	}
+}
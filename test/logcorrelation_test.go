@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestLogCorrelation(t *testing.T) {
+	const AppName = "logbridge"
+	UseApp(AppName)
+	RunGoBuild(t, "go", "build")
+	env := []string{
+		"OTEL_LOGS_CORRELATION_FIELDS=trace_id=traceId,span_id=spanId," +
+			"sampled,service_name=service,traceparent",
+		"OTEL_SERVICE_NAME=logcorrelation",
+		"OTEL_TRACES_EXPORTER=console",
+		"OTEL_METRICS_EXPORTER=none",
+		"IN_OTEL_TEST=false",
+	}
+	stdout, stderr := RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, "Log bridge test completed")
+	for _, expect := range []string{
+		// zap
+		`"user":"alice","traceId":"`,
+		`"sampled":"true","service":"logcorrelation","traceparent":"00-`,
+		// slog
+		`slog bridged traceId=`,
+		" sampled=true service=logcorrelation traceparent=00-",
+		// log, the fields are written right after the prefix
+		"  traceId=",
+		// logrus
+		"service=logcorrelation",
+		// zerolog
+		`"attempt":3,"traceId":"`,
+	} {
+		ExpectContains(t, stderr, expect)
+	}
+	ExpectNotContains(t, stderr, "trace_id")
+	ExpectNotContains(t, stderr, "span_id")
+}
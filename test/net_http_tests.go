@@ -22,6 +22,7 @@ func init() {
 		NewGeneralTestCase("nethttp-http-2-test", "nethttp", "", "", "1.18", "", TestHttp2),
 		NewGeneralTestCase("nethttp-https-test", "nethttp", "", "", "1.18", "", TestHttps),
 		NewGeneralTestCase("nethttp-metric-test", "nethttp", "", "", "1.18", "", TestHttpMetric),
+		NewGeneralTestCase("nethttp-capture-headers-test", "nethttp", "", "", "1.18", "", TestHttpCaptureHeaders),
 	)
 }
 
@@ -48,3 +49,14 @@ func TestHttpMetric(t *testing.T, env ...string) {
 	RunGoBuild(t, "go", "build", "test_http_metrics.go", "http_server.go")
 	RunApp(t, "test_http_metrics", env...)
 }
+
+func TestHttpCaptureHeaders(t *testing.T, env ...string) {
+	UseApp("nethttp")
+	RunGoBuild(t, "go", "build", "test_http_headers.go", "http_server.go")
+	env = append(env,
+		"OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_CLIENT_REQUEST=X-Request-Id",
+		"OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_CLIENT_RESPONSE=X-Response-Id",
+		"OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_REQUEST=x-request-id",
+		"OTEL_INSTRUMENTATION_HTTP_CAPTURE_HEADERS_SERVER_RESPONSE=x-response-id")
+	RunApp(t, "test_http_headers", env...)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alibaba/loongsuite-go-agent/test/verifier"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func headersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Response-Id", "resp-1")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("success"))
+}
+
+func setupHeadersHttp() {
+	http.HandleFunc("/headers", headersHandler)
+	var err error
+	port, err = verifier.GetFreePort()
+	if err != nil {
+		panic(err)
+	}
+	err = http.ListenAndServe(":"+strconv.Itoa(port), nil)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func expectHeader(span tracetest.SpanStub, name, value string) {
+	actual := verifier.GetAttribute(span.Attributes, name)
+	verifier.Assert(actual.Type() == attribute.STRINGSLICE &&
+		len(actual.AsStringSlice()) == 1 && actual.AsStringSlice()[0] == value,
+		"Except %s to be [%s], got %v", name, value, actual.Emit())
+}
+
+func main() {
+	go setupHeadersHttp()
+	time.Sleep(1 * time.Second)
+	req, err := http.NewRequest("GET", "http://127.0.0.1:"+strconv.Itoa(port)+"/headers", nil)
+	if err != nil {
+		panic(err)
+	}
+	req.Header.Set("X-Request-Id", "req-1")
+	req.Header.Set("X-Secret", "secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+	verifier.WaitAndAssertTraces(func(stubs []tracetest.SpanStubs) {
+		client, server := stubs[0][0], stubs[0][1]
+		expectHeader(client, "http.request.header.x-request-id", "req-1")
+		expectHeader(client, "http.response.header.x-response-id", "resp-1")
+		expectHeader(server, "http.request.header.x-request-id", "req-1")
+		expectHeader(server, "http.response.header.x-response-id", "resp-1")
+		for _, span := range []tracetest.SpanStub{client, server} {
+			secret := verifier.GetAttribute(span.Attributes, "http.request.header.x-secret")
+			verifier.Assert(secret.Type() == attribute.INVALID,
+				"Except x-secret not to be captured, got %v", secret.Emit())
+		}
+	}, 1)
+}
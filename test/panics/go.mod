@@ -0,0 +1,5 @@
+module panics
+
+go 1.22.0
+
+replace panicshook => ./hook
@@ -0,0 +1,3 @@
+module panicshook
+
+go 1.22
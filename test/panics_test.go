@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestRecoverPanic(t *testing.T) {
+	const AppName = "panics"
+	UseApp(AppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuild(t, "go", "build")
+	env := []string{
+		"OTEL_TRACES_EXPORTER=console",
+		"OTEL_METRICS_EXPORTER=none",
+		"IN_OTEL_TEST=false",
+	}
+	// Panics raised by hooks are recovered, while panics passing through the
+	// instrumented functions are left untouched by default
+	stdout, stderr := RunApp(t, AppName, env...)
+	ExpectContains(t, stderr, "failed to exec onEnter hook greetOnEnter")
+	ExpectContains(t, stdout, "hello bob")
+	ExpectContains(t, stdout, "quotient 2")
+	ExpectContains(t, stdout, "recovered: runtime error: integer divide by zero")
+	ExpectContains(t, stdout, `"Name":"service.Divide"`)
+	ExpectNotContains(t, stdout, "exception.stacktrace")
+
+	// Panics are recorded on the current span and propagated as they are
+	env = append(env, "OTEL_INSTRUMENTATION_RECORD_PANIC_ENABLED=true")
+	stdout, _ = RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, "recovered: runtime error: integer divide by zero")
+	ExpectContains(t, stdout,
+		`{"Key":"exception.type","Value":{"Type":"STRING","Value":"runtime.errorString"}}`)
+	ExpectContains(t, stdout,
+		`{"Key":"exception.message","Value":{"Type":"STRING","Value":"runtime error: integer divide by zero"}}`)
+	ExpectContains(t, stdout, `{"Key":"exception.stacktrace"`)
+	ExpectContains(t, stdout, "panics/service.Divide(")
+	ExpectContains(t, stdout,
+		`"Status":{"Code":"Error","Description":"panic: runtime error: integer divide by zero"}`)
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestRawRule(t *testing.T) {
+	UseApp(HelloworldAppName)
+
+	RunSet(t, UseTestRules("test_raw.json"))
+	RunGoBuild(t, "go", "build")
+	stdout, stderr := RunApp(t, HelloworldAppName)
+	ExpectContains(t, stdout, "helloworld")
+	ExpectContains(t, stderr, "[RAW-TEST] enter Printf")
+	ExpectContains(t, stderr, "[RAW-TEST] exit Printf 22")
+	ExpectContains(t, stderr, "[RAW-TEST] enter doPrintf helloworld%s")
+	ExpectContains(t, stderr, "[RAW-TEST] exit Every true")
+	ExpectDebugLogContains(t, "Match raw rule")
+}
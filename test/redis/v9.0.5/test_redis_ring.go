@@ -48,7 +48,7 @@ func main() {
 	fmt.Printf("%v\n", val)
 	verifier.WaitAndAssertTraces(func(stubs []tracetest.SpanStubs) {
 		verifier.VerifyDbAttributes(stubs[0][0], "command", "redis", "localhost", "command", "command", "", nil)
-		verifier.VerifyDbAttributes(stubs[1][0], "hset", "redis", "localhost", "hset a key1 1 key2 2", "hset", "", nil)
+		verifier.VerifyDbAttributes(stubs[1][0], "hset", "redis", "localhost", "hset a ? ? ? ?", "hset", "", nil)
 		verifier.VerifyDbAttributes(stubs[2][0], "hvals", "redis", "localhost", "hvals a", "hvals", "", nil)
 	}, 3)
 }
@@ -0,0 +1,3 @@
+module rulesampler
+
+go 1.23
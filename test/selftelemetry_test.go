@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestSelfTelemetry(t *testing.T) {
+	const AppName = "panics"
+	UseApp(AppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuild(t, "go", "build")
+	env := []string{
+		"OTEL_TRACES_EXPORTER=console",
+		"OTEL_METRICS_EXPORTER=console",
+		"IN_OTEL_TEST=false",
+	}
+	stdout, _ := RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, "hello bob")
+	ExpectNotContains(t, stdout, "loongsuite.agent.hook")
+
+	env = append(env, "OTEL_INSTRUMENTATION_SELF_TELEMETRY_ENABLED=true")
+	stdout, _ = RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, `"Name":"loongsuite.agent"`)
+	ExpectContains(t, stdout, `"Name":"loongsuite.agent.hook.invocations"`)
+	ExpectContains(t, stdout, `"Name":"loongsuite.agent.hook.duration"`)
+	ExpectContains(t, stdout, `"Name":"loongsuite.agent.hook.panics"`)
+	ExpectContains(t, stdout,
+		`{"Key":"rule","Value":{"Type":"STRING","Value":"panics/service.Greet"}}`)
+	ExpectContains(t, stdout,
+		`{"Key":"hook","Value":{"Type":"STRING","Value":"greetOnEnter"}}`)
+	ExpectContains(t, stdout,
+		`{"Key":"rule","Value":{"Type":"STRING","Value":"panics/service.Divide"}}`)
+}
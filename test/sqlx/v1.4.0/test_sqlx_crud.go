@@ -93,7 +93,7 @@ func main() {
 	TestDropTable()
 	verifier.WaitAndAssertTraces(func(stubs []tracetest.SpanStubs) {
 		verifier.VerifyDbAttributes(stubs[0][0], "ping", "mysql", host, "ping", "ping", "", nil)
-		verifier.VerifyDbAttributes(stubs[1][0], "CREATE TABLE", "mysql", host, "CREATE TABLE IF NOT EXISTS users (id char(255), name VARCHAR(255), age INTEGER)", "CREATE TABLE", "", nil)
+		verifier.VerifyDbAttributes(stubs[1][0], "CREATE TABLE", "mysql", host, "CREATE TABLE IF NOT EXISTS users (id char(?), name VARCHAR(?), age INTEGER)", "CREATE TABLE", "", nil)
 		verifier.VerifyDbAttributes(stubs[2][0], "INSERT", "mysql", host, "INSERT INTO users (id, name, age) VALUES ( :id, :name, :age)", "INSERT", "", nil)
 		verifier.VerifyDbAttributes(stubs[3][0], "SELECT", "mysql", host, "select id, name from users where id = $1", "SELECT", "", nil)
 		verifier.VerifyDbAttributes(stubs[4][0], "UPDATE", "mysql", host, "UPDATE users set name = :name where id = :id", "UPDATE", "", nil)
@@ -0,0 +1,3 @@
+module tailsampling
+
+go 1.23
@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestTailSampling(t *testing.T) {
+	const AppName = "tailsampling"
+	UseApp(AppName)
+	RunGoBuild(t, "go", "build")
+	env := []string{
+		"OTEL_EXPERIMENTAL_TRACES_PROCESSOR=tail_sampling",
+		"OTEL_TRACES_EXPORTER=console",
+		"IN_OTEL_TEST=false", // Use the console exporter
+	}
+	stdout, _ := RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, "Tail sampling test completed")
+	ExpectContains(t, stdout, `"/fail"`)
+	ExpectNotContains(t, stdout, `"/ok"`)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"tracer/service"
+)
+
+func main() {
+	ctx := context.Background()
+	repo := service.NewRepo()
+	repo.Save(ctx, 7, "alice")
+	if name, err := repo.Find(ctx, 7); err == nil {
+		fmt.Println("found", name)
+	}
+	if _, err := repo.Find(ctx, 8); err != nil {
+		fmt.Println("find failed:", err)
+	}
+	fmt.Println("checksum", service.Checksum("hello"))
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+type Repo struct {
+	users map[int]string
+}
+
+func NewRepo() *Repo {
+	return &Repo{users: make(map[int]string)}
+}
+
+func (r *Repo) Save(ctx context.Context, id int, name string) {
+	r.users[id] = name
+}
+
+func (r *Repo) Find(ctx context.Context, id int) (string, error) {
+	name, ok := r.users[id]
+	if !ok {
+		return "", fmt.Errorf("user %d not found", id)
+	}
+	return name, nil
+}
+
+func Checksum(s string) int {
+	sum := 0
+	for _, c := range s {
+		sum += int(c)
+	}
+	return sum
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestTraceRule(t *testing.T) {
+	const AppName = "tracer"
+	UseApp(AppName)
+	RunSet(t, "-rule=rule.json")
+	RunGoBuild(t, "go", "build")
+	env := []string{
+		"OTEL_TRACES_EXPORTER=console",
+		"OTEL_METRICS_EXPORTER=none",
+		"IN_OTEL_TEST=false",
+	}
+	stdout, _ := RunApp(t, AppName, env...)
+	ExpectContains(t, stdout, "find failed: user 8 not found")
+	// Span names are formatted by the template, or "package.Func" by default
+	ExpectContains(t, stdout, `"Name":"repo.Save"`)
+	ExpectContains(t, stdout, `"Name":"repo.Find"`)
+	ExpectContains(t, stdout, `"Name":"service.Checksum"`)
+	// Parameters are captured by index, the receiver is the first one
+	ExpectContains(t, stdout,
+		`{"Key":"user.id","Value":{"Type":"INT64","Value":7}}`)
+	ExpectContains(t, stdout,
+		`{"Key":"user.name","Value":{"Type":"STRING","Value":"alice"}}`)
+	ExpectContains(t, stdout,
+		`{"Key":"code.function.name","Value":{"Type":"STRING","Value":"Checksum"}}`)
+	ExpectContains(t, stdout,
+		`{"Key":"code.namespace","Value":{"Type":"STRING","Value":"tracer/service.Repo"}}`)
+	// The returned error is recorded as the span status
+	ExpectContains(t, stdout,
+		`{"Key":"exception.message","Value":{"Type":"STRING","Value":"user 8 not found"}}`)
+	ExpectContains(t, stdout, `"Status":{"Code":"Error"`)
+}
@@ -56,6 +56,14 @@ func (ap *AstParser) FindPosition(node dst.Node) token.Position {
 	return ap.fset.Position(astNode.Pos())
 }
 
+func (ap *AstParser) FindEndPosition(node dst.Node) token.Position {
+	astNode := ap.dec.Ast.Nodes[node]
+	if astNode == nil {
+		return token.Position{Filename: "", Line: -1, Column: -1} // Invalid
+	}
+	return ap.fset.Position(astNode.End())
+}
+
 // ParseSnippet parses the AST from incomplete source code snippet.
 func (ap *AstParser) ParseSnippet(codeSnippet string) ([]dst.Stmt, error) {
 	util.Assert(codeSnippet != "", "empty code snippet")
@@ -105,6 +105,15 @@ func IndexExpr(x dst.Expr, index dst.Expr) *dst.IndexExpr {
 	}
 }
 
+// IndexListExpr instantiates the generic x with the type arguments, i.e. x[T]
+// or x[K, V]
+func IndexListExpr(x dst.Expr, indices []dst.Expr) dst.Expr {
+	if len(indices) == 1 {
+		return &dst.IndexExpr{X: x, Index: indices[0]}
+	}
+	return &dst.IndexListExpr{X: x, Indices: indices}
+}
+
 func TypeAssertExpr(x dst.Expr, typ dst.Expr) *dst.TypeAssertExpr {
 	return &dst.TypeAssertExpr{
 		X:    x,
@@ -16,11 +16,17 @@ package ast
 
 import (
 	"fmt"
+	goast "go/ast"
+	"go/parser"
 	"go/token"
+	"go/types"
 	"regexp"
+	"strings"
 
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
 	"github.com/alibaba/loongsuite-go-agent/tool/util"
 	"github.com/dave/dst"
+	"github.com/dave/dst/decorator"
 )
 
 // -----------------------------------------------------------------------------
@@ -153,56 +159,84 @@ func FindFuncDeclWithoutRecv(root *dst.File, funcName string) *dst.FuncDecl {
 	return decls[0]
 }
 
+// splitReceiverType splits the receiver type into the type name and the type
+// parameters, e.g. "Foo" and [K, V] of *Foo[K, V]
+func splitReceiverType(recvTypeExpr dst.Expr) (string, []*dst.Ident) {
+	if star, ok := recvTypeExpr.(*dst.StarExpr); ok {
+		recvTypeExpr = star.X
+	}
+	var indices []dst.Expr
+	switch expr := recvTypeExpr.(type) {
+	case *dst.IndexExpr:
+		recvTypeExpr, indices = expr.X, []dst.Expr{expr.Index}
+	case *dst.IndexListExpr:
+		recvTypeExpr, indices = expr.X, expr.Indices
+	}
+	ident, ok := recvTypeExpr.(*dst.Ident)
+	if !ok {
+		msg := fmt.Sprintf("unexpected receiver type: %T", recvTypeExpr)
+		util.UnimplementedT(msg)
+	}
+	typeParams := make([]*dst.Ident, 0, len(indices))
+	for _, index := range indices {
+		typeParam, ok := index.(*dst.Ident)
+		util.Assert(ok, "receiver type parameter must be identifier")
+		typeParams = append(typeParams, typeParam)
+	}
+	return ident.Name, typeParams
+}
+
+// ReceiverTypeName returns the receiver type name of the function, e.g. "*Foo",
+// or an empty string if the function has no receiver. Type parameters of the
+// generic receiver type are omitted, e.g. "*Foo" for *Foo[T]
+func ReceiverTypeName(funcDecl *dst.FuncDecl) string {
+	if !HasReceiver(funcDecl) {
+		return ""
+	}
+	recvTypeExpr := funcDecl.Recv.List[0].Type
+	name, _ := splitReceiverType(recvTypeExpr)
+	if _, ok := recvTypeExpr.(*dst.StarExpr); ok {
+		return "*" + name
+	}
+	return name
+}
+
+// ReceiverTypeParams returns the type parameters of the generic receiver type,
+// e.g. [K, V] of *Foo[K, V], or nil if the receiver type is not generic
+func ReceiverTypeParams(funcDecl *dst.FuncDecl) []*dst.Ident {
+	if !HasReceiver(funcDecl) {
+		return nil
+	}
+	_, typeParams := splitReceiverType(funcDecl.Recv.List[0].Type)
+	if len(typeParams) == 0 {
+		return nil
+	}
+	return typeParams
+}
+
+// FindFuncDecl finds all functions whose name strictly matches the function
+// regexp and whose receiver type strictly matches the receiver type regexp. If
+// receiver type is not specified, only functions without receiver are matched.
+// Function declarations without body are never matched.
 func FindFuncDecl(root *dst.File, function string, receiverType string) []*dst.FuncDecl {
-	decls := findFuncDecls(root, func(funcDecl *dst.FuncDecl) bool {
-		return function == funcDecl.Name.Name
-	})
+	fnRe := regexp.MustCompile("^" + function + "$") // strict match
+	var recvRe *regexp.Regexp
 	if receiverType != "" {
-		filtered := make([]*dst.FuncDecl, 0)
-		for _, funcDecl := range decls {
-			if !HasReceiver(funcDecl) {
-				continue
-			}
-			re := regexp.MustCompile("^" + receiverType + "$") // strict match
-			if !HasReceiver(funcDecl) {
-				if re.MatchString("") {
-					filtered = append(filtered, funcDecl)
-				}
-			}
-			switch recvTypeExpr := funcDecl.Recv.List[0].Type.(type) {
-			case *dst.StarExpr:
-				if _, ok := recvTypeExpr.X.(*dst.Ident); !ok {
-					// This is a generic type, we don't support it yet
-					continue
-				}
-				t := "*" + recvTypeExpr.X.(*dst.Ident).Name
-				if re.MatchString(t) {
-					filtered = append(filtered, funcDecl)
-				}
-			case *dst.Ident:
-				t := recvTypeExpr.Name
-				if re.MatchString(t) {
-					filtered = append(filtered, funcDecl)
-				}
-			case *dst.IndexExpr:
-				// This is a generic type, we don't support it yet
-				continue
-			default:
-				msg := fmt.Sprintf("unexpected receiver type: %T", recvTypeExpr)
-				util.UnimplementedT(msg)
-			}
-		}
-		return filtered
+		recvRe = regexp.MustCompile("^" + receiverType + "$")
 	}
-	// Receiver type is not specified, return all functions without receiver
-	filtered := make([]*dst.FuncDecl, 0)
-	for _, funcDecl := range decls {
+	return findFuncDecls(root, func(funcDecl *dst.FuncDecl) bool {
+		if funcDecl.Body == nil || !fnRe.MatchString(funcDecl.Name.Name) {
+			return false
+		}
+		if recvRe == nil {
+			return !HasReceiver(funcDecl)
+		}
 		if !HasReceiver(funcDecl) {
-			filtered = append(filtered, funcDecl)
+			return recvRe.MatchString("")
 		}
-	}
-	return filtered
-
+		t := ReceiverTypeName(funcDecl)
+		return t != "" && recvRe.MatchString(t)
+	})
 }
 
 func ListFuncDecls(root *dst.File) []*dst.FuncDecl {
@@ -229,3 +263,93 @@ func FindStructDecl(root *dst.File, structName string) *dst.GenDecl {
 	}
 	return nil
 }
+
+// FindTypeSpec finds the type declaration of the given name
+func FindTypeSpec(root *dst.File, typeName string) *dst.TypeSpec {
+	for _, decl := range root.Decls {
+		genDecl, ok := decl.(*dst.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if typeSpec, ok := spec.(*dst.TypeSpec); ok &&
+				typeSpec.Name.Name == typeName {
+				return typeSpec
+			}
+		}
+	}
+	return nil
+}
+
+// TypeString returns the canonical form of the type expression, e.g. "[]*Foo",
+// which is the same as types.ExprString gives
+func TypeString(expr dst.Expr) string {
+	spec := &dst.ValueSpec{
+		Names: []*dst.Ident{Ident(IdentIgnore)},
+		Type:  dst.Clone(expr).(dst.Expr),
+	}
+	file := &dst.File{
+		Name:  Ident("_"),
+		Decls: []dst.Decl{&dst.GenDecl{Tok: token.VAR, Specs: []dst.Spec{spec}}},
+	}
+	astFile, err := decorator.NewRestorer().RestoreFile(file)
+	if err != nil {
+		return fmt.Sprintf("%T", expr)
+	}
+	astSpec := astFile.Decls[0].(*goast.GenDecl).Specs[0].(*goast.ValueSpec)
+	return types.ExprString(astSpec.Type)
+}
+
+// NormalizeType converts the type written by hand to its canonical form, so
+// that it can be compared with the result of TypeString
+func NormalizeType(typ string) (string, error) {
+	// Variadic parameter type is not a valid expression
+	if elem, ok := strings.CutPrefix(typ, "..."); ok {
+		normalized, err := NormalizeType(elem)
+		if err != nil {
+			return "", err
+		}
+		return "..." + normalized, nil
+	}
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return "", ex.Wrapf(err, "bad type %q", typ)
+	}
+	return types.ExprString(expr), nil
+}
+
+func fieldTypes(list *dst.FieldList) []string {
+	typs := make([]string, 0)
+	if list == nil {
+		return typs
+	}
+	for _, field := range list.List {
+		typ := TypeString(field.Type)
+		typs = append(typs, typ)
+		// Syntax of n1,n2 type
+		for i := 1; i < len(field.Names); i++ {
+			typs = append(typs, typ)
+		}
+	}
+	return typs
+}
+
+// FuncSignature returns types of parameters and results of the function, the
+// receiver is not included
+func FuncSignature(funcDecl *dst.FuncDecl) ([]string, []string) {
+	return fieldTypes(funcDecl.Type.Params), fieldTypes(funcDecl.Type.Results)
+}
+
+// SignatureString formats the parameter and result types as they appear in the
+// function type, e.g. "(context.Context, string) (int, error)"
+func SignatureString(params, results []string) string {
+	sig := "(" + strings.Join(params, ", ") + ")"
+	switch len(results) {
+	case 0:
+	case 1:
+		sig += " " + results[0]
+	default:
+		sig += " (" + strings.Join(results, ", ") + ")"
+	}
+	return sig
+}
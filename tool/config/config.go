@@ -60,6 +60,15 @@ type BuildConfig struct {
 	// PkgPath specifies the path of the package to be used across multiple
 	// instrumentations
 	PkgPath string
+
+	// ForceRebuild true means rebuild all packages from scratch, ignoring the
+	// previously instrumented objects in the build cache.
+	ForceRebuild bool
+
+	// Offline true means never access the network during preprocessing, all
+	// dependencies are resolved from the embedded pkg module and the local
+	// module cache.
+	Offline bool
 }
 
 var conf *BuildConfig
@@ -248,6 +257,10 @@ func Configure() error {
 		"Disable specific rules. Use 'all' to disable all default rules, or comma-separated list of rule file names to disable specific rules")
 	flag.StringVar(&bc.PkgPath, "pkg", bc.PkgPath,
 		"Specify the path of the package to be used across multiple instrumentations")
+	flag.BoolVar(&bc.ForceRebuild, "rebuild", bc.ForceRebuild,
+		"Force rebuilding all packages instead of reusing the instrumentation cache")
+	flag.BoolVar(&bc.Offline, "offline", bc.Offline,
+		"Resolve all dependencies locally without accessing the network")
 	err = flag.CommandLine.Parse(os.Args[2:])
 	if err != nil {
 		return ex.Wrap(err)
@@ -0,0 +1,688 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrument
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"go/version"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/ast"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+	"github.com/dave/dst"
+)
+
+// -----------------------------------------------------------------------------
+// Call Site Instrumentation
+//
+// Call rules instrument the calls to the given function within caller packages
+// rather than the function itself. Every matched call site is redirected to a
+// generic wrapper function, which takes the callee as the first argument, then
+// the receiver if any, and the original arguments, i.e.
+//
+//	rows, err := db.QueryContext(ctx, query)
+//
+// becomes
+//
+//	rows, err := OtelCallSite_QueryContext123(db.QueryContext, db, ctx, query)
+//
+// The wrapper jumps to the onEnter and onExit trampolines in the same way as
+// the trampoline-jump-if does, and then calls the callee. Since all types are
+// inferred from the callee, types of the callee package are never spelled out
+// in the caller package, even if they are unexported. The trampolines and the
+// CallContext implementation are generated from the same template as func rules
+// do, except that they are parameterized by the type parameters of the wrapper.
+//
+// Call sites are precisely located by type checking the caller package against
+// export data of its dependencies, so that methods promoted by embedded fields
+// are recognized as well. Hooks can not know all caller packages in advance, so
+// they are declared in the caller package and refer to the hook functions via
+// pull-style linkname directives. As hooks are shared by call sites of different
+// signatures, they only accept the CallContext parameter.
+
+const (
+	CallSiteName     = "OtelCallSite"
+	CallMinGoVersion = "go1.18"
+)
+
+type typeInfo struct {
+	fset  *token.FileSet
+	files map[string]*goast.File
+	info  *types.Info
+	lang  string
+}
+
+// callSite describes how to instrument a matched call
+type callSite struct {
+	callee *types.Func
+	fields []string // embedded fields to reach the receiver from the operand
+	addr   bool     // whether to take the address of the receiver
+	deref  bool     // whether to dereference the receiver
+}
+
+// asFuncRule converts the call rule to the equivalent func rule, so that the
+// trampolines can be generated in the same way as func rules do
+func asFuncRule(rule *rules.InstCallRule) *rules.InstFuncRule {
+	base := rule.InstBaseRule
+	base.ImportPath = rule.CalleeImportPath
+	return &rules.InstFuncRule{
+		InstBaseRule: base,
+		Function:     rule.CalleeFunction,
+		ReceiverType: rule.CalleeReceiverType,
+		OnEnter:      rule.OnEnter,
+		OnExit:       rule.OnExit,
+	}
+}
+
+// calleeRule narrows the func rule down to the resolved callee. The receiver
+// type pattern may match callees of different signatures, each of them needs
+// its own wrapper and trampolines
+func calleeRule(t *rules.InstFuncRule, callee *types.Func) *rules.InstFuncRule {
+	recv := callee.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return t
+	}
+	ct := *t
+	ct.ReceiverType = regexp.QuoteMeta(receiverTypeName(recv.Type()))
+	return &ct
+}
+
+// parseImportCfg parses the import config file passed to the compiler, which
+// maps import paths to export data files of the dependencies
+func parseImportCfg(file string) (map[string]string, error) {
+	content, err := util.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	importMap := make(map[string]string)
+	packageFile := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		verb, args, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found {
+			continue
+		}
+		before, after, found := strings.Cut(args, "=")
+		if !found {
+			continue
+		}
+		switch verb {
+		case "importmap":
+			importMap[before] = after
+		case "packagefile":
+			packageFile[before] = after
+		}
+	}
+	for path, mapped := range importMap {
+		if file, ok := packageFile[mapped]; ok {
+			packageFile[path] = file
+		}
+	}
+	return packageFile, nil
+}
+
+// loadTypes type checks the compiling package, it's done only once for all call
+// rules of the package
+func (rp *RuleProcessor) loadTypes() (*typeInfo, error) {
+	if rp.types != nil {
+		return rp.types, nil
+	}
+	importCfg := util.FindFlagValue(rp.compileArgs, "-importcfg")
+	if importCfg == "" {
+		return nil, ex.Newf("no -importcfg found in %v", rp.compileArgs)
+	}
+	packageFiles, err := parseImportCfg(importCfg)
+	if err != nil {
+		return nil, err
+	}
+	ti := &typeInfo{
+		fset:  token.NewFileSet(),
+		files: make(map[string]*goast.File),
+		info: &types.Info{
+			Types:      make(map[goast.Expr]types.TypeAndValue),
+			Uses:       make(map[*goast.Ident]types.Object),
+			Selections: make(map[*goast.SelectorExpr]*types.Selection),
+		},
+	}
+	files := make([]*goast.File, 0)
+	for _, arg := range rp.compileArgs {
+		if lang, ok := strings.CutPrefix(arg, "-lang="); ok {
+			ti.lang = lang
+			continue
+		}
+		if !util.IsGoFile(arg) {
+			continue
+		}
+		source, err := util.ReadFile(arg)
+		if err != nil {
+			return nil, err
+		}
+		// Use the base name as file name, which is consistent with the AST
+		// parser, so that positions can be compared with each other
+		name := filepath.Base(arg)
+		file, err := parser.ParseFile(ti.fset, name, source, parser.ParseComments)
+		if err != nil {
+			return nil, ex.Wrap(err)
+		}
+		files = append(files, file)
+		ti.files[name] = file
+	}
+	lookup := func(path string) (io.ReadCloser, error) {
+		file, ok := packageFiles[path]
+		if !ok {
+			return nil, ex.Newf("no export data for %s", path)
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, ex.Wrap(err)
+		}
+		return f, nil
+	}
+	conf := types.Config{
+		Importer:  importer.ForCompiler(ti.fset, "gc", lookup),
+		GoVersion: ti.lang,
+		// Type errors are tolerable as long as the call sites are resolved,
+		// the compiler will report the real ones anyway
+		Error: func(error) {},
+	}
+	importPath := util.FindFlagValue(rp.compileArgs, util.BuildPattern)
+	_, _ = conf.Check(importPath, ti.fset, files, ti.info)
+	rp.types = ti
+	return ti, nil
+}
+
+func receiverTypeName(t types.Type) string {
+	prefix := ""
+	if ptr, ok := t.(*types.Pointer); ok {
+		prefix = "*"
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return prefix + named.Obj().Name()
+	}
+	return prefix + t.String()
+}
+
+// isPureExpr checks if the expression can be evaluated twice without any side
+// effect, which is required for receivers as they are evaluated both for the
+// callee and the CallContext
+func isPureExpr(expr goast.Expr) bool {
+	switch e := expr.(type) {
+	case *goast.Ident:
+		return true
+	case *goast.SelectorExpr:
+		return isPureExpr(e.X)
+	case *goast.ParenExpr:
+		return isPureExpr(e.X)
+	case *goast.StarExpr:
+		return isPureExpr(e.X)
+	}
+	return false
+}
+
+// newCallSite checks if the call matches the rule. It returns the call site if
+// matched, or the reason why the matched call can not be instrumented
+func (ti *typeInfo) newCallSite(rule *rules.InstCallRule, recvRe *regexp.Regexp,
+	call *goast.CallExpr, sel *goast.SelectorExpr) (*callSite, string) {
+	fn, ok := ti.info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil ||
+		fn.Pkg().Path() != rule.CalleeImportPath ||
+		fn.Name() != rule.CalleeFunction {
+		return nil, ""
+	}
+	sig := fn.Type().(*types.Signature)
+	recv := sig.Recv()
+	if recvRe == nil {
+		if recv != nil {
+			return nil, ""
+		}
+	} else if recv == nil || !recvRe.MatchString(receiverTypeName(recv.Type())) {
+		return nil, ""
+	}
+	if sig.TypeParams().Len() > 0 || sig.RecvTypeParams().Len() > 0 {
+		return nil, "generic callee"
+	}
+	if len(call.Args) == 1 {
+		if tuple, ok := ti.info.TypeOf(call.Args[0]).(*types.Tuple); ok &&
+			tuple.Len() > 1 {
+			return nil, "multi-value argument"
+		}
+	}
+	site := &callSite{callee: fn}
+	if recv == nil {
+		return site, ""
+	}
+	selection := ti.info.Selections[sel]
+	if selection == nil || selection.Kind() != types.MethodVal {
+		return nil, "method expression"
+	}
+	if !isPureExpr(sel.X) {
+		return nil, "receiver with side effects"
+	}
+	// Walk through the embedded fields to find the real receiver
+	typ := selection.Recv()
+	index := selection.Index()
+	for _, idx := range index[:len(index)-1] {
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		st, ok := typ.Underlying().(*types.Struct)
+		if !ok {
+			return nil, "unexpected embedding"
+		}
+		field := st.Field(idx)
+		site.fields = append(site.fields, field.Name())
+		typ = field.Type()
+	}
+	_, isPtr := typ.Underlying().(*types.Pointer)
+	_, wantPtr := recv.Type().(*types.Pointer)
+	site.addr = wantPtr && !isPtr
+	site.deref = !wantPtr && isPtr && !types.IsInterface(recv.Type())
+	return site, ""
+}
+
+// findCallSites finds all calls that match the rule in the file, call sites are
+// keyed by their end positions, which are unique among all calls
+func (ti *typeInfo) findCallSites(rule *rules.InstCallRule,
+	recvRe *regexp.Regexp, file *goast.File) map[string]*callSite {
+	sites := make(map[string]*callSite)
+	goast.Inspect(file, func(node goast.Node) bool {
+		call, ok := node.(*goast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*goast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		key := ti.fset.Position(call.End()).String()
+		site, reason := ti.newCallSite(rule, recvRe, call, sel)
+		if reason != "" {
+			util.Log("Skip call site %s of %s: %s", key, rule, reason)
+		}
+		if site != nil {
+			sites[key] = site
+		}
+		return true
+	})
+	return sites
+}
+
+// genericize parameterizes the trampolines and the CallContext implementation
+// with the type parameters
+func genericize(decls []dst.Decl, implName string, typeParams []string) {
+	if len(typeParams) == 0 {
+		return
+	}
+	newTypeParams := func() *dst.FieldList {
+		names := make([]*dst.Ident, 0, len(typeParams))
+		for _, tp := range typeParams {
+			names = append(names, ast.Ident(tp))
+		}
+		field := &dst.Field{Names: names, Type: ast.InterfaceType()}
+		return &dst.FieldList{List: []*dst.Field{field}}
+	}
+	instantiate := func() dst.Expr {
+		if len(typeParams) == 1 {
+			return ast.IndexExpr(ast.Ident(implName), ast.Ident(typeParams[0]))
+		}
+		indices := make([]dst.Expr, 0, len(typeParams))
+		for _, tp := range typeParams {
+			indices = append(indices, ast.Ident(tp))
+		}
+		return &dst.IndexListExpr{X: ast.Ident(implName), Indices: indices}
+	}
+	for _, decl := range decls {
+		switch d := decl.(type) {
+		case *dst.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*dst.TypeSpec); ok && ts.Name.Name == implName {
+					ts.TypeParams = newTypeParams()
+				}
+			}
+		case *dst.FuncDecl:
+			// Trampolines only, hooks have no body
+			if !ast.HasReceiver(d) && d.Body != nil {
+				d.Type.TypeParams = newTypeParams()
+			}
+		}
+		// Instantiate all references to the CallContext implementation, i.e.
+		// method receivers, composite literals and type assertions
+		dst.Inspect(decl, func(node dst.Node) bool {
+			switch n := node.(type) {
+			case *dst.StarExpr:
+				if ident, ok := n.X.(*dst.Ident); ok && ident.Name == implName {
+					n.X = instantiate()
+				}
+			case *dst.CompositeLit:
+				if ident, ok := n.Type.(*dst.Ident); ok && ident.Name == implName {
+					n.Type = instantiate()
+				}
+			}
+			return true
+		})
+	}
+}
+
+func typeParamList(typeParams []string, constraint string) string {
+	if len(typeParams) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(typeParams, ", ") + constraint + "]"
+}
+
+func resultList(results []string) string {
+	switch len(results) {
+	case 0:
+		return ""
+	case 1:
+		return " " + results[0]
+	}
+	return " (" + strings.Join(results, ", ") + ")"
+}
+
+// describeCallee collects the information of the callee. Trampolines are
+// shared by all call sites of the callee, so the source position refers to
+// the callee declaration rather than the call site
+func (rp *RuleProcessor) describeCallee(callee *types.Func) *targetInfo {
+	sig := callee.Type().(*types.Signature)
+	info := &targetInfo{
+		funcName:    callee.Name(),
+		packageName: callee.Pkg().Name(),
+		packagePath: callee.Pkg().Path(),
+		paramNames:  make([]string, 0),
+		resultNames: make([]string, 0),
+	}
+	if rp.types != nil && callee.Pos().IsValid() {
+		pos := rp.types.fset.Position(callee.Pos())
+		info.sourceFile, info.sourceLine = pos.Filename, pos.Line
+	}
+	if sig.Recv() != nil {
+		info.receiverType = receiverTypeName(sig.Recv().Type())
+		info.paramNames = append(info.paramNames, varName(sig.Recv(), "recv"))
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		info.paramNames = append(info.paramNames,
+			varName(sig.Params().At(i), fmt.Sprintf("param%d", i)))
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		info.resultNames = append(info.resultNames,
+			varName(sig.Results().At(i), fmt.Sprintf("retVal%d", i)))
+	}
+	return info
+}
+
+// varName returns the name of the variable, or the fallback name if it's
+// unnamed or blank, as func rules do
+func varName(v *types.Var, fallback string) string {
+	if v.Name() == "" || v.Name() == "_" {
+		return fallback
+	}
+	return v.Name()
+}
+
+// createCallTrampoline generates the wrapper function, the trampolines and the
+// CallContext implementation for the callee
+func (rp *RuleProcessor) createCallTrampoline(t *rules.InstFuncRule,
+	callee *types.Func) ([]dst.Decl, error) {
+	// The callee signature is described by type parameters, i.e. T0 for the
+	// receiver, A0...An for parameters and R0...Rn for results. A synthetic
+	// target function of such signature is used to generate the trampolines
+	sig := callee.Type().(*types.Signature)
+	typeParams := make([]string, 0)
+	recv := ""
+	params := make([]string, 0)
+	fnParams := make([]string, 0)
+	enterArgs := make([]string, 0)
+	callArgs := make([]string, 0)
+	if sig.Recv() != nil {
+		typeParams = append(typeParams, "T0")
+		recv = "recv T0"
+		enterArgs = append(enterArgs, "&recv")
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		tp := fmt.Sprintf("A%d", i)
+		arg := fmt.Sprintf("a%d", i)
+		typeParams = append(typeParams, tp)
+		enterArgs = append(enterArgs, "&"+arg)
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			tp = "..." + tp
+			arg += "..."
+		}
+		params = append(params, fmt.Sprintf("a%d %s", i, tp))
+		fnParams = append(fnParams, tp)
+		callArgs = append(callArgs, arg)
+	}
+	results := make([]string, 0)
+	fnResults := make([]string, 0)
+	exitArgs := []string{TrampolineCallContextName}
+	for i := 0; i < sig.Results().Len(); i++ {
+		tp := fmt.Sprintf("R%d", i)
+		typeParams = append(typeParams, tp)
+		results = append(results, fmt.Sprintf("r%d %s", i, tp))
+		fnResults = append(fnResults, tp)
+		exitArgs = append(exitArgs, fmt.Sprintf("&r%d", i))
+	}
+	retList := ""
+	if len(results) > 0 {
+		retList = " (" + strings.Join(results, ", ") + ")"
+	}
+	p := ast.NewAstParser()
+	synthetic := fmt.Sprintf("func %s(%s)%s {}", callee.Name(),
+		strings.Join(params, ", "), retList)
+	if recv != "" {
+		synthetic = fmt.Sprintf("func (%s) %s(%s)%s {}", recv, callee.Name(),
+			strings.Join(params, ", "), retList)
+	}
+	scratch, err := p.ParseSource(fmt.Sprintf("package %s\n%s",
+		callee.Pkg().Name(), synthetic))
+	if err != nil {
+		return nil, err
+	}
+	targetFunc := scratch.Decls[0].(*dst.FuncDecl)
+	scratch.Decls = nil
+
+	// Generate trampolines into the scratch file, whose package name is the
+	// same as the callee package, as it's reported by CallContext. Hooks are
+	// shared by all call sites, they only accept CallContext
+	target := rp.target
+	rp.target, rp.targetFunc = scratch, targetFunc
+	rp.targetInfo = rp.describeCallee(callee)
+	rp.exact, rp.pullHook = false, true
+	err = rp.createTrampoline(t)
+	rp.target, rp.targetFunc, rp.targetInfo = target, nil, nil
+	if err != nil {
+		return nil, err
+	}
+	decls := scratch.Decls
+	implName := TrampolineCallContextImplType + makeSuffix(t, targetFunc)
+	genericize(decls, implName, typeParams)
+
+	// Generate the wrapper function, it has the same structure as the
+	// trampoline-jump-if, see insertTJump for details
+	inst := typeParamList(typeParams, "")
+	onEnterCall := fmt.Sprintf("%s%s(%s)", rp.onEnterHookFunc.Name.Name, inst,
+		strings.Join(enterArgs, ", "))
+	onExitCall := fmt.Sprintf("%s%s(%s)", rp.onExitHookFunc.Name.Name, inst,
+		strings.Join(exitArgs, ", "))
+	call := fmt.Sprintf("fn(%s)", strings.Join(callArgs, ", "))
+	if len(results) > 0 {
+		call = "return " + call
+	}
+	// Arguments are passed to the returned function rather than the wrapper
+	// itself, otherwise type parameters would be inferred from them as well,
+	// which fails if they are assignable but not identical to the parameter
+	// types, e.g. *bytes.Buffer passed as io.Writer
+	fnType := fmt.Sprintf("func(%s)%s", strings.Join(fnParams, ", "),
+		resultList(fnResults))
+	wrapperParams := []string{"fn " + fnType}
+	if recv != "" {
+		wrapperParams = append(wrapperParams, recv)
+	}
+	wrapper, err := p.ParseSource(fmt.Sprintf(`package %s
+func %s%s(%s) %s {
+	return func(%s)%s {
+		if %s, %s := %s; %s {
+			%s
+			return
+		} else {
+			defer %s
+		}
+		%s
+	}
+}`, callee.Pkg().Name(), makeCallSiteName(t),
+		typeParamList(typeParams, " interface{}"),
+		strings.Join(wrapperParams, ", "), fnType,
+		strings.Join(params, ", "), retList,
+		TrampolineCallContextName, TrampolineSkipName, onEnterCall,
+		TrampolineSkipName, onExitCall, onExitCall, call))
+	if err != nil {
+		return nil, err
+	}
+	decls = append(decls, wrapper.Decls...)
+	return decls, nil
+}
+
+func makeCallSiteName(t *rules.InstFuncRule) string {
+	return fmt.Sprintf("%s_%s%s", CallSiteName, t.Function,
+		util.Crc32(t.String()))
+}
+
+// rewriteCallSite redirects the call to the function returned by the wrapper,
+// e.g. c.Do(req) is rewritten as OtelCallSite_Do(c.Do, c)(req)
+func rewriteCallSite(call *dst.CallExpr, sel *dst.SelectorExpr, site *callSite,
+	wrapper string) {
+	args := []dst.Expr{sel}
+	if site.callee.Type().(*types.Signature).Recv() != nil {
+		recv := dst.Clone(sel.X).(dst.Expr)
+		for _, field := range site.fields {
+			recv = ast.SelectorExpr(recv, field)
+		}
+		if site.addr {
+			recv = ast.AddressOf(recv)
+		} else if site.deref {
+			recv = ast.DereferenceOf(recv)
+		}
+		args = append(args, recv)
+	}
+	call.Fun = &dst.CallExpr{Fun: ast.Ident(wrapper), Args: args}
+}
+
+// calleeReceiverRegexp compiles the receiver type pattern of the callee once
+// for all files, it's nil if the callee is a function without receiver
+func (rp *RuleProcessor) calleeReceiverRegexp(rule *rules.InstCallRule) (
+	*regexp.Regexp, error) {
+	pattern := rule.CalleeReceiverTypeRegexp()
+	if pattern == "" {
+		return nil, nil
+	}
+	if re, ok := rp.calleeRecvRes[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile("^" + pattern + "$") // strict match
+	if err != nil {
+		return nil, ex.Wrapf(err, "bad call rule %s", rule)
+	}
+	rp.calleeRecvRes[pattern] = re
+	return re, nil
+}
+
+func (rp *RuleProcessor) applyCallRule(rule *rules.InstCallRule,
+	root *dst.File, file string) (bool, error) {
+	ti, err := rp.loadTypes()
+	if err != nil {
+		return false, err
+	}
+	// The wrapper function is generic
+	if ti.lang != "" && version.Compare(ti.lang, CallMinGoVersion) < 0 {
+		util.Log("Skip call rule %s: requires %s, but got %s", rule,
+			CallMinGoVersion, ti.lang)
+		return false, nil
+	}
+	name := filepath.Base(rp.tryRelocated(file))
+	fileAst, ok := ti.files[name]
+	if !ok {
+		return false, ex.Newf("file %s is not compiled", file)
+	}
+	recvRe, err := rp.calleeReceiverRegexp(rule)
+	if err != nil {
+		return false, err
+	}
+	sites := ti.findCallSites(rule, recvRe, fileAst)
+	if len(sites) == 0 {
+		return false, nil
+	}
+	t := asFuncRule(rule)
+	applied := false
+	dst.Inspect(root, func(node dst.Node) bool {
+		if err != nil {
+			return false
+		}
+		call, ok := node.(*dst.CallExpr)
+		if !ok {
+			return true
+		}
+		pos := rp.parser.FindEndPosition(call)
+		site, ok := sites[pos.String()]
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*dst.SelectorExpr)
+		if !ok {
+			// Already redirected by another call rule
+			util.Log("Skip call site %s of %s: already instrumented",
+				pos, rule)
+			return true
+		}
+		// Declarations are keyed by the wrapper name, which is unique for
+		// each pair of the rule and the resolved callee
+		ct := calleeRule(t, site.callee)
+		wrapper := makeCallSiteName(ct)
+		if _, exist := rp.callDecls[wrapper]; !exist {
+			var decls []dst.Decl
+			decls, err = rp.createCallTrampoline(ct, site.callee)
+			if err != nil {
+				return false
+			}
+			rp.callDecls[wrapper] = decls
+		}
+		rewriteCallSite(call, sel, site, wrapper)
+		applied = true
+		return true
+	})
+	if err != nil {
+		return false, err
+	}
+	if applied {
+		util.Log("Apply call rule %s (%v)", rule, rp.compileArgs)
+		callee := rule.CalleeImportPath + "." + rule.CalleeFunction
+		if rule.CalleeReceiverType != "" {
+			callee = rule.CalleeImportPath + ".(" + rule.CalleeReceiverType +
+				")." + rule.CalleeFunction
+		}
+		rp.reportRule(file, KindCall, callee, rule)
+	}
+	return applied, nil
+}
@@ -62,6 +62,7 @@ func (rp *RuleProcessor) applyFileRule(rule *rules.InstFileRule, pkgName string)
 		rp.addCompileArg(target)
 	}
 	util.Log("Apply file rule %v (%v)", rule, rp.compileArgs)
+	rp.reportRule(rule.FileName, KindFile, fileName, rule)
 	rp.keepForDebug(target)
 	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"go/parser"
 	"path/filepath"
 	"regexp"
+	"sort"
 
 	"github.com/alibaba/loongsuite-go-agent/tool/ast"
 	"github.com/alibaba/loongsuite-go-agent/tool/config"
@@ -32,6 +33,7 @@ import (
 const (
 	TJumpLabel      = "/* TRAMPOLINE_JUMP_IF */"
 	OtelGlobalsFile = "otel.globals.go"
+	OtelSharedFile  = "otel.shared.go"
 )
 
 func (rp *RuleProcessor) parseAst(filePath string) (*dst.File, error) {
@@ -69,6 +71,14 @@ func (rp *RuleProcessor) writeInstrumented(root *dst.File, filePath string) erro
 	return nil
 }
 
+// makeSuffix generates the suffix for declarations generated for the target
+// function, a pattern rule may match multiple functions of the same name, so
+// the receiver type is taken into account as well
+func makeSuffix(r *rules.InstFuncRule, funcDecl *dst.FuncDecl) string {
+	return util.Crc32(r.String() + ast.ReceiverTypeName(funcDecl) + "." +
+		funcDecl.Name.Name)
+}
+
 func makeName(r *rules.InstFuncRule,
 	funcDecl *dst.FuncDecl, onEnter bool) string {
 	prefix := TrampolineOnExitName
@@ -76,7 +86,7 @@ func makeName(r *rules.InstFuncRule,
 		prefix = TrampolineOnEnterName
 	}
 	return fmt.Sprintf("%s_%s%s",
-		prefix, funcDecl.Name.Name, util.Crc32(r.String()))
+		prefix, funcDecl.Name.Name, makeSuffix(r, funcDecl))
 }
 
 func findJumpPoint(jumpIf *dst.IfStmt) *dst.BlockStmt {
@@ -143,7 +153,7 @@ func collectArguments(funcDecl *dst.FuncDecl) []dst.Expr {
 func (rp *RuleProcessor) createTJumpIf(t *rules.InstFuncRule, funcDecl *dst.FuncDecl,
 	args []dst.Expr, retVals []dst.Expr,
 ) *dst.IfStmt {
-	varSuffix := util.Crc32(t.String())
+	varSuffix := makeSuffix(t, funcDecl)
 	if config.GetConf().Verbose {
 		util.Log("varSuffix: %s for %s", varSuffix, t.String())
 	}
@@ -152,6 +162,7 @@ func (rp *RuleProcessor) createTJumpIf(t *rules.InstFuncRule, funcDecl *dst.Func
 	// heavily depends on the structure of trampoline-jump-if. Any change in it
 	// should be carefully examined.
 	onEnterCall := ast.CallTo(makeName(t, funcDecl, true), args)
+	onEnterCall.Fun = instantiate(onEnterCall.Fun, funcDecl)
 	onExitCall := ast.CallTo(makeName(t, funcDecl, false), func() []dst.Expr {
 		// NB. DST framework disallows duplicated node in the
 		// AST tree, we need to replicate the return values
@@ -163,6 +174,7 @@ func (rp *RuleProcessor) createTJumpIf(t *rules.InstFuncRule, funcDecl *dst.Func
 		}
 		return clone
 	}())
+	onExitCall.Fun = instantiate(onExitCall.Fun, funcDecl)
 	tjumpInit := ast.DefineStmts(
 		ast.Exprs(
 			ast.Ident(TrampolineCallContextName+varSuffix),
@@ -288,22 +300,22 @@ func (rp *RuleProcessor) insertTJump(t *rules.InstFuncRule,
 	return rp.createTrampoline(t)
 }
 
-func (rp *RuleProcessor) insertRaw(r *rules.InstFuncRule, decl *dst.FuncDecl) error {
-	util.Assert(r.OnEnter != "" || r.OnExit != "", "sanity check")
-	if r.OnEnter != "" {
+func (rp *RuleProcessor) insertRaw(onEnter, onExit string, decl *dst.FuncDecl) error {
+	util.Assert(onEnter != "" || onExit != "", "sanity check")
+	if onEnter != "" {
 		// Prepend raw code snippet to function body for onEnter
 		p := ast.NewAstParser()
-		onEnterSnippet, err := p.ParseSnippet(r.OnEnter)
+		onEnterSnippet, err := p.ParseSnippet(onEnter)
 		if err != nil {
 			return err
 		}
 		decl.Body.List = append(onEnterSnippet, decl.Body.List...)
 	}
-	if r.OnExit != "" {
+	if onExit != "" {
 		// Use defer func(){ raw_code_snippet }() for onExit
 		p := ast.NewAstParser()
 		onExitSnippet, err := p.ParseSnippet(
-			fmt.Sprintf("defer func(){ %s }()", r.OnExit),
+			fmt.Sprintf("defer func(){ %s }()", onExit),
 		)
 		if err != nil {
 			return err
@@ -326,6 +338,30 @@ func nameReturnValues(funcDecl *dst.FuncDecl) {
 	}
 }
 
+// nameParameters names the receiver, parameters and type parameters that are
+// unnamed or blank, so that they can be referred by the trampoline
+func nameParameters(funcDecl *dst.FuncDecl) {
+	nameTypeParams(funcDecl)
+	if ast.HasReceiver(funcDecl) {
+		field := funcDecl.Recv.List[0]
+		if len(field.Names) == 0 || field.Names[0].Name == "_" {
+			field.Names = []*dst.Ident{ast.Ident("recv")}
+		}
+	}
+	idx := 0
+	for _, field := range funcDecl.Type.Params.List {
+		if len(field.Names) == 0 {
+			field.Names = []*dst.Ident{ast.Ident(fmt.Sprintf("param%d", idx))}
+		}
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				name.Name = fmt.Sprintf("param%d", idx)
+			}
+			idx++
+		}
+	}
+}
+
 //go:embed api.tmpl
 var templateAPI string
 
@@ -357,6 +393,42 @@ func (rp *RuleProcessor) writeGlobals(pkgName string) error {
 	return nil
 }
 
+// writeSharedDecls writes declarations shared by all instrumented files of the
+// package, i.e. wrappers of call rules and pulled hooks, to a separate file
+func (rp *RuleProcessor) writeSharedDecls(pkgName string) error {
+	p := ast.NewAstParser()
+	root, err := p.ParseSource(fmt.Sprintf("package %s\nimport _ %q",
+		pkgName, "unsafe"))
+	if err != nil {
+		return err
+	}
+	// Sort declarations to make sure the generated file is deterministic
+	wrappers := make([]string, 0, len(rp.callDecls))
+	for wrapper := range rp.callDecls {
+		wrappers = append(wrappers, wrapper)
+	}
+	sort.Strings(wrappers)
+	for _, wrapper := range wrappers {
+		root.Decls = append(root.Decls, rp.callDecls[wrapper]...)
+	}
+	names := make([]string, 0, len(rp.hookDecls))
+	for name := range rp.hookDecls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		root.Decls = append(root.Decls, rp.hookDecls[name])
+	}
+	path := filepath.Join(rp.workDir, OtelSharedFile)
+	sharedFile, err := ast.WriteFile(root, path)
+	if err != nil {
+		return err
+	}
+	rp.addCompileArg(sharedFile)
+	rp.keepForDebug(path)
+	return nil
+}
+
 func (rp *RuleProcessor) enableLineDirective(filePath string) error {
 	text, err := util.ReadFile(filePath)
 	if err != nil {
@@ -372,30 +444,80 @@ func (rp *RuleProcessor) enableLineDirective(filePath string) error {
 	return nil
 }
 
-func (rp *RuleProcessor) applyFuncRule(rule *rules.InstFuncRule, root *dst.File) (err error) {
-	funcDecls := ast.FindFuncDecl(root, rule.Function, rule.ReceiverType)
+// describeFunc collects the information of the target function, it must be
+// called after parameters and return values are named
+func (rp *RuleProcessor) describeFunc(funcDecl *dst.FuncDecl,
+	file string) *targetInfo {
+	info := &targetInfo{
+		funcName:    funcDecl.Name.Name,
+		packageName: rp.target.Name.Name,
+		packagePath: util.FindFlagValue(rp.compileArgs, "-p"),
+		sourceFile:  file,
+		paramNames:  make([]string, 0),
+		resultNames: make([]string, 0),
+	}
+	if pos := rp.parser.FindPosition(funcDecl); pos.Line > 0 {
+		info.sourceLine = pos.Line
+	}
+	if ast.HasReceiver(funcDecl) {
+		info.receiverType = ast.ReceiverTypeName(funcDecl)
+		info.paramNames = append(info.paramNames,
+			getNames(funcDecl.Recv)...)
+	}
+	info.paramNames = append(info.paramNames,
+		getNames(funcDecl.Type.Params)...)
+	if funcDecl.Type.Results != nil {
+		info.resultNames = getNames(funcDecl.Type.Results)
+	}
+	return info
+}
+
+func (rp *RuleProcessor) applyFuncRule(rule *rules.InstFuncRule, root *dst.File,
+	file string) (err error) {
+	funcDecls := make([]*dst.FuncDecl, 0)
+	for _, funcDecl := range ast.FindFuncDecl(root, rule.FunctionRegexp(),
+		rule.ReceiverTypeRegexp()) {
+		// Signature mismatches are already reported during preprocess
+		if rule.MatchSignature(ast.FuncSignature(funcDecl)) {
+			funcDecls = append(funcDecls, funcDecl)
+		}
+	}
 	if len(funcDecls) == 0 {
 		return ex.Newf("func %s not found", rule.Function)
 	}
+	// Hooks can not know all target packages of the import path pattern or
+	// the trace rule in advance, they are pulled from the hook package instead
+	rp.pullHook = rule.IsHookPulled()
+	if rp.pullHook && !rule.UseRaw {
+		hookPath, err := findHookImportPath(rule)
+		if err != nil {
+			return err
+		}
+		if hookPath == util.FindFlagValue(rp.compileArgs, "-p") {
+			util.Log("Skip func rule %s: hook package itself", rule)
+			return nil
+		}
+	}
 	for _, funcDecl := range funcDecls {
 		util.Assert(funcDecl.Body != nil, "target func body is empty")
-		fnName := funcDecl.Name.Name
 		// Save raw function declaration
 		rp.targetFunc = funcDecl
 		// The func rule can either fully match the target function
-		// or use a regexp to match a batch of functions. The
+		// or use a pattern to match a batch of functions. The
 		// generation of tjump differs slightly between these two
 		// cases. In the former case, the hook function is required
 		// to have the same signature as the target function, while
-		// the latter does not have this requirement.
-		rp.exact = fnName == rule.Function
-		// Add explicit names for return values, they can be further
-		// referenced if we're willing
+		// the latter only accepts the CallContext parameter.
+		rp.exact = rule.IsExact()
+		// Add explicit names for parameters and return values, they can be
+		// further referenced if we're willing
+		nameParameters(funcDecl)
 		nameReturnValues(funcDecl)
+		rp.targetInfo = rp.describeFunc(funcDecl, file)
 
 		// Apply all matched rules for this function
 		if rule.UseRaw {
-			err = rp.insertRaw(rule, funcDecl)
+			err = rp.insertRaw(rule.OnEnter, rule.OnExit, funcDecl)
 		} else {
 			err = rp.insertTJump(rule, funcDecl)
 		}
@@ -403,6 +525,7 @@ func (rp *RuleProcessor) applyFuncRule(rule *rules.InstFuncRule, root *dst.File)
 			return err
 		}
 		util.Log("Apply func rule %s (%v)", rule, rp.compileArgs)
+		rp.reportRule(file, KindFunc, funcDeclName(funcDecl), rule)
 	}
 	return nil
 }
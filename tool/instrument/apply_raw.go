@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrument
+
+import (
+	"regexp"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/ast"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+	"github.com/dave/dst"
+)
+
+func (rp *RuleProcessor) applyRawRule(rule *rules.InstRawRule, root *dst.File,
+	file string) error {
+	funcDecls := ast.FindFuncDecl(root, regexp.QuoteMeta(rule.Func), rule.Recv)
+	if len(funcDecls) == 0 {
+		return ex.Newf("func %s not found", rule.Func)
+	}
+	for _, funcDecl := range funcDecls {
+		util.Assert(funcDecl.Body != nil, "target func body is empty")
+		// Raw code at exit may refer to return values, name them in the same
+		// way as func rules do
+		nameReturnValues(funcDecl)
+		err := rp.insertRaw(rule.Raw, rule.RawExit, funcDecl)
+		if err != nil {
+			return err
+		}
+		util.Log("Apply raw rule %s (%v)", rule, rp.compileArgs)
+		rp.reportRule(file, KindRaw, funcDeclName(funcDecl), rule)
+	}
+	return nil
+}
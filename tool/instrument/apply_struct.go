@@ -29,10 +29,12 @@ func (rp *RuleProcessor) addStructField(rule *rules.InstStructRule, decl dst.Dec
 	ast.AddStructField(decl, rule.FieldName, rule.FieldType)
 }
 
-func (rp *RuleProcessor) applyStructRule(rule *rules.InstStructRule, root *dst.File) error {
+func (rp *RuleProcessor) applyStructRule(rule *rules.InstStructRule, root *dst.File,
+	file string) error {
 	structDecl := ast.FindStructDecl(root, rule.StructType)
 	if structDecl != nil {
 		rp.addStructField(rule, structDecl)
+		rp.reportRule(file, KindStruct, rule.StructType, rule)
 	} else {
 		return ex.Newf("struct %s not found", rule.StructType)
 	}
@@ -0,0 +1,328 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrument
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/config"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+)
+
+// -----------------------------------------------------------------------------
+// Instrumentation Cache
+//
+// The go command caches every compiled package under its action ID, which is
+// content-addressed by the import path, the hashes of source files, compiler
+// flags, the action IDs of all dependencies and the tool ID reported by the
+// "compile -V=full" command. What affects the instrumented code but is invisible
+// to the go command, i.e. the tool itself, the matched rule set of the package
+// and the hook code it refers to, is covered by instrumentation fingerprints:
+//
+//   - The tool fingerprint is appended to the tool ID, which is queried once
+//     per build through -toolexec as well, so it's shared by all packages.
+//   - The package fingerprint of each instrumented package is passed as a fake
+//     compiler flag by the per-package -gcflags, which is part of the action ID
+//     of that package only, and it's stripped before the compiler is invoked.
+//
+// As a result, packages whose inputs stay unchanged reuse previously instrumented
+// objects from the build cache, while packages whose inputs changed are compiled
+// and instrumented again. Since the last matching -gcflags wins, user-specified
+// -gcflags would be overridden by package fingerprints, in which case all rule
+// sets are folded into the tool fingerprint instead.
+
+const (
+	ToolIDFlag        = "-V=full"
+	ToolIDCompile     = "compile"
+	FingerprintPrefix = "otel:"
+	FingerprintFlag   = "-otel.fingerprint="
+	FingerprintFile   = "fingerprint"
+)
+
+// Fingerprints are computed by the preprocess phase once rules are matched
+type Fingerprints struct {
+	// Tool is appended to the compiler tool ID
+	Tool string
+	// Packages maps the -gcflags pattern of the instrumented package, i.e. the
+	// import path, or the directory for main packages, to its fingerprint
+	Packages map[string]string
+}
+
+// isToolIDQuery checks if the command is the "compile -V=full" query issued by
+// the go command to compute the tool ID of the compiler
+func isToolIDQuery(args []string) bool {
+	if len(args) != 2 || args[1] != ToolIDFlag {
+		return false
+	}
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	return name == ToolIDCompile
+}
+
+func hashFile(h hash.Hash, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	defer func() { _ = f.Close() }()
+	_, err = fmt.Fprintf(h, "file %s\n", filepath.Base(file))
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	return nil
+}
+
+// collectHookFiles collects all source files that are injected into or referred
+// by the instrumented packages
+func collectHookFiles(bundles []*rules.InstRuleSet) ([]string, error) {
+	dirs := make(map[string]bool)
+	files := make(map[string]bool)
+	for _, bundle := range bundles {
+		for _, funcRules := range bundle.FuncRules {
+			for _, rule := range funcRules {
+				if rule.UseRaw || rule.GetPath() == "" {
+					continue
+				}
+				dirs[rule.GetPath()] = true
+			}
+		}
+		for _, callRules := range bundle.CallRules {
+			for _, rule := range callRules {
+				dirs[rule.GetPath()] = true
+			}
+		}
+		for _, fileRule := range bundle.FileRules {
+			files[fileRule.FileName] = true
+		}
+	}
+	for dir := range dirs {
+		list, err := util.ListFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range list {
+			if util.IsGoFile(file) && !util.IsGoTestFile(file) {
+				files[file] = true
+			}
+		}
+	}
+	sorted := make([]string, 0, len(files))
+	for file := range files {
+		sorted = append(sorted, file)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// hashBundles hashes the rule sets along with the hook code they refer to
+func hashBundles(h hash.Hash, bundles []*rules.InstRuleSet) error {
+	sorted := make([]*rules.InstRuleSet, len(bundles))
+	copy(sorted, bundles)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	for _, bundle := range sorted {
+		_, err := fmt.Fprintf(h, "bundle %s\n", bundle.String())
+		if err != nil {
+			return ex.Wrap(err)
+		}
+	}
+	files, err := collectHookFiles(sorted)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		err = hashFile(h, file)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// computeToolFingerprint computes the tool fingerprint from the tool version and
+// the executable, along with the rule sets that are not covered by package
+// fingerprints
+func computeToolFingerprint(bundles []*rules.InstRuleSet) (string, error) {
+	h := sha256.New()
+	_, err := fmt.Fprintf(h, "tool %s\n", config.ToolVersion)
+	if err != nil {
+		return "", ex.Wrap(err)
+	}
+	// Development builds of the tool share the same version, the executable
+	// itself is taken into account as well
+	exe, err := os.Executable()
+	if err != nil {
+		return "", ex.Wrap(err)
+	}
+	err = hashFile(h, exe)
+	if err != nil {
+		return "", err
+	}
+	err = hashBundles(h, bundles)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32], nil
+}
+
+// computePackageFingerprint computes the fingerprint of the instrumented package
+// from the tool fingerprint, the matched rule set, which contains the import
+// path, and the hook code it refers to. Hashes of source files are not included
+// as the go command takes care of them
+func computePackageFingerprint(tool string, bundle *rules.InstRuleSet) (
+	string, error) {
+	h := sha256.New()
+	_, err := fmt.Fprintf(h, "tool %s\n", tool)
+	if err != nil {
+		return "", ex.Wrap(err)
+	}
+	err = hashBundles(h, []*rules.InstRuleSet{bundle})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32], nil
+}
+
+// packagePattern returns the -gcflags pattern that matches the package of the
+// rule set only. All main packages are compiled as "main", they are told apart
+// by their directories, which must be relative to the working directory of the
+// go command. Empty string is returned if there is no such pattern
+func packagePattern(bundle *rules.InstRuleSet) string {
+	if bundle.ImportPath != "main" {
+		return bundle.ImportPath
+	}
+	files := make([]string, 0)
+	for file := range bundle.FuncRules {
+		files = append(files, file)
+	}
+	for file := range bundle.StructRules {
+		files = append(files, file)
+	}
+	for file := range bundle.RawRules {
+		files = append(files, file)
+	}
+	for file := range bundle.CallRules {
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return ""
+	}
+	sort.Strings(files)
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(cwd, filepath.Dir(files[0]))
+	if err != nil || strings.Contains(rel, "=") {
+		return ""
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." || strings.HasPrefix(rel, "../") {
+		return rel
+	}
+	return "./" + rel
+}
+
+// ComputeFingerprints computes fingerprints of the matched rule sets, they are
+// all folded into the tool fingerprint unless perPackage is set
+func ComputeFingerprints(bundles []*rules.InstRuleSet, perPackage bool) (
+	*Fingerprints, error) {
+	patterns := make(map[string]*rules.InstRuleSet)
+	folded := make([]*rules.InstRuleSet, 0)
+	for _, bundle := range bundles {
+		pattern := ""
+		if perPackage {
+			pattern = packagePattern(bundle)
+		}
+		if pattern == "" || patterns[pattern] != nil {
+			folded = append(folded, bundle)
+			continue
+		}
+		patterns[pattern] = bundle
+	}
+	tool, err := computeToolFingerprint(folded)
+	if err != nil {
+		return nil, err
+	}
+	fps := &Fingerprints{Tool: tool, Packages: make(map[string]string)}
+	for pattern, bundle := range patterns {
+		fps.Packages[pattern], err = computePackageFingerprint(tool, bundle)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fps, nil
+}
+
+// stripFingerprintFlag removes the package fingerprint from the compiler flags
+// and returns it, or the tool fingerprint if the package has none
+func stripFingerprintFlag(args []string) ([]string, string, error) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, FingerprintFlag) {
+			stripped := append(append([]string{}, args[:i]...), args[i+1:]...)
+			return stripped, strings.TrimPrefix(arg, FingerprintFlag), nil
+		}
+	}
+	fingerprint, err := util.ReadFile(util.GetPreprocessLogPath(FingerprintFile))
+	if err != nil {
+		return nil, "", err
+	}
+	return args, fingerprint, nil
+}
+
+// formatToolID appends the tool fingerprint to the output of the tool ID query.
+// For release toolchains, the whole line is used as tool ID, so appending a new
+// field is enough. Development toolchains only take the content ID of the
+// trailing buildID=... field into account, so we have to append the fingerprint
+// to that field instead
+func formatToolID(line, fingerprint string) string {
+	line = strings.TrimSpace(line)
+	fields := strings.Fields(line)
+	if len(fields) > 2 && strings.Contains(fields[2], "devel") {
+		return line + "." + fingerprint
+	}
+	return line + " " + FingerprintPrefix + fingerprint
+}
+
+// printToolID prints the original tool ID of the compiler along with the tool
+// fingerprint, so that the go command can tell apart objects instrumented by
+// different tools
+func printToolID(args []string) error {
+	out, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return ex.Wrapf(err, "command %v", args)
+	}
+	fingerprint, err := util.ReadFile(util.GetPreprocessLogPath(FingerprintFile))
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatToolID(string(out), fingerprint))
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrument
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+)
+
+func TestIsToolIDQuery(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"/go/pkg/tool/linux_amd64/compile", "-V=full"}, true},
+		{[]string{"compile.exe", "-V=full"}, true},
+		{[]string{"/go/pkg/tool/linux_amd64/asm", "-V=full"}, false},
+		{[]string{"/go/pkg/tool/linux_amd64/compile", "-V"}, false},
+		{[]string{"/go/pkg/tool/linux_amd64/compile", "-o", "_pkg_.a"}, false},
+		{[]string{"/go/pkg/tool/linux_amd64/compile"}, false},
+	}
+	for _, tt := range tests {
+		if got := isToolIDQuery(tt.args); got != tt.want {
+			t.Errorf("isToolIDQuery(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestFormatToolID(t *testing.T) {
+	release := formatToolID("compile version go1.23.0\n", "abc")
+	if release != "compile version go1.23.0 otel:abc" {
+		t.Errorf("unexpected release tool ID %q", release)
+	}
+	devel := formatToolID("compile version devel go1.24-1a2b3c "+
+		"buildID=x/y\n", "abc")
+	if devel != "compile version devel go1.24-1a2b3c buildID=x/y.abc" {
+		t.Errorf("unexpected devel tool ID %q", devel)
+	}
+}
+
+// chdirTemp changes the working directory to a temporary directory, where the
+// build directory of the tool is created
+func chdirTemp(t *testing.T) string {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	return dir
+}
+
+func TestPrintToolID(t *testing.T) {
+	out, err := exec.Command("go", "env", "GOTOOLDIR").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compile := filepath.Join(strings.TrimSpace(string(out)), "compile")
+	chdirTemp(t)
+	err = os.MkdirAll(filepath.Dir(util.GetPreprocessLogPath(FingerprintFile)),
+		0777)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = util.WriteFile(util.GetPreprocessLogPath(FingerprintFile), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	err = printToolID([]string{compile, ToolIDFlag})
+	os.Stdout = stdout
+	_ = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSpace(string(bs))
+	if !strings.HasPrefix(line, "compile version ") ||
+		!(strings.HasSuffix(line, " "+FingerprintPrefix+"abc") ||
+			strings.HasSuffix(line, ".abc")) {
+		t.Errorf("unexpected tool ID %q", line)
+	}
+}
+
+func newTestBundle(importPath, file, function string) *rules.InstRuleSet {
+	bundle := rules.NewInstRuleSet(importPath)
+	bundle.AddFuncRule(file, &rules.InstFuncRule{
+		InstBaseRule: rules.InstBaseRule{ImportPath: importPath},
+		Function:     function,
+		OnEnter:      "onEnter",
+	})
+	return bundle
+}
+
+func TestFingerprintStability(t *testing.T) {
+	dir := chdirTemp(t)
+	mainFile := filepath.Join(dir, "cmd", "app", "main.go")
+	compute := func(bundles ...*rules.InstRuleSet) *Fingerprints {
+		fps, err := ComputeFingerprints(bundles, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fps
+	}
+	fps := compute(newTestBundle("net/http", "/x/client.go", "Do"),
+		newTestBundle("main", mainFile, "main"))
+	if len(fps.Packages) != 2 || fps.Packages["net/http"] == "" ||
+		fps.Packages["./cmd/app"] == "" {
+		t.Fatalf("unexpected package fingerprints %v", fps.Packages)
+	}
+	// Same inputs yield the same fingerprints regardless of the order
+	same := compute(newTestBundle("main", mainFile, "main"),
+		newTestBundle("net/http", "/x/client.go", "Do"))
+	if same.Tool != fps.Tool ||
+		same.Packages["net/http"] != fps.Packages["net/http"] ||
+		same.Packages["./cmd/app"] != fps.Packages["./cmd/app"] {
+		t.Errorf("fingerprints are not stable: %v, %v", fps, same)
+	}
+	// Changing the rule set of one package leaves the others intact
+	changed := compute(newTestBundle("net/http", "/x/client.go", "Get"),
+		newTestBundle("main", mainFile, "main"))
+	if changed.Tool != fps.Tool ||
+		changed.Packages["./cmd/app"] != fps.Packages["./cmd/app"] {
+		t.Errorf("unrelated fingerprints are changed: %v, %v", fps, changed)
+	}
+	if changed.Packages["net/http"] == fps.Packages["net/http"] {
+		t.Errorf("fingerprint of the changed package is not changed")
+	}
+	// Without package fingerprints, all rule sets are covered by the tool one
+	folded, err := ComputeFingerprints([]*rules.InstRuleSet{
+		newTestBundle("net/http", "/x/client.go", "Get")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folded.Packages) != 0 || folded.Tool == fps.Tool {
+		t.Errorf("unexpected folded fingerprints %v", folded)
+	}
+}
+
+func TestStripFingerprintFlag(t *testing.T) {
+	args := []string{"compile", "-o", "_pkg_.a", FingerprintFlag + "abc",
+		"-p", "net/http"}
+	stripped, fingerprint, err := stripFingerprintFlag(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint != "abc" ||
+		strings.Join(stripped, " ") != "compile -o _pkg_.a -p net/http" {
+		t.Errorf("unexpected result %v %q", stripped, fingerprint)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrument
+
+import (
+	"fmt"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/ast"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+	"github.com/dave/dst"
+	"github.com/dave/dst/dstutil"
+)
+
+// -----------------------------------------------------------------------------
+// Generic Trampoline
+//
+// Generic functions and methods of generic types are instrumented in the same
+// way as the others, except that their trampolines and CallContextImpl{suffix}
+// are generic as well. They declare the same type parameters as the target
+// function, or as the receiver type of the target method, and are instantiated
+// explicitly by the trampoline-jump-if, i.e.
+//
+//	func (r *Repo[T]) Get(id int) (retVal0 T, retVal1 error) {
+//	    if ctx, skip := OtelOnEnterTrampoline_Get_abc[T](&r, &id); skip {
+//	    ...
+//	}
+//
+//	func OtelOnEnterTrampoline_Get_abc[T any](r **Repo[T], id *int) (
+//	    callContext *CallContextImpl_abc[T], skip bool) {...}
+//
+// The type arguments can not always be inferred from the arguments, e.g. the
+// type parameter is only used by the return values, so they are always given.
+// Hook functions are linked by go:linkname and can not be generic, parameters
+// of the hooks are declared as interface{} if their types refer to the type
+// parameters.
+
+// typeParamNames returns the names of the type parameters of the generic
+// function, or of the receiver type of the generic method
+func typeParamNames(funcDecl *dst.FuncDecl) []string {
+	if typeParams := ast.ReceiverTypeParams(funcDecl); typeParams != nil {
+		names := make([]string, 0, len(typeParams))
+		for _, typeParam := range typeParams {
+			names = append(names, typeParam.Name)
+		}
+		return names
+	}
+	if funcDecl.Type.TypeParams == nil {
+		return nil
+	}
+	return getNames(funcDecl.Type.TypeParams)
+}
+
+func isGeneric(funcDecl *dst.FuncDecl) bool {
+	return len(typeParamNames(funcDecl)) > 0
+}
+
+// instantiate instantiates the generic trampoline or call context x with the
+// type parameters of the target function, x is returned as is if the target
+// function is not generic
+func instantiate(x dst.Expr, funcDecl *dst.FuncDecl) dst.Expr {
+	names := typeParamNames(funcDecl)
+	if len(names) == 0 {
+		return x
+	}
+	typeArgs := make([]dst.Expr, 0, len(names))
+	for _, name := range names {
+		typeArgs = append(typeArgs, ast.Ident(name))
+	}
+	return ast.IndexListExpr(x, typeArgs)
+}
+
+// nameTypeParams names the blank type parameters, so that they can be referred
+// by the trampolines
+func nameTypeParams(funcDecl *dst.FuncDecl) {
+	typeParams := ast.ReceiverTypeParams(funcDecl)
+	if funcDecl.Type.TypeParams != nil {
+		for _, field := range funcDecl.Type.TypeParams.List {
+			typeParams = append(typeParams, field.Names...)
+		}
+	}
+	for i, typeParam := range typeParams {
+		if typeParam.Name == "_" {
+			typeParam.Name = fmt.Sprintf("typeParam%d", i)
+		}
+	}
+}
+
+// renameIdents renames the identifiers referring to the type parameters, the
+// selectors of qualified identifiers are never renamed
+func renameIdents(node dst.Node, names map[string]string) {
+	dst.Inspect(node, func(node dst.Node) bool {
+		switch n := node.(type) {
+		case *dst.SelectorExpr:
+			renameIdents(n.X, names)
+			return false
+		case *dst.Ident:
+			if name, ok := names[n.Name]; ok {
+				n.Name = name
+			}
+		}
+		return true
+	})
+}
+
+// refersTo reports whether the type expression refers to any type parameter
+func refersTo(typ dst.Expr, names []string) bool {
+	found := false
+	dst.Inspect(typ, func(node dst.Node) bool {
+		switch n := node.(type) {
+		case *dst.SelectorExpr:
+			found = found || refersTo(n.X, names)
+			return false
+		case *dst.Ident:
+			for _, name := range names {
+				if n.Name == name {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// findTypeSpec finds the declaration of the receiver type, which may be in any
+// file of the compiling package
+func (rp *RuleProcessor) findTypeSpec(typeName string) (*dst.TypeSpec, error) {
+	if spec := ast.FindTypeSpec(rp.target, typeName); spec != nil {
+		return spec, nil
+	}
+	for _, arg := range rp.compileArgs {
+		if !util.IsGoFile(arg) {
+			continue
+		}
+		root, err := ast.ParseFileFast(arg)
+		if err != nil {
+			return nil, err
+		}
+		if spec := ast.FindTypeSpec(root, typeName); spec != nil {
+			return spec, nil
+		}
+	}
+	return nil, ex.Newf("type %s not found", typeName)
+}
+
+// buildTypeParams builds the type parameter list of the generic trampolines,
+// which is the same as the one of the target function, or of the receiver type
+// with the type parameters renamed as the receiver does, e.g. [K comparable, V
+// any] of func (m *Map[K, V]) Get(k K) V, where Map is declared as
+// Map[T comparable, U any]
+func (rp *RuleProcessor) buildTypeParams() (*dst.FieldList, error) {
+	funcDecl := rp.targetFunc
+	recvTypeParams := ast.ReceiverTypeParams(funcDecl)
+	if recvTypeParams == nil {
+		return dst.Clone(funcDecl.Type.TypeParams).(*dst.FieldList), nil
+	}
+	typeName := ast.ReceiverTypeName(funcDecl)
+	if typeName[0] == '*' {
+		typeName = typeName[1:]
+	}
+	spec, err := rp.findTypeSpec(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if spec.TypeParams == nil {
+		return nil, ex.Newf("type %s is not generic", typeName)
+	}
+	typeParams := dst.Clone(spec.TypeParams).(*dst.FieldList)
+	declNames := getNames(typeParams)
+	if len(declNames) != len(recvTypeParams) {
+		return nil, ex.Newf("type parameters of %s mismatch", typeName)
+	}
+	names := make(map[string]string, len(declNames))
+	for i, name := range declNames {
+		names[name] = recvTypeParams[i].Name
+	}
+	renameIdents(typeParams, names)
+	return typeParams, nil
+}
+
+// checkGenericHook checks the parameters of the exact hook function, those of
+// the type parameter types must be declared as interface{}, they are rectified
+// to interface{} later
+func (rp *RuleProcessor) checkGenericHook(t *rules.InstFuncRule,
+	paramTypes *dst.FieldList, traits []ParamTrait) error {
+	names := typeParamNames(rp.targetFunc)
+	for i, field := range paramTypes.List {
+		if i < len(traits) && !traits[i].IsInterfaceAny &&
+			refersTo(field.Type, names) {
+			return ex.Newf("hook of %s must declare parameter %d of type %s "+
+				"as interface{}", t, i, ast.TypeString(field.Type))
+		}
+	}
+	return nil
+}
+
+// genericizeTrampoline declares the type parameters of the target function on
+// the trampolines and the call context, and instantiates the call context
+// wherever it's referred
+func (rp *RuleProcessor) genericizeTrampoline() error {
+	typeParams, err := rp.buildTypeParams()
+	if err != nil {
+		return err
+	}
+	structType := rp.callCtxDecl.Specs[0].(*dst.TypeSpec)
+	structType.TypeParams = typeParams
+	callCtxImplType := structType.Name.Name
+	for _, method := range rp.callCtxMethods {
+		recv := method.Recv.List[0].Type.(*dst.StarExpr)
+		recv.X = instantiate(recv.X, rp.targetFunc)
+	}
+	for _, funcDecl := range []*dst.FuncDecl{rp.onEnterHookFunc, rp.onExitHookFunc} {
+		funcDecl.Type.TypeParams = dst.Clone(typeParams).(*dst.FieldList)
+		// Replace in post-order, otherwise the replacement is traversed again
+		dstutil.Apply(funcDecl, nil, func(c *dstutil.Cursor) bool {
+			if ident, ok := c.Node().(*dst.Ident); ok &&
+				ident.Name == callCtxImplType {
+				c.Replace(instantiate(ident, rp.targetFunc))
+			}
+			return true
+		})
+	}
+	return nil
+}
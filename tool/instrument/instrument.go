@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/alibaba/loongsuite-go-agent/tool/ast"
@@ -46,8 +47,13 @@ type RuleProcessor struct {
 	compileArgs []string
 	// The target function to be instrumented
 	targetFunc *dst.FuncDecl
+	// The information of the target function reported by the CallContext
+	targetInfo *targetInfo
 	// Whether the rule is exact match with target function, or it's a regexp match
 	exact bool
+	// Whether the hook functions are pulled from the hook package via linkname
+	// rather than pushed into the target package by the hook package
+	pullHook bool
 	// The enter hook function, it should be inserted into the target source file
 	onEnterHookFunc *dst.FuncDecl
 	// The exit hook function, it should be inserted into the target source file
@@ -62,6 +68,16 @@ type RuleProcessor struct {
 	callCtxDecl *dst.GenDecl
 	// The methods of the call context
 	callCtxMethods []*dst.FuncDecl
+	// Type information of the compiling package, used by call rules only
+	types *typeInfo
+	// Declarations generated for call rules, keyed by wrapper name
+	callDecls map[string][]dst.Decl
+	// Compiled receiver type patterns of call rules, keyed by pattern
+	calleeRecvRes map[string]*regexp.Regexp
+	// Declarations of pulled hook functions, keyed by declaration name
+	hookDecls map[string]dst.Decl
+	// Report of rules applied to the compiling package
+	report *PackageReport
 }
 
 func newRuleProcessor(args []string, pkgName string) *RuleProcessor {
@@ -76,10 +92,18 @@ func newRuleProcessor(args []string, pkgName string) *RuleProcessor {
 	util.Assert(outputDir != "", "sanity check")
 	// Create a new rule processor
 	rp := &RuleProcessor{
-		workDir:     outputDir,
-		target:      nil,
-		compileArgs: args,
-		relocated:   make(map[string]string),
+		workDir:       outputDir,
+		target:        nil,
+		compileArgs:   args,
+		relocated:     make(map[string]string),
+		callDecls:     make(map[string][]dst.Decl),
+		calleeRecvRes: make(map[string]*regexp.Regexp),
+		hookDecls:     make(map[string]dst.Decl),
+		report: &PackageReport{
+			ImportPath:  util.FindFlagValue(args, util.BuildPattern),
+			PackageName: pkgName,
+			Files:       make([]*FileReport, 0),
+		},
 	}
 	return rp
 }
@@ -180,6 +204,16 @@ func groupRules(rset *rules.InstRuleSet) map[string][]rules.InstRule {
 			file2rules[file] = append(file2rules[file], rule)
 		}
 	}
+	for file, rules := range rset.RawRules {
+		for _, rule := range rules {
+			file2rules[file] = append(file2rules[file], rule)
+		}
+	}
+	for file, rules := range rset.CallRules {
+		for _, rule := range rules {
+			file2rules[file] = append(file2rules[file], rule)
+		}
+	}
 	return file2rules
 }
 
@@ -205,16 +239,29 @@ func (rp *RuleProcessor) applyRules(rset *rules.InstRuleSet) (err error) {
 		for _, r := range rs {
 			switch rt := r.(type) {
 			case *rules.InstFuncRule:
-				err1 := rp.applyFuncRule(rt, root)
+				err1 := rp.applyFuncRule(rt, root, file)
 				if err1 != nil {
 					return err1
 				}
 				hasFuncRule = true
 			case *rules.InstStructRule:
-				err1 := rp.applyStructRule(rt, root)
+				err1 := rp.applyStructRule(rt, root, file)
+				if err1 != nil {
+					return err1
+				}
+			case *rules.InstRawRule:
+				err1 := rp.applyRawRule(rt, root, file)
+				if err1 != nil {
+					return err1
+				}
+			case *rules.InstCallRule:
+				applied, err1 := rp.applyCallRule(rt, root, file)
 				if err1 != nil {
 					return err1
 				}
+				if applied {
+					hasFuncRule = true
+				}
 			default:
 				util.ShouldNotReachHere()
 			}
@@ -232,6 +279,14 @@ func (rp *RuleProcessor) applyRules(rset *rules.InstRuleSet) (err error) {
 			return err
 		}
 	}
+	// Write declarations generated for call rules and pulled hooks to a separate
+	// file, they are shared by all instrumented files of the package
+	if len(rp.callDecls) > 0 || len(rp.hookDecls) > 0 {
+		err = rp.writeSharedDecls(rset.PackageName)
+		if err != nil {
+			return err
+		}
+	}
 	// Write globals file if any function is instrumented because injected code
 	// always requires some global variables and auxiliary declarations
 	if hasFuncRule {
@@ -258,8 +313,10 @@ func stripCompleteFlag(args []string) []string {
 	return args
 }
 
-func compileRemix(bundle *rules.InstRuleSet, args []string) error {
+func compileRemix(bundle *rules.InstRuleSet, args []string,
+	fingerprint string) error {
 	rp := newRuleProcessor(args, bundle.PackageName)
+	rp.report.Version = bundle.Version
 	err := rp.applyRules(bundle)
 	if err != nil {
 		return err
@@ -273,14 +330,25 @@ func compileRemix(bundle *rules.InstRuleSet, args []string) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return rp.writeReport(fingerprint)
 }
 
 func Instrument() error {
 	// Remove the tool itself from the command line arguments
 	args := os.Args[2:]
+	// Is querying the compiler tool ID?
+	if isToolIDQuery(args) {
+		return printToolID(args)
+	}
 	// Is compile command?
 	if util.IsCompileCommand(strings.Join(args, " ")) {
+		// The package fingerprint is not a real compiler flag, see cache.go
+		var fingerprint string
+		var err error
+		args, fingerprint, err = stripFingerprintFlag(args)
+		if err != nil {
+			return err
+		}
 		if config.GetConf().Verbose {
 			util.Log("RunCmd: %v", args)
 		}
@@ -293,7 +361,7 @@ func Instrument() error {
 			// Is compiling the target package?
 			if matchImportPath(bundle.ImportPath, args) {
 				util.Log("Apply bundle %v", bundle)
-				err = compileRemix(bundle, args)
+				err = compileRemix(bundle, args, fingerprint)
 				if err != nil {
 					return err
 				}
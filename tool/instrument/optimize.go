@@ -158,13 +158,15 @@ func (rp *RuleProcessor) newCallContextImpl(tjump *TJump) (dst.Expr, error) {
 	// directive
 	// One line please, otherwise debugging line number will be a nightmare
 	tmpl := fmt.Sprintf("&CallContextImpl%s{Params:[]interface{}{},ReturnVals:[]interface{}{}}",
-		util.Crc32(tjump.rule.String()))
+		makeSuffix(tjump.rule, tjump.target))
 	p := ast.NewAstParser()
 	astRoot, err := p.ParseSnippet(tmpl)
 	if err != nil {
 		return nil, err
 	}
 	ctxExpr := astRoot[0].(*dst.ExprStmt).X
+	ctxLit := ctxExpr.(*dst.UnaryExpr).X.(*dst.CompositeLit)
+	ctxLit.Type = instantiate(ctxLit.Type, tjump.target)
 	// Replenish call context by passing addresses of all arguments
 	replenishCallContextLiteral(tjump, ctxExpr)
 	return ctxExpr, nil
@@ -0,0 +1,208 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrument
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/ast"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+	"github.com/dave/dst"
+)
+
+// -----------------------------------------------------------------------------
+// Instrumentation Report
+//
+// Every compile command that instruments a package writes down what has been
+// applied to the package, i.e. the instrumented files and functions, the rules
+// and where their hooks come from. Packages are compiled by different processes,
+// so each of them writes a separate report, which are merged by the preprocess
+// phase once the build is done.
+//
+// Packages whose inputs stay unchanged are not compiled again but reused from
+// the build cache, so reports are kept across builds. Each report is named after
+// the fingerprint the package is compiled with, since a cached package is only
+// reused as long as its fingerprint stays unchanged, its report of the previous
+// build is still valid in this case. Only reports whose fingerprints are
+// computed by the current build are merged, which rules out packages that are
+// not part of it.
+
+const ReportDir = "report"
+
+const (
+	KindFunc   = "func"
+	KindStruct = "struct"
+	KindFile   = "file"
+	KindRaw    = "raw"
+	KindCall   = "call"
+)
+
+// AppliedRule describes a rule applied to a specific target
+type AppliedRule struct {
+	// Kind of the rule, e.g. "func"
+	Kind string `json:"Kind"`
+	// The target of the rule, i.e. the function, struct, file or callee name
+	Target string `json:"Target"`
+	// Import path of the package where the hooks or the file come from
+	HookPackage string `json:"HookPackage,omitempty"`
+	// Module path of the rule
+	Module string `json:"Module,omitempty"`
+	// Version of the rule module, it's filled when reports are merged
+	ModuleVersion string `json:"ModuleVersion,omitempty"`
+	// The rule definition
+	Rule json.RawMessage `json:"Rule"`
+}
+
+// FileReport describes rules applied to a source file of the package, or the
+// file added by the file rule
+type FileReport struct {
+	File  string         `json:"File"`
+	Rules []*AppliedRule `json:"Rules"`
+}
+
+// PackageReport describes rules applied to a package
+type PackageReport struct {
+	ImportPath  string `json:"ImportPath"`
+	PackageName string `json:"PackageName"`
+	// Version of the module that contains the package, standard library
+	// packages have no version
+	Version string        `json:"Version,omitempty"`
+	Files   []*FileReport `json:"Files"`
+}
+
+// funcDeclName returns the name of the function declaration along with its
+// receiver type, e.g. "(*Client).Do"
+func funcDeclName(funcDecl *dst.FuncDecl) string {
+	if recv := ast.ReceiverTypeName(funcDecl); recv != "" {
+		return "(" + recv + ")." + funcDecl.Name.Name
+	}
+	return funcDecl.Name.Name
+}
+
+// reportRule records that the rule is applied to the target of the file
+func (rp *RuleProcessor) reportRule(file, kind, target string,
+	rule rules.InstRule) {
+	applied := &AppliedRule{
+		Kind:   kind,
+		Target: target,
+		Rule:   json.RawMessage(rule.String()),
+	}
+	// Find out where the hooks come from, failing to do so is tolerable as
+	// this is only for reporting
+	var err error
+	switch rl := rule.(type) {
+	case *rules.InstFuncRule:
+		if !rl.UseRaw && rl.GetPath() != "" {
+			var hookFile string
+			hookFile, err = findHookFile(rl)
+			if err == nil {
+				applied.Module, applied.HookPackage, err =
+					findImportPathOf(rl.GetPath(), hookFile)
+			}
+		}
+	case *rules.InstCallRule:
+		var hookFile string
+		hookFile, err = findHookFile(asFuncRule(rl))
+		if err == nil {
+			applied.Module, applied.HookPackage, err =
+				findImportPathOf(rl.GetPath(), hookFile)
+		}
+	case *rules.InstFileRule:
+		applied.Module, applied.HookPackage, err =
+			findImportPathOf(rl.GetPath(), rl.FileName)
+		if err == nil {
+			// The file is extracted to a temporary directory, refer to it by
+			// import path instead
+			file = path.Join(applied.HookPackage, filepath.Base(rl.FileName))
+		}
+	}
+	if err != nil {
+		util.Log("Failed to find hook package of rule %s: %v", rule, err)
+	}
+
+	for _, fr := range rp.report.Files {
+		if fr.File == file {
+			fr.Rules = append(fr.Rules, applied)
+			return
+		}
+	}
+	rp.report.Files = append(rp.report.Files, &FileReport{
+		File:  file,
+		Rules: []*AppliedRule{applied},
+	})
+}
+
+// GetReportDir returns the directory where package reports are written to
+func GetReportDir() string {
+	return util.GetTempBuildDirWith(ReportDir)
+}
+
+// GetReportFingerprint returns the fingerprint of the package report file
+func GetReportFingerprint(file string) string {
+	fingerprint, _, _ := strings.Cut(filepath.Base(file), "_")
+	return fingerprint
+}
+
+// writeReport writes the report of the package compiled with the fingerprint
+func (rp *RuleProcessor) writeReport(fingerprint string) error {
+	if len(rp.report.Files) == 0 {
+		return nil
+	}
+	dir := GetReportDir()
+	err := os.MkdirAll(dir, 0777)
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	// All main packages are compiled as "main", the files tell them apart
+	files := make([]string, 0, len(rp.report.Files))
+	for _, fr := range rp.report.Files {
+		files = append(files, fr.File)
+	}
+	sort.Strings(files)
+	escaper := strings.NewReplacer("/", "_", ".", "_")
+	name := fingerprint + "_" + escaper.Replace(rp.report.ImportPath) + "_" +
+		util.Crc32(strings.Join(files, ",")) + ".json"
+	bs, err := json.MarshalIndent(rp.report, "", "  ")
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	// The same package may be compiled more than once concurrently, e.g. the
+	// test variant, write to a temporary file and rename it to avoid partial
+	// writes
+	temp, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	_, err = temp.Write(bs)
+	if err1 := temp.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		return ex.Wrap(err)
+	}
+	err = os.Rename(temp.Name(), filepath.Join(dir, name))
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	return nil
+}
@@ -16,7 +16,10 @@ package instrument
 
 import (
 	_ "embed"
+	"fmt"
 	"go/token"
+	"path"
+	"path/filepath"
 	"strconv"
 
 	"github.com/alibaba/loongsuite-go-agent/tool/ast"
@@ -24,6 +27,7 @@ import (
 	"github.com/alibaba/loongsuite-go-agent/tool/rules"
 	"github.com/alibaba/loongsuite-go-agent/tool/util"
 	"github.com/dave/dst"
+	"golang.org/x/mod/modfile"
 )
 
 // -----------------------------------------------------------------------------
@@ -42,11 +46,17 @@ const (
 	TrampolineGetParamName           = "GetParam"
 	TrampolineSetReturnValName       = "SetReturnVal"
 	TrampolineGetReturnValName       = "GetReturnVal"
+	TrampolineGetFuncName            = "GetFuncName"
+	TrampolineGetPackageName         = "GetPackageName"
+	TrampolineGetPackagePath         = "GetPackagePath"
+	TrampolineGetReceiverType        = "GetReceiverType"
+	TrampolineGetSourceFile          = "GetSourceFile"
+	TrampolineGetSourceLine          = "GetSourceLine"
+	TrampolineGetParamNames          = "GetParamNames"
+	TrampolineGetResultNames         = "GetResultNames"
 	TrampolineValIdentifier          = "val"
 	TrampolineCtxIdentifier          = "c"
 	TrampolineParamsIdentifier       = "Params"
-	TrampolineFuncNameIdentifier     = "FuncName"
-	TrampolinePackageNameIdentifier  = "PackageName"
 	TrampolineReturnValsIdentifier   = "ReturnVals"
 	TrampolineSkipName               = "skip"
 	TrampolineCallContextName        = "callContext"
@@ -56,6 +66,8 @@ const (
 	TrampolineOnExitName             = "OtelOnExitTrampoline"
 	TrampolineOnEnterNamePlaceholder = "\"OtelOnEnterNamePlaceholder\""
 	TrampolineOnExitNamePlaceholder  = "\"OtelOnExitNamePlaceholder\""
+	TrampolineRuleNamePlaceholder    = "\"OtelRuleNamePlaceholder\""
+	TrampolineHookName               = "OtelHook"
 )
 
 // @@ Modification on this trampoline template should be cautious, as it imposes
@@ -128,12 +140,18 @@ func getNames(list *dst.FieldList) []string {
 	return names
 }
 
-func makeOnXName(t *rules.InstFuncRule, onEnter bool) string {
+func (rp *RuleProcessor) makeOnXName(t *rules.InstFuncRule, onEnter bool) string {
+	hook := t.OnExit
 	if onEnter {
-		return t.OnEnter
-	} else {
-		return t.OnExit
+		hook = t.OnEnter
+	}
+	if rp.pullHook {
+		// Pulled hooks are declared in the target package, rename them to avoid
+		// conflicts with declarations of the target package
+		return fmt.Sprintf("%s_%s%s", TrampolineHookName, hook,
+			util.Crc32(t.String()))
 	}
+	return hook
 }
 
 type ParamTrait struct {
@@ -178,6 +196,36 @@ func findHookFile(rule *rules.InstFuncRule) (string, error) {
 		rule.OnEnter, rule.OnExit, rule.Function, files)
 }
 
+// findHookImportPath finds the import path of the package where the hooks are
+// defined, pulled hooks are referred by their symbol names
+func findHookImportPath(t *rules.InstFuncRule) (string, error) {
+	file, err := findHookFile(t)
+	if err != nil {
+		return "", err
+	}
+	_, importPath, err := findImportPathOf(t.GetPath(), file)
+	return importPath, err
+}
+
+// findImportPathOf finds the module path of the rule and the import path of the
+// package where the file is located
+func findImportPathOf(ruleDir, file string) (string, string, error) {
+	gomod := filepath.Join(ruleDir, util.GoModFile)
+	content, err := util.ReadFile(gomod)
+	if err != nil {
+		return "", "", err
+	}
+	modPath := modfile.ModulePath([]byte(content))
+	if modPath == "" {
+		return "", "", ex.Newf("no module path found in %s", gomod)
+	}
+	rel, err := filepath.Rel(ruleDir, filepath.Dir(file))
+	if err != nil {
+		return "", "", ex.Wrap(err)
+	}
+	return modPath, path.Join(modPath, filepath.ToSlash(rel)), nil
+}
+
 func listRuleFiles(rule rules.InstRule) ([]string, error) {
 	files, err := util.ListFiles(rule.GetPath())
 	if err != nil {
@@ -233,6 +281,43 @@ func getHookParamTraits(t *rules.InstFuncRule, onEnter bool) ([]ParamTrait, erro
 	return attrs, nil
 }
 
+// CheckHooks checks if hooks of the rule can be found as they would be during
+// instrumentation, the rule path must be a local directory. Hooks of call rules
+// and pattern rules are shared by different functions, they must only accept
+// CallContext
+func CheckHooks(rule rules.InstRule) error {
+	var t *rules.InstFuncRule
+	exact := false
+	switch rl := rule.(type) {
+	case *rules.InstFuncRule:
+		if rl.UseRaw {
+			return nil
+		}
+		t, exact = rl, rl.IsExact()
+	case *rules.InstCallRule:
+		t = asFuncRule(rl)
+	default:
+		return nil
+	}
+	for _, onEnter := range []bool{true, false} {
+		hook := t.OnEnter
+		if !onEnter {
+			hook = t.OnExit
+		}
+		if hook == "" {
+			continue
+		}
+		traits, err := getHookParamTraits(t, onEnter)
+		if err != nil {
+			return err
+		}
+		if !exact && len(traits) != 1 {
+			return ex.Newf("hook %s must only accept CallContext", hook)
+		}
+	}
+	return nil
+}
+
 func (rp *RuleProcessor) callOnEnterHook(t *rules.InstFuncRule, traits []ParamTrait) error {
 	// The actual parameter list of hook function should be the same as the
 	// target function
@@ -255,7 +340,7 @@ func (rp *RuleProcessor) callOnEnterHook(t *rules.InstFuncRule, traits []ParamTr
 			}
 		}
 	}
-	fnName := makeOnXName(t, true)
+	fnName := rp.makeOnXName(t, true)
 	call := ast.ExprStmt(ast.CallTo(fnName, args))
 	iff := ast.IfNotNilStmt(
 		dst.NewIdent(fnName),
@@ -296,7 +381,7 @@ func (rp *RuleProcessor) callOnExitHook(t *rules.InstFuncRule, traits []ParamTra
 			}
 		}
 	}
-	fnName := makeOnXName(t, false)
+	fnName := rp.makeOnXName(t, false)
 	call := ast.ExprStmt(ast.CallTo(fnName, args))
 	iff := ast.IfNotNilStmt(
 		dst.NewIdent(fnName),
@@ -329,6 +414,12 @@ func (rp *RuleProcessor) addHookFuncVar(t *rules.InstFuncRule,
 	}
 	addCallContext(paramTypes)
 	if rp.exact {
+		if isGeneric(rp.targetFunc) {
+			err := rp.checkGenericHook(t, paramTypes, traits)
+			if err != nil {
+				return err
+			}
+		}
 		// Hook functions may uses interface{} as parameter type, as some types of
 		// raw function is not exposed
 		err := rectifyAnyType(paramTypes, traits)
@@ -342,7 +433,7 @@ func (rp *RuleProcessor) addHookFuncVar(t *rules.InstFuncRule,
 	// multiple hook function declarations to the same file, so we need to check
 	// if the hook function variable is already declared in the target file
 	exist := false
-	fnName := makeOnXName(t, onEnter)
+	fnName := rp.makeOnXName(t, onEnter)
 	funcDecl := &dst.FuncDecl{
 		Name: &dst.Ident{
 			Name: fnName,
@@ -352,6 +443,23 @@ func (rp *RuleProcessor) addHookFuncVar(t *rules.InstFuncRule,
 			Params: paramTypes,
 		},
 	}
+	if rp.pullHook {
+		// Link the hook declaration to the real hook function, it's shared by
+		// all target functions of the package
+		hookPath, err := findHookImportPath(t)
+		if err != nil {
+			return err
+		}
+		hook := t.OnExit
+		if onEnter {
+			hook = t.OnEnter
+		}
+		funcDecl.Decs.Before = dst.NewLine
+		funcDecl.Decs.Start.Append(fmt.Sprintf("//go:linkname %s %s.%s",
+			fnName, hookPath, hook))
+		rp.hookDecls[fnName] = funcDecl
+		return nil
+	}
 	for _, decl := range rp.target.Decls {
 		if fDecl, ok := decl.(*dst.FuncDecl); ok {
 			if fDecl.Name.Name == fnName {
@@ -377,14 +485,27 @@ func insertAtEnd(funcDecl *dst.FuncDecl, stmt dst.Stmt) {
 	insertAt(funcDecl, stmt, len(funcDecl.Body.List))
 }
 
+// ruleName names the rule in self telemetry, i.e. the import path followed by
+// the receiver type and function it matches, e.g. net/http.(*Client).Do
+func ruleName(t *rules.InstFuncRule) string {
+	if t.ReceiverType != "" {
+		return fmt.Sprintf("%s.(%s).%s", t.ImportPath, t.ReceiverType,
+			t.Function)
+	}
+	return t.ImportPath + "." + t.Function
+}
+
 func (rp *RuleProcessor) renameTrampolineFunc(t *rules.InstFuncRule) {
 	// Randomize trampoline function names
 	rp.onEnterHookFunc.Name.Name = makeName(t, rp.targetFunc, true)
 	dst.Inspect(rp.onEnterHookFunc, func(node dst.Node) bool {
 		if basicLit, ok := node.(*dst.BasicLit); ok {
 			// Replace OtelOnEnterTrampolinePlaceHolder to real hook func name
-			if basicLit.Value == TrampolineOnEnterNamePlaceholder {
+			switch basicLit.Value {
+			case TrampolineOnEnterNamePlaceholder:
 				basicLit.Value = strconv.Quote(t.OnEnter)
+			case TrampolineRuleNamePlaceholder:
+				basicLit.Value = strconv.Quote(ruleName(t))
 			}
 		}
 		return true
@@ -392,8 +513,11 @@ func (rp *RuleProcessor) renameTrampolineFunc(t *rules.InstFuncRule) {
 	rp.onExitHookFunc.Name.Name = makeName(t, rp.targetFunc, false)
 	dst.Inspect(rp.onExitHookFunc, func(node dst.Node) bool {
 		if basicLit, ok := node.(*dst.BasicLit); ok {
-			if basicLit.Value == TrampolineOnExitNamePlaceholder {
+			switch basicLit.Value {
+			case TrampolineOnExitNamePlaceholder:
 				basicLit.Value = strconv.Quote(t.OnExit)
+			case TrampolineRuleNamePlaceholder:
+				basicLit.Value = strconv.Quote(ruleName(t))
 			}
 		}
 		return true
@@ -448,20 +572,6 @@ func (rp *RuleProcessor) buildTrampolineTypes() {
 	addCallContext(onExitHookFunc.Type.Params)
 }
 
-func assignString(assignStmt *dst.AssignStmt, val string) bool {
-	rhs := assignStmt.Rhs
-	if len(rhs) == 1 {
-		rhsExpr := rhs[0]
-		if basicLit, ok2 := rhsExpr.(*dst.BasicLit); ok2 {
-			if basicLit.Kind == token.STRING {
-				basicLit.Value = strconv.Quote(val)
-				return true
-			}
-		}
-	}
-	return false
-}
-
 func assignSliceLiteral(assignStmt *dst.AssignStmt, vals []dst.Expr) bool {
 	rhs := assignStmt.Rhs
 	if len(rhs) == 1 {
@@ -485,35 +595,21 @@ func (rp *RuleProcessor) replenishCallContext(onEnter bool) bool {
 	for _, stmt := range funcDecl.Body.List {
 		if assignStmt, ok := stmt.(*dst.AssignStmt); ok {
 			lhs := assignStmt.Lhs
-			if sel, ok := lhs[0].(*dst.SelectorExpr); ok {
-				switch sel.Sel.Name {
-				case TrampolineFuncNameIdentifier:
-					util.Assert(onEnter, "sanity check")
-					// callContext.FuncName = "..."
-					assigned := assignString(assignStmt, rp.targetFunc.Name.Name)
-					util.Assert(assigned, "sanity check")
-				case TrampolinePackageNameIdentifier:
-					util.Assert(onEnter, "sanity check")
-					// callContext.PackageName = "..."
-					assigned := assignString(assignStmt, rp.target.Name.Name)
-					util.Assert(assigned, "sanity check")
-				default:
-					// callContext.Params = []interface{}{...} or
-					// callContext.(*CallContextImpl).Params[0] = &int
-					names := getNames(funcDecl.Type.Params)
-					vals := make([]dst.Expr, 0, len(names))
-					for i, name := range names {
-						if i == 0 && !onEnter {
-							// SKip first callContext parameter for after
-							continue
-						}
-						vals = append(vals, ast.Ident(name))
+			if _, ok := lhs[0].(*dst.SelectorExpr); ok {
+				// callContext.Params = []interface{}{...} or
+				// callContext.(*CallContextImpl).Params[0] = &int
+				names := getNames(funcDecl.Type.Params)
+				vals := make([]dst.Expr, 0, len(names))
+				for i, name := range names {
+					if i == 0 && !onEnter {
+						// SKip first callContext parameter for after
+						continue
 					}
-					assigned := assignSliceLiteral(assignStmt, vals)
-					util.Assert(assigned, "sanity check")
+					vals = append(vals, ast.Ident(name))
 				}
+				assigned := assignSliceLiteral(assignStmt, vals)
+				util.Assert(assigned, "sanity check")
 			}
-
 		}
 	}
 	return true
@@ -534,7 +630,7 @@ func (rp *RuleProcessor) replenishCallContext(onEnter bool) bool {
 // renaming occurrences of CallContextImpl to CallContextImpl{suffix} in the
 // trampoline template
 func (rp *RuleProcessor) implementCallContext(t *rules.InstFuncRule) {
-	suffix := util.Crc32(t.String())
+	suffix := makeSuffix(t, rp.targetFunc)
 	structType := rp.callCtxDecl.Specs[0].(*dst.TypeSpec)
 	util.Assert(structType.Name.Name == TrampolineCallContextImplType,
 		"sanity check")
@@ -687,12 +783,78 @@ func (rp *RuleProcessor) rewriteCallContext() {
 	}
 }
 
+// targetInfo describes the instrumented function, all of them are known at
+// compile time and reported to hooks by the CallContext
+type targetInfo struct {
+	funcName     string
+	packageName  string
+	packagePath  string
+	receiverType string
+	sourceFile   string
+	sourceLine   int
+	paramNames   []string
+	resultNames  []string
+}
+
+func stringsLit(vals []string) dst.Expr {
+	if len(vals) == 0 {
+		return ast.Ident("nil")
+	}
+	elts := make([]dst.Expr, 0, len(vals))
+	for _, val := range vals {
+		elts = append(elts, ast.StringLit(val))
+	}
+	return &dst.CompositeLit{
+		Type: ast.ArrayType(ast.Ident("string")),
+		Elts: elts,
+	}
+}
+
+// describeCallContext replaces the return values of descriptive CallContext
+// methods with the information of the target function, so that hooks can get
+// them without any runtime cost
+func (rp *RuleProcessor) describeCallContext() {
+	info := rp.targetInfo
+	util.Assert(info != nil, "sanity check")
+	for _, decl := range rp.callCtxMethods {
+		var result dst.Expr
+		switch decl.Name.Name {
+		case TrampolineGetFuncName:
+			result = ast.StringLit(info.funcName)
+		case TrampolineGetPackageName:
+			result = ast.StringLit(info.packageName)
+		case TrampolineGetPackagePath:
+			result = ast.StringLit(info.packagePath)
+		case TrampolineGetReceiverType:
+			result = ast.StringLit(info.receiverType)
+		case TrampolineGetSourceFile:
+			result = ast.StringLit(info.sourceFile)
+		case TrampolineGetSourceLine:
+			result = ast.IntLit(info.sourceLine)
+		case TrampolineGetParamNames:
+			result = stringsLit(info.paramNames)
+		case TrampolineGetResultNames:
+			result = stringsLit(info.resultNames)
+		default:
+			continue
+		}
+		ret, ok := decl.Body.List[0].(*dst.ReturnStmt)
+		util.Assert(ok, "sanity check")
+		ret.Results = []dst.Expr{result}
+	}
+}
+
 func (rp *RuleProcessor) callHookFunc(t *rules.InstFuncRule,
 	onEnter bool) error {
 	traits, err := getHookParamTraits(t, onEnter)
 	if err != nil {
 		return err
 	}
+	// Hooks of the pattern rule are shared by functions of different
+	// signatures, they only accept CallContext
+	if !rp.exact && len(traits) != 1 {
+		return ex.Newf("hook of %s must only accept CallContext", t)
+	}
 	err = rp.addHookFuncVar(t, traits, onEnter)
 	if err != nil {
 		return err
@@ -724,12 +886,22 @@ func (rp *RuleProcessor) createTrampoline(t *rules.InstFuncRule) error {
 	// Make all HookContext methods type-aware according to the target function
 	// signature.
 	rp.rewriteCallContext()
+	// Fill in the information of the target function
+	rp.describeCallContext()
 	// Rename template function to trampoline function
 	rp.renameTrampolineFunc(t)
 	// Build types of trampoline functions. The parameters of the Before trampoline
 	// function are the same as the target function, the parameters of the After
 	// trampoline function are the same as the target function.
 	rp.buildTrampolineTypes()
+	// Declare type parameters of generic target function on the trampoline
+	// functions and the call context
+	if isGeneric(rp.targetFunc) {
+		err = rp.genericizeTrampoline()
+		if err != nil {
+			return err
+		}
+	}
 	// Generate calls to hook functions
 	if t.OnEnter != "" {
 		err = rp.callHookFunc(t, true)
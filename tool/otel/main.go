@@ -31,6 +31,8 @@ const (
 	SubcommandGo      = "go"
 	SubcommandVersion = "version"
 	SubcommandRemix   = "remix"
+	SubcommandRules   = "rules"
+	SubcommandInspect = "inspect"
 )
 
 var usage = `Usage: {} <command> [args]
@@ -40,11 +42,15 @@ Example:
 	{} go build main.go
 	{} version
 	{} set -verbose -rule=custom.json
+	{} rules explain go build
+	{} inspect ./app
 
 Command:
 	version    print the version
 	set        set the configuration
 	go         build the Go application
+	rules      list, validate or explain the rules
+	inspect    print the instrumentation manifest of the binary
 `
 
 func printUsage() {
@@ -86,6 +92,10 @@ func initEnv() error {
 	case strings.HasSuffix(os.Args[1], SubcommandGo):
 		// otel go build?
 		util.SetRunPhase(util.PPreprocess)
+	case os.Args[1] == SubcommandRules:
+		// otel rules? It shares the configuration and the rule matching with
+		// the preprocess phase
+		util.SetRunPhase(util.PPreprocess)
 	case os.Args[1] == SubcommandRemix:
 		// otel remix?
 		util.SetRunPhase(util.PInstrument)
@@ -130,6 +140,10 @@ func main() {
 		err = preprocess.Preprocess()
 	case SubcommandRemix:
 		err = instrument.Instrument()
+	case SubcommandRules:
+		err = preprocess.Rules(os.Args[2:])
+	case SubcommandInspect:
+		err = preprocess.Inspect(os.Args[2:])
 	default:
 		printUsage()
 	}
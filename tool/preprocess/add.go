@@ -17,6 +17,7 @@ package preprocess
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/alibaba/loongsuite-go-agent/tool/ex"
@@ -110,61 +111,150 @@ func (dp *DepProcessor) findRuleDir(path string) (string, string, error) {
 	}
 }
 
+// writeRuntimeFiles writes the generated declarations to the otel.runtime.go
+// file of the main package, or to the otel.runtime_test.go file of each tested
+// package when running go test
+func (dp *DepProcessor) writeRuntimeFiles(content string) error {
+	if dp.otelRuntimeGo != "" {
+		_, err := util.WriteFile(dp.otelRuntimeGo, "package main\n"+content)
+		if err != nil {
+			return err
+		}
+	}
+	for file, pkgName := range dp.otelRuntimeTests {
+		_, err := util.WriteFile(file, "package "+pkgName+"\n"+content)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// genTraceRules generates the registrations of trace rules, by which the built-in
+// tracer hooks look up the span name template and the captured parameters of
+// the traced functions at runtime. It returns the import declaration and the
+// init function separately, as imports must precede other declarations
+func genTraceRules(bundles []*rules.InstRuleSet) (string, string) {
+	registrations := ""
+	registered := make(map[string]bool)
+	for _, bundle := range bundles {
+		for _, funcRules := range bundle.FuncRules {
+			for _, rule := range funcRules {
+				if !rule.Trace || registered[rule.String()] {
+					continue
+				}
+				registered[rule.String()] = true
+				indices := make([]int, 0, len(rule.TraceParams))
+				for idx := range rule.TraceParams {
+					indices = append(indices, idx)
+				}
+				sort.Ints(indices)
+				params := make([]string, 0, len(indices))
+				for _, idx := range indices {
+					params = append(params, fmt.Sprintf("%d: %q", idx,
+						rule.TraceParams[idx]))
+				}
+				registrations += fmt.Sprintf(
+					"\t_otel_tracer.Register(%q, %q, %q, %q, map[int]string{%s})\n",
+					rule.ImportPath, rule.FunctionRegexp(),
+					rule.ReceiverTypeRegexp(), rule.TraceName,
+					strings.Join(params, ", "))
+			}
+		}
+	}
+	if registrations == "" {
+		return "", ""
+	}
+	return fmt.Sprintf("import _otel_tracer %q\n", rules.TracerPath),
+		"func init() {\n" + registrations + "}\n"
+}
+
 func (dp *DepProcessor) newDeps(bundles []*rules.InstRuleSet) error {
-	content := "package main\n"
+	content := ""
 	builtin := map[string]string{
 		// for go:linkname when declaring printstack/getstack variable
 		"unsafe": "_",
-		// for debug.Stack and log.Printf when declaring printstack/getstack
+		// for debug.Stack and log.Print when declaring printstack/getstack
 		// we do need import alias because user may declare global variable such
 		// as "log" or "debug" in their code, which will conflict with the import
 		"runtime/debug": "_otel_debug",
-		// for log.Printf when declaring printstack/getstack variable
+		// for log.Print when declaring printstack/getstack variable
 		"log": "_otel_log",
+		// for runtime.KeepAlive when declaring the manifest variable
+		"runtime": "_otel_runtime",
+		// for counting and recording panics when declaring hookpanic/recordpanic
+		"github.com/alibaba/loongsuite-go-agent/pkg/core/recovery": "_otel_recovery",
+		// for timing hooks when declaring hookstart/hookend
+		"github.com/alibaba/loongsuite-go-agent/pkg/core/selftelemetry": "_otel_selftelemetry",
 		// otel setup
 		"github.com/alibaba/loongsuite-go-agent/pkg": "_",
 		"go.opentelemetry.io/otel":                   "_",
 		"go.opentelemetry.io/otel/sdk/trace":         "_",
 		"go.opentelemetry.io/otel/baggage":           "_",
 	}
-	for pkg, alias := range builtin {
-		content += fmt.Sprintf("import %s %q\n", alias, pkg)
+	// Sort imports to make sure the generated file is deterministic
+	pkgs := make([]string, 0, len(builtin))
+	for pkg := range builtin {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		content += fmt.Sprintf("import %s %q\n", builtin[pkg], pkg)
+	}
+	manifest, err := genManifest(bundles)
+	if err != nil {
+		return err
 	}
 
 	// No rule bundles? We still need to generate the otel_importer.go file whose
 	// purpose is to import the fundamental dependencies
 	if len(bundles) == 0 {
-		_, err := util.WriteFile(dp.otelRuntimeGo, content)
+		return dp.writeRuntimeFiles(content + manifest)
+	}
+
+	// Generate the otel.runtime.go file with the rule bundles
+	addDeps := make([]Dependency, 0)
+	addRuleDep := func(path string) error {
+		if path == "" {
+			return nil
+		}
+		moduleName, replacePath, err := dp.findRuleDir(path)
 		if err != nil {
 			return err
 		}
+		content += fmt.Sprintf("import _ %q\n", moduleName)
+		addDeps = append(addDeps, Dependency{
+			ImportPath: moduleName,
+			// use latest version for the rule import
+			Version:        "v0.0.0-00010101000000-000000000000",
+			Replace:        true,
+			ReplacePath:    replacePath,
+			ReplaceVersion: "",
+		})
 		return nil
 	}
-
-	// Generate the otel.runtime.go file with the rule bundles
-	addDeps := make([]Dependency, 0)
 	for _, bundle := range bundles {
 		for _, funcRules := range bundle.FuncRules {
 			for _, rule := range funcRules {
-				path := rule.GetPath()
-				if path != "" {
-					moduleName, replacePath, err := dp.findRuleDir(path)
-					if err != nil {
-						return err
-					}
-					content += fmt.Sprintf("import _ %q\n", moduleName)
-					addDeps = append(addDeps, Dependency{
-						ImportPath: moduleName,
-						// use latest version for the rule import
-						Version:        "v0.0.0-00010101000000-000000000000",
-						Replace:        true,
-						ReplacePath:    replacePath,
-						ReplaceVersion: "",
-					})
+				err := addRuleDep(rule.GetPath())
+				if err != nil {
+					return err
+				}
+			}
+		}
+		// Hooks of call rules are referred by the caller packages, they should
+		// be linked into the final binary as well
+		for _, callRules := range bundle.CallRules {
+			for _, rule := range callRules {
+				err := addRuleDep(rule.GetPath())
+				if err != nil {
+					return err
 				}
 			}
 		}
 	}
+	traceImport, traceInit := genTraceRules(bundles)
+	content += traceImport
 	cnt := 0
 	for _, bundle := range bundles {
 		tag := ""
@@ -184,11 +274,47 @@ func (dp *DepProcessor) newDeps(bundles []*rules.InstRuleSet) error {
 				cnt, bundle.ImportPath)
 		}
 		content += tag
-		s = fmt.Sprintf("var _printstack%d = func (bt []byte){ _otel_log.Printf(string(bt)) }\n", cnt)
+		s = fmt.Sprintf("var _printstack%d = func (bt []byte){ _otel_log.Print(string(bt)) }\n", cnt)
+		content += s
+		if bundle.ImportPath != "main" {
+			tag = fmt.Sprintf("//go:linkname _hookpanic%d %s.OtelHookPanicImpl\n",
+				cnt, bundle.ImportPath)
+		}
+		content += tag
+		s = fmt.Sprintf("var _hookpanic%d = _otel_recovery.ReportHookPanic\n", cnt)
+		content += s
+		// The recorder is nil unless recording panics is enabled at runtime, so
+		// that trampolines never intercept panics by default. Note that linked
+		// variables must be initialized statically, otherwise they are treated
+		// as references rather than definitions by the linker
+		if bundle.ImportPath != "main" {
+			tag = fmt.Sprintf("//go:linkname _recordpanic%d %s.OtelPanicRecorderImpl\n",
+				cnt, bundle.ImportPath)
+		}
+		content += tag
+		s = fmt.Sprintf("var _recordpanic%d = _otel_recovery.Recorder\n", cnt)
+		content += s
+		// Hooks are not timed unless self telemetry is enabled at runtime, in
+		// which case the hook start time is non-zero
+		if bundle.ImportPath != "main" {
+			tag = fmt.Sprintf("//go:linkname _hookstart%d %s.OtelHookStartImpl\n",
+				cnt, bundle.ImportPath)
+		}
+		content += tag
+		s = fmt.Sprintf("var _hookstart%d = _otel_selftelemetry.HookStart\n", cnt)
+		content += s
+		if bundle.ImportPath != "main" {
+			tag = fmt.Sprintf("//go:linkname _hookend%d %s.OtelHookEndImpl\n",
+				cnt, bundle.ImportPath)
+		}
+		content += tag
+		s = fmt.Sprintf("var _hookend%d = _otel_selftelemetry.HookEnd\n", cnt)
 		content += s
 		cnt++
 	}
-	_, err := util.WriteFile(dp.otelRuntimeGo, content)
+	content += traceInit
+	content += manifest
+	err = dp.writeRuntimeFiles(content)
 	if err != nil {
 		return err
 	}
@@ -81,6 +81,7 @@ func runDryBuild(goBuildCmd []string) ([]string, error) {
 	// @@Note that dir should not be set, as the dry build should be run in the
 	// same directory as the original build command
 	cmd.Dir = ""
+	cmd.Env = append(os.Environ(), getGoEnv()...)
 	err = cmd.Run()
 	if err != nil {
 		return nil, ex.Wrapf(err, "command %v", args)
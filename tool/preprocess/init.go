@@ -111,10 +111,14 @@ import (
 // Directory and file names that begin with "." or "_" are ignored
 // by the go tool, as are directories named "testdata".
 
-func tryLoadPackage(path string) ([]*packages.Package, error) {
+func tryLoadPackage(path string, tests bool) ([]*packages.Package, error) {
 	cfg := &packages.Config{
 		// Change it unless you know what you are doing
 		Mode: packages.NeedModule | packages.NeedFiles | packages.NeedName,
+		Env:  append(os.Environ(), getGoEnv()...),
+		// Load test variants of packages as well, so that we know where the
+		// test files are located
+		Tests: tests,
 	}
 
 	pkgs, err := packages.Load(cfg, path)
@@ -124,20 +128,69 @@ func tryLoadPackage(path string) ([]*packages.Package, error) {
 	return pkgs, nil
 }
 
+// Build flags of go run that take the value as a separate argument, e.g.
+// go run -tags foo .
+var runValueFlags = map[string]bool{
+	"C": true, "p": true, "covermode": true, "coverpkg": true,
+	"asmflags": true, "buildmode": true, "compiler": true, "gccgoflags": true,
+	"gcflags": true, "installsuffix": true, "ldflags": true, "mod": true,
+	"modfile": true, "overlay": true, "pgo": true, "pkgdir": true,
+	"tags": true, "toolexec": true, "exec": true, "o": true,
+}
+
+// findRunArgs returns the main package of go run, which is either a single
+// package or a list of .go files, arguments following it are passed to the
+// program rather than the go command
+func findRunArgs(buildCmd []string) []string {
+	for i := 2; i < len(buildCmd); i++ {
+		arg := buildCmd[i]
+		if strings.HasPrefix(arg, "-") {
+			name := strings.TrimLeft(arg, "-")
+			if !strings.Contains(name, "=") && runValueFlags[name] {
+				i++
+			}
+			continue
+		}
+		if !strings.HasSuffix(arg, ".go") {
+			return buildCmd[i : i+1]
+		}
+		j := i
+		for j < len(buildCmd) && strings.HasSuffix(buildCmd[j], ".go") {
+			j++
+		}
+		return buildCmd[i:j]
+	}
+	return nil
+}
+
 func findModule(buildCmd []string) ([]*packages.Package, error) {
 	candidates := make([]*packages.Package, 0)
 	found := false
+	tests := buildCmd[1] == util.GoCmdTest
+
+	args := buildCmd
+	if buildCmd[1] == util.GoCmdRun {
+		// Stop at the main package, program arguments are not packages
+		args = append([]string{buildCmd[0], buildCmd[1]},
+			findRunArgs(buildCmd)...)
+	}
 
 	// Find from build arguments e.g. go build test.go or go build cmd/app
-	for i := len(buildCmd) - 1; i >= 0; i-- {
-		buildArg := buildCmd[i]
+	for i := len(args) - 1; i >= 0; i-- {
+		buildArg := args[i]
 
-		// Stop canary when we see a build flag or a "build" command
-		if strings.HasPrefix("-", buildArg) ||
-			buildArg == "build" ||
-			buildArg == "install" {
+		// Stop canary when we see a "build" command, or a build flag preceding
+		// the packages. Flags may also follow the packages for go test, e.g.
+		// go test ./... -run TestFoo, they are skipped until packages are found
+		if util.IsGoBuildSubCmd(buildArg) {
 			break
 		}
+		if strings.HasPrefix(buildArg, "-") {
+			if found {
+				break
+			}
+			continue
+		}
 
 		// Special case. If the file named with test_ prefix, we create a fake
 		// package for it. This is a workaround for the case that the test file
@@ -157,7 +210,7 @@ func findModule(buildCmd []string) ([]*packages.Package, error) {
 		// because we don't know what the build argument is. One exception is
 		// when we already found packages, in this case, we expect subsequent
 		// build arguments are packages, so we should not tolerate any error.
-		pkgs, err := tryLoadPackage(buildArg)
+		pkgs, err := tryLoadPackage(buildArg, tests)
 		if err != nil {
 			if found {
 				// If packages are already found, we expect subsequent build
@@ -179,7 +232,7 @@ func findModule(buildCmd []string) ([]*packages.Package, error) {
 	// If no import paths are given, the action applies to the package in the
 	// current directory.
 	if !found {
-		pkgs, err := tryLoadPackage(".")
+		pkgs, err := tryLoadPackage(".", tests)
 		if err != nil {
 			return nil, err
 		}
@@ -213,11 +266,11 @@ func findGoMod(dir string) (string, error) {
 	return "", ex.Newf("cannot find go.mod")
 }
 
-func (dp *DepProcessor) initCmd() {
+func (dp *DepProcessor) initCmd(buildCmd []string) {
 	// There is a tricky, all arguments after the otel tool itself are saved for
 	// later use, which means the subcommand "go build" itself are also included
-	dp.goBuildCmd = make([]string, len(os.Args)-1)
-	copy(dp.goBuildCmd, os.Args[1:])
+	dp.goBuildCmd = make([]string, len(buildCmd))
+	copy(dp.goBuildCmd, buildCmd)
 	util.AssertGoBuild(dp.goBuildCmd)
 	util.Log("Go build command: %v", dp.goBuildCmd)
 }
@@ -248,6 +301,43 @@ func findMainDir(pkgs []*packages.Package) (string, error) {
 	return "", ex.Newf("cannot find main function in the source files")
 }
 
+// findTestPkgs finds all tested packages of the main module. Test binaries have
+// no main function in the source code, so we generate an otel.runtime_test.go
+// file for each of them instead. It's declared in the external test package,
+// i.e. xxx_test, to avoid conflicting with the declarations of the tested
+// package itself. It returns the paths of these files along with their package
+// names.
+func findTestPkgs(pkgs []*packages.Package) (map[string]string, error) {
+	testPkgs := make(map[string]string)
+	for _, pkg := range pkgs {
+		// Test files of dependency modules are read-only in the module cache,
+		// and we never run them anyway
+		if pkg.Module == nil || !pkg.Module.Main {
+			continue
+		}
+		for _, gofile := range pkg.GoFiles {
+			if !util.IsGoTestFile(gofile) ||
+				filepath.Base(gofile) == OtelRuntimeTestGo {
+				continue
+			}
+			file := filepath.Join(filepath.Dir(gofile), OtelRuntimeTestGo)
+			if _, exist := testPkgs[file]; exist {
+				continue
+			}
+			root, err := ast.ParseFileFast(gofile)
+			if err != nil {
+				return nil, err
+			}
+			pkgName := root.Name.Name
+			if !strings.HasSuffix(pkgName, "_test") {
+				pkgName += "_test"
+			}
+			testPkgs[file] = pkgName
+		}
+	}
+	return testPkgs, nil
+}
+
 func (dp *DepProcessor) initMod() (err error) {
 	// Find compiling module and package information from the build command
 	pkgs, err := findModule(dp.goBuildCmd)
@@ -268,6 +358,11 @@ func (dp *DepProcessor) initMod() (err error) {
 			util.Assert(pkg.Module.GoMod != "", "pkg.Module.GoMod is empty")
 			dp.moduleName = pkg.Module.Path
 			dp.modulePath = pkg.Module.GoMod
+			if dp.isGoTest() {
+				// Test binaries are linked with otel.runtime_test.go files
+				// instead, see findTestPkgs
+				continue
+			}
 			dir, err := findMainDir(pkgs)
 			if err != nil {
 				return err
@@ -316,7 +411,15 @@ func (dp *DepProcessor) initMod() (err error) {
 	if dp.moduleName == "" || dp.modulePath == "" {
 		return ex.Newf("cannot find compiled module")
 	}
-	if dp.otelRuntimeGo == "" {
+	if dp.isGoTest() {
+		dp.otelRuntimeTests, err = findTestPkgs(pkgs)
+		if err != nil {
+			return err
+		}
+		if len(dp.otelRuntimeTests) == 0 {
+			util.Log("No test files in the main module")
+		}
+	} else if dp.otelRuntimeGo == "" {
 		return ex.Newf("cannot place otel_importer.go file")
 	}
 
@@ -382,8 +485,8 @@ func (dp *DepProcessor) initSignalHandler() {
 	}()
 }
 
-func (dp *DepProcessor) init() error {
-	dp.initCmd()
+func (dp *DepProcessor) init(buildCmd []string) error {
+	dp.initCmd(buildCmd)
 	err := dp.initMod()
 	if err != nil {
 		return err
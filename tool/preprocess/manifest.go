@@ -0,0 +1,269 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preprocess
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/config"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+)
+
+// -----------------------------------------------------------------------------
+// Instrumentation Manifest
+//
+// Once a binary is shipped, there is no way to tell whether or how it was
+// instrumented. The manifest of the applied rules, along with the tool version
+// and the build configuration, is therefore embedded into the binary as a string
+// variable of the generated otel.runtime.go file. The manifest is prefixed with
+// a magic marker, so that it can be read back from the data of the binary by
+// the inspect command without running it, even if the symbol table is stripped.
+
+const (
+	manifestMagic = "\x00otel.manifest\x00"
+	inspectUsage  = `Usage: otel inspect [-json] <binary>
+Print the instrumentation manifest embedded in the binary built by otel.
+
+	-json    print the raw manifest in JSON format
+`
+)
+
+type manifestRule struct {
+	Type   string `json:"Type"`
+	Target string `json:"Target"`
+	Hook   string `json:"Hook,omitempty"`
+	// Version range of the rule
+	Version string `json:"Version,omitempty"`
+	// Import path of the instrumented package, a rule of the import path
+	// pattern is recorded once for each matched package
+	Package string `json:"Package,omitempty"`
+	// Version of the module that contains the target package
+	TargetVersion string `json:"TargetVersion,omitempty"`
+	// Path of the rule module
+	Path string `json:"Path,omitempty"`
+}
+
+type manifest struct {
+	ToolVersion string              `json:"ToolVersion"`
+	BuildConfig *config.BuildConfig `json:"BuildConfig"`
+	Rules       []*manifestRule     `json:"Rules"`
+}
+
+func newManifest(bundles []*rules.InstRuleSet) *manifest {
+	return &manifest{
+		ToolVersion: config.ToolVersion,
+		BuildConfig: config.GetConf(),
+		Rules:       collectManifestRules(bundles),
+	}
+}
+
+// collectManifestRules collects the applied rules of all bundles
+func collectManifestRules(bundles []*rules.InstRuleSet) []*manifestRule {
+	applied := make([]*manifestRule, 0)
+	seen := make(map[string]bool)
+	addRule := func(rule rules.InstRule, bundle *rules.InstRuleSet) {
+		// A rule may be matched in multiple files of the same package, but
+		// also in multiple packages or module versions
+		key := rule.String() + "\x00" + bundle.ImportPath + "\x00" + bundle.Version
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		typ, target, hook := describeRule(rule)
+		applied = append(applied, &manifestRule{
+			Type:          typ,
+			Target:        target,
+			Hook:          hook,
+			Version:       describeVersion(rule),
+			Package:       bundle.ImportPath,
+			TargetVersion: bundle.Version,
+			Path:          rule.GetPath(),
+		})
+	}
+	for _, bundle := range bundles {
+		for _, rule := range bundleRules(bundle) {
+			addRule(rule, bundle)
+		}
+	}
+	// Bundles and rules are kept in maps, sort them to make sure the generated
+	// file is deterministic, otherwise the main package is never cached
+	sort.Slice(applied, func(i, j int) bool {
+		if applied[i].Target != applied[j].Target {
+			return applied[i].Target < applied[j].Target
+		}
+		if applied[i].Package != applied[j].Package {
+			return applied[i].Package < applied[j].Package
+		}
+		if applied[i].TargetVersion != applied[j].TargetVersion {
+			return applied[i].TargetVersion < applied[j].TargetVersion
+		}
+		if applied[i].Type != applied[j].Type {
+			return applied[i].Type < applied[j].Type
+		}
+		return applied[i].Hook < applied[j].Hook
+	})
+	return applied
+}
+
+// bundleRules returns all rules of the bundle
+func bundleRules(bundle *rules.InstRuleSet) []rules.InstRule {
+	all := make([]rules.InstRule, 0)
+	for _, rule := range bundle.FileRules {
+		all = append(all, rule)
+	}
+	for _, rs := range bundle.FuncRules {
+		for _, rule := range rs {
+			all = append(all, rule)
+		}
+	}
+	for _, rs := range bundle.StructRules {
+		for _, rule := range rs {
+			all = append(all, rule)
+		}
+	}
+	for _, rs := range bundle.RawRules {
+		for _, rule := range rs {
+			all = append(all, rule)
+		}
+	}
+	for _, rs := range bundle.CallRules {
+		for _, rule := range rs {
+			all = append(all, rule)
+		}
+	}
+	return all
+}
+
+// genManifest generates the declaration of the manifest variable, which is kept
+// alive by an init function, otherwise the linker would discard it as nobody
+// refers to it
+func genManifest(bundles []*rules.InstRuleSet) (string, error) {
+	bs, err := json.Marshal(newManifest(bundles))
+	if err != nil {
+		return "", ex.Wrap(err)
+	}
+	content := fmt.Sprintf("var _otel_manifest = %q\n", manifestMagic+string(bs))
+	content += "func init() { _otel_runtime.KeepAlive(_otel_manifest) }\n"
+	return content, nil
+}
+
+// readManifest finds the manifest from the allocated sections of the ELF
+// binary, the magic marker may appear more than once, e.g. the otel tool itself
+// contains it, only the one followed by a valid manifest counts
+func readManifest(binary string) (string, *manifest, error) {
+	f, err := elf.Open(binary)
+	if err != nil {
+		return "", nil, ex.Wrapf(err, "failed to open ELF binary %s", binary)
+	}
+	defer func() { _ = f.Close() }()
+	for _, section := range f.Sections {
+		if section.Type == elf.SHT_NOBITS ||
+			section.Flags&elf.SHF_ALLOC == 0 {
+			continue
+		}
+		data, err := section.Data()
+		if err != nil {
+			return "", nil, ex.Wrapf(err, "failed to read section %s",
+				section.Name)
+		}
+		for {
+			idx := bytes.Index(data, []byte(manifestMagic))
+			if idx < 0 {
+				break
+			}
+			data = data[idx+len(manifestMagic):]
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			raw := json.RawMessage{}
+			if decoder.Decode(&raw) != nil {
+				continue
+			}
+			m := &manifest{}
+			if json.Unmarshal(raw, m) != nil || m.ToolVersion == "" {
+				continue
+			}
+			return string(raw), m, nil
+		}
+	}
+	return "", nil, ex.Newf("no instrumentation manifest found in %s, "+
+		"it's not built by otel", binary)
+}
+
+func printManifest(m *manifest) error {
+	fmt.Printf("Tool version: %s\n", m.ToolVersion)
+	fmt.Printf("Build config:\n")
+	if m.BuildConfig != nil {
+		v := reflect.ValueOf(*m.BuildConfig)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).IsZero() {
+				continue
+			}
+			fmt.Printf("  %s: %v\n", v.Type().Field(i).Name, v.Field(i))
+		}
+	}
+	fmt.Printf("Rules:\n")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  TYPE\tTARGET\tPACKAGE\tTARGET VERSION\tVERSION\tHOOK")
+	for _, rule := range m.Rules {
+		pkg, targetVersion := rule.Package, rule.TargetVersion
+		if pkg == "" {
+			pkg = "-"
+		}
+		if targetVersion == "" {
+			targetVersion = "-"
+		}
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n", rule.Type,
+			rule.Target, pkg, targetVersion, rule.Version, rule.Hook)
+	}
+	err := w.Flush()
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	fmt.Printf("%d rules applied\n", len(m.Rules))
+	return nil
+}
+
+func Inspect(args []string) error {
+	asJson := false
+	if len(args) > 0 && args[0] == "-json" {
+		asJson = true
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		fmt.Print(inspectUsage)
+		return ex.Newf("expect exactly one binary")
+	}
+	raw, m, err := readManifest(args[0])
+	if err != nil {
+		return err
+	}
+	if asJson {
+		out := bytes.Buffer{}
+		err = json.Indent(&out, []byte(raw), "", "  ")
+		if err != nil {
+			return ex.Wrap(err)
+		}
+		fmt.Println(out.String())
+		return nil
+	}
+	return printManifest(m)
+}
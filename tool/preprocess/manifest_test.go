@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preprocess
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+)
+
+func TestCollectManifestRules(t *testing.T) {
+	rule := &rules.InstFuncRule{
+		InstBaseRule: rules.InstBaseRule{ImportPath: "example.com/foo/..."},
+		Function:     "Do",
+		OnEnter:      "doOnEnter",
+	}
+	newBundle := func(importPath, version string, files ...string) *rules.InstRuleSet {
+		bundle := rules.NewInstRuleSet(importPath)
+		bundle.SetVersion(version)
+		for _, file := range files {
+			bundle.AddFuncRule(file, rule)
+		}
+		return bundle
+	}
+	applied := collectManifestRules([]*rules.InstRuleSet{
+		// Matched in multiple files of the same package
+		newBundle("example.com/foo/a", "v1.0.0", "a1.go", "a2.go"),
+		// Matched in another package of the same module
+		newBundle("example.com/foo/b", "v1.0.0", "b.go"),
+		// Matched in another module version, e.g. a major version suffix
+		newBundle("example.com/foo/v2", "v2.1.0", "v2.go"),
+	})
+	got := make([][2]string, 0, len(applied))
+	for _, r := range applied {
+		got = append(got, [2]string{r.Package, r.TargetVersion})
+	}
+	want := [][2]string{
+		{"example.com/foo/a", "v1.0.0"},
+		{"example.com/foo/b", "v1.0.0"},
+		{"example.com/foo/v2", "v2.1.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectManifestRules() = %v, want %v", got, want)
+	}
+}
@@ -16,10 +16,12 @@ package preprocess
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/alibaba/loongsuite-go-agent/tool/ast"
@@ -36,17 +38,40 @@ import (
 
 type ruleMatcher struct {
 	availableRules map[string][]rules.InstRule
-	moduleVersions []*vendorModule // vendor used only
-	projectDeps    map[string]bool // actual dependencies from dry run commands
+	patternRules   []rules.InstRule // matched by import path patterns
+	moduleVersions []*vendorModule  // vendor used only
+	projectDeps    map[string]bool  // actual dependencies from dry run commands
+	mainModule     string           // module path of the main module
+	report         *matchReport     // explain used only
+}
+
+// isPatternRule checks if the rule designates target packages by import path
+// pattern rather than a single import path
+func isPatternRule(rule rules.InstRule) bool {
+	switch rl := rule.(type) {
+	case *rules.InstCallRule:
+		return true
+	case *rules.InstFuncRule:
+		return rules.IsImportPattern(rl.ImportPath)
+	}
+	return false
 }
 
 func newRuleMatcher(compileCmds []string) *ruleMatcher {
 	availableRules := make(map[string][]rules.InstRule)
+	patternRules := make([]rules.InstRule, 0)
 	for _, rule := range findAvailableRules() {
+		// Rules that designate target packages by import path pattern can not
+		// be indexed by import path
+		if isPatternRule(rule) {
+			patternRules = append(patternRules, rule)
+			continue
+		}
 		availableRules[rule.GetImportPath()] = append(availableRules[rule.GetImportPath()], rule)
 	}
 	if config.GetConf().Verbose {
 		util.Log("Available rules: %v", availableRules)
+		util.Log("Available pattern rules: %v", patternRules)
 	}
 
 	// Populated projectDeps from compileCmds
@@ -54,6 +79,7 @@ func newRuleMatcher(compileCmds []string) *ruleMatcher {
 
 	return &ruleMatcher{
 		availableRules: availableRules,
+		patternRules:   patternRules,
 		projectDeps:    projectDeps,
 	}
 }
@@ -86,6 +112,19 @@ func loadRuleFile(path string) ([]rules.InstRule, error) {
 }
 
 func loadRuleRaw(content string) ([]rules.InstRule, error) {
+	return decodeRules(content, false)
+}
+
+// decodeRules decodes rules from the JSON content, unknown fields of rules are
+// rejected in strict mode, which is useful to catch typos in rule files
+func decodeRules(content string, strict bool) ([]rules.InstRule, error) {
+	unmarshal := func(raw json.RawMessage, v interface{}) error {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		if strict {
+			decoder.DisallowUnknownFields()
+		}
+		return decoder.Decode(v)
+	}
 	var rawMessages []json.RawMessage
 	if err := json.Unmarshal([]byte(content), &rawMessages); err != nil {
 		return nil, ex.Wrap(err)
@@ -101,22 +140,43 @@ func loadRuleRaw(content string) ([]rules.InstRule, error) {
 		var rule rules.InstRule
 		if _, ok := obj["Function"]; ok {
 			var funcRule rules.InstFuncRule
-			if err := json.Unmarshal(raw, &funcRule); err != nil {
+			if err := unmarshal(raw, &funcRule); err != nil {
 				return nil, ex.Wrap(err)
 			}
+			if err := checkFuncRule(&funcRule); err != nil {
+				return nil, ex.Wrapf(err, "bad func rule: %s", string(raw))
+			}
 			rule = &funcRule
 		} else if _, ok := obj["StructType"]; ok {
 			var structRule rules.InstStructRule
-			if err := json.Unmarshal(raw, &structRule); err != nil {
+			if err := unmarshal(raw, &structRule); err != nil {
 				return nil, ex.Wrap(err)
 			}
 			rule = &structRule
 		} else if _, ok := obj["FileName"]; ok {
 			var fileRule rules.InstFileRule
-			if err := json.Unmarshal(raw, &fileRule); err != nil {
+			if err := unmarshal(raw, &fileRule); err != nil {
 				return nil, ex.Wrap(err)
 			}
 			rule = &fileRule
+		} else if _, ok := obj["CalleeFunction"]; ok {
+			var callRule rules.InstCallRule
+			if err := unmarshal(raw, &callRule); err != nil {
+				return nil, ex.Wrap(err)
+			}
+			if err := checkCallRule(&callRule); err != nil {
+				return nil, ex.Wrapf(err, "bad call rule: %s", string(raw))
+			}
+			rule = &callRule
+		} else if _, ok := obj["func"]; ok {
+			var rawRule rules.InstRawRule
+			if err := unmarshal(raw, &rawRule); err != nil {
+				return nil, ex.Wrap(err)
+			}
+			if rawRule.Raw == "" && rawRule.RawExit == "" {
+				return nil, ex.Newf("raw rule has no raw code: %s", string(raw))
+			}
+			rule = &rawRule
 		} else {
 			return nil, ex.Newf("unknown rule type: %s", string(raw))
 		}
@@ -126,17 +186,84 @@ func loadRuleRaw(content string) ([]rules.InstRule, error) {
 	return rulesSlice, nil
 }
 
-type chunk []rules.InstRule
+// checkFuncRule checks if the function and receiver type patterns of the func
+// rule are well-formed, and normalizes its signature constraints
+func checkFuncRule(rule *rules.InstFuncRule) error {
+	switch rule.Pattern {
+	case "", rules.PatternRegex, rules.PatternGlob:
+	default:
+		return ex.Newf("unknown pattern %q", rule.Pattern)
+	}
+	if _, err := regexp.Compile(rule.FunctionRegexp()); err != nil {
+		return ex.Wrap(err)
+	}
+	if _, err := regexp.Compile(rule.ReceiverTypeRegexp()); err != nil {
+		return ex.Wrap(err)
+	}
+	for _, typs := range [][]string{rule.Params, rule.Results} {
+		for i, typ := range typs {
+			normalized, err := ast.NormalizeType(typ)
+			if err != nil {
+				return err
+			}
+			typs[i] = normalized
+		}
+	}
+	if rule.Trace {
+		// Trace rules are served by the built-in tracer hooks
+		if rule.UseRaw || rule.OnEnter != "" || rule.OnExit != "" ||
+			(rule.Path != "" && rule.Path != rules.TracerPath) {
+			return ex.Newf("trace rule must not specify hooks")
+		}
+		rule.Path = rules.TracerPath
+		rule.OnEnter, rule.OnExit = rules.TracerOnEnter, rules.TracerOnExit
+	} else if rule.TraceName != "" || len(rule.TraceParams) > 0 {
+		return ex.Newf("TraceName and TraceParams require Trace")
+	}
+	return nil
+}
 
-func loadDefaultRules() []rules.InstRule {
-	// Read all default embedded rule files
-	files, err := data.ListRuleFiles()
-	if err != nil {
-		util.Log("Failed to list default rule json files: %v", err)
-		return nil
+// checkCallRule checks if the call rule designates the callee and the hooks,
+// and if the receiver type pattern of the callee is well-formed
+func checkCallRule(rule *rules.InstCallRule) error {
+	if rule.CalleeImportPath == "" {
+		return ex.Newf("call rule has no callee")
+	}
+	if rule.Path == "" || (rule.OnEnter == "" && rule.OnExit == "") {
+		return ex.Newf("call rule has no hook")
 	}
+	if _, err := regexp.Compile(rule.CalleeReceiverTypeRegexp()); err != nil {
+		return ex.Wrap(err)
+	}
+	return nil
+}
+
+// matchSignature filters out functions whose signature mismatches the rule,
+// mismatches are reported as warnings because they usually indicate that the
+// rule is outdated for the library version
+func matchSignature(rule *rules.InstFuncRule, funcDecls []*dst.FuncDecl,
+	file string) []*dst.FuncDecl {
+	matched := make([]*dst.FuncDecl, 0, len(funcDecls))
+	for _, funcDecl := range funcDecls {
+		params, results := ast.FuncSignature(funcDecl)
+		if !rule.MatchSignature(params, results) {
+			name := funcDecl.Name.Name
+			if recv := ast.ReceiverTypeName(funcDecl); recv != "" {
+				name = "(" + recv + ")." + name
+			}
+			util.LogWarn("Signature mismatch: rule %s, file %s, func %s%s",
+				rule, file, name, ast.SignatureString(params, results))
+			continue
+		}
+		matched = append(matched, funcDecl)
+	}
+	return matched
+}
 
-	// Disable specific rules if specified
+type chunk []rules.InstRule
+
+// filterRuleFiles filters out default rule files that are disabled
+func filterRuleFiles(files []string) []string {
 	filteredFiles := make([]string, 0)
 	disable := config.GetConf().GetDisabledRules()
 	switch disable {
@@ -161,8 +288,19 @@ func loadDefaultRules() []rules.InstRule {
 			}
 		}
 	}
+	return filteredFiles
+}
+
+func loadDefaultRules() []rules.InstRule {
+	// Read all default embedded rule files
+	files, err := data.ListRuleFiles()
+	if err != nil {
+		util.Log("Failed to list default rule json files: %v", err)
+		return nil
+	}
 
 	// Load and parse each rule file concurrently
+	filteredFiles := filterRuleFiles(files)
 	ruleChunks := make([]chunk, len(filteredFiles))
 	group := &errgroup.Group{}
 	foundBase := false
@@ -317,29 +455,37 @@ func matchVersion(version string, ruleVersion string) (bool, error) {
 	return false, nil
 }
 
-// matchDependencies checks if all required dependencies are present in the project
-// Only InstFuncRule supports dependencies checking
-func (rm *ruleMatcher) matchDependencies(rule rules.InstRule) bool {
+// findMissingDependency finds the first required dependency that is not present
+// in the project, or returns empty string if all of them are present. Only
+// InstFuncRule supports dependencies checking
+func (rm *ruleMatcher) findMissingDependency(rule rules.InstRule) string {
 	funcRule, ok := rule.(*rules.InstFuncRule)
 	if !ok {
-		return true
-	}
-
-	dependencies := funcRule.Dependencies
-	if len(dependencies) == 0 {
-		return true // No dependencies required
+		return ""
 	}
 
-	for _, dep := range dependencies {
+	for _, dep := range funcRule.Dependencies {
 		if !rm.projectDeps[dep] {
 			if config.GetConf().Verbose {
 				util.Log("Dependency %s not found for rule %s", dep, rule.GetImportPath())
 			}
-			return false
+			return dep
 		}
 	}
+	return ""
+}
 
-	return true
+// matchImportPattern checks if the package is one of the packages designated
+// by the import path pattern of the rule. The main package is always compiled
+// as "main", it's deemed to be matched if the pattern covers the main module.
+func (rm *ruleMatcher) matchImportPattern(pattern, importPath string) bool {
+	if importPath == "main" && pattern != "main" {
+		if rm.mainModule == "" {
+			return false
+		}
+		importPath = rm.mainModule
+	}
+	return rules.MatchImportPath(pattern, importPath)
 }
 
 // match gives compilation arguments and finds out all interested rules
@@ -356,20 +502,44 @@ func (rm *ruleMatcher) match(cmdArgs []string) *rules.InstRuleSet {
 	// the instrumentation rule, but first we need to check if the package name
 	// are already registered, to avoid futile effort
 	copy(availables, rm.availableRules[importPath])
+	for _, rule := range rm.patternRules {
+		if !rm.matchImportPattern(rule.GetImportPath(), importPath) {
+			continue
+		}
+		// The package must call into the callee, which implies the callee is
+		// one of the dependencies
+		if rl, ok := rule.(*rules.InstCallRule); ok &&
+			!rm.projectDeps[rl.CalleeImportPath] {
+			rm.report.skip(rule, importPath, "",
+				"callee %s is not a dependency", rl.CalleeImportPath)
+			continue
+		}
+		availables = append(availables, rule)
+	}
 	if len(availables) == 0 {
 		return nil // fast fail
 	}
 	// Early filtering: filter rules based on dependencies before processing any files
 	filteredAvailables := make([]rules.InstRule, 0, len(availables))
 	for _, rule := range availables {
-		if rm.matchDependencies(rule) {
-			filteredAvailables = append(filteredAvailables, rule)
+		if dep := rm.findMissingDependency(rule); dep != "" {
+			rm.report.skip(rule, importPath, "", "dependency %s is not found", dep)
+			continue
 		}
+		filteredAvailables = append(filteredAvailables, rule)
 	}
 
 	if len(filteredAvailables) == 0 {
 		return nil // no rules match dependencies
 	}
+	// Rules that are neither matched nor skipped for a specific reason are
+	// reported as having no matching target
+	version := ""
+	defer func(candidates []rules.InstRule) {
+		for _, rule := range candidates {
+			rm.report.skip(rule, importPath, version, "no matching target found")
+		}
+	}(append([]rules.InstRule(nil), filteredAvailables...))
 
 	parsedAst := make(map[string]*dst.File)
 	bundle := rules.NewInstRuleSet(importPath)
@@ -383,6 +553,11 @@ func (rm *ruleMatcher) match(cmdArgs []string) *rules.InstRuleSet {
 		if !util.IsGoFile(candidate) {
 			continue
 		}
+		// Test files are compiled only into test variants of the package, we
+		// never instrument them
+		if util.IsGoTestFile(candidate) {
+			continue
+		}
 		file, err := filepath.Abs(candidate)
 		if err != nil {
 			util.Log("Failed to get absolute path of file %s: %v", candidate, err)
@@ -392,13 +567,14 @@ func (rm *ruleMatcher) match(cmdArgs []string) *rules.InstRuleSet {
 		// If it's a vendor build, we need to extract the version of the module
 		// from vendor/modules.txt, otherwise we find the version from source
 		// code file path
-		version := extractVersion(file)
+		version = extractVersion(file)
 		if rm.moduleVersions != nil {
 			recorded := findVendorModuleVersion(rm.moduleVersions, importPath)
 			if recorded != "" {
 				version = recorded
 			}
 		}
+		bundle.SetVersion(version)
 
 		// Fair enough, parse the file content. Since this is a heavy operation,
 		// we cache the parsed AST to avoid redundant parsing.
@@ -432,9 +608,13 @@ func (rm *ruleMatcher) match(cmdArgs []string) *rules.InstRuleSet {
 			if err != nil {
 				util.Log("Bad match: file %s, rule %s, version %s",
 					file, rule, version)
+				rm.report.skip(rule, importPath, version,
+					"version is unknown or invalid: %v", err)
 				continue
 			}
 			if !matched {
+				rm.report.skip(rule, importPath, version,
+					"version %s is not in %s", version, rule.GetVersion())
 				continue
 			}
 			// Check if the rule requires a specific Go version(range)
@@ -443,39 +623,78 @@ func (rm *ruleMatcher) match(cmdArgs []string) *rules.InstRuleSet {
 				if err != nil {
 					util.Log("Bad match: file %s, rule %s, go version %s",
 						file, rule, goVersion)
+					rm.report.skip(rule, importPath, version,
+						"go version is invalid: %v", err)
 					continue
 				}
 				if !matched {
+					rm.report.skip(rule, importPath, version,
+						"go version %s is not in %s", goVersion,
+						rule.GetGoVersion())
 					continue
 				}
 			}
 
 			// Let's match with the rule precisely
-			valid := false
+			valid, added := false, false
 			switch rl := rule.(type) {
 			case *rules.InstFuncRule:
-				funcDecls := ast.FindFuncDecl(tree, rl.Function, rl.ReceiverType)
-				if len(funcDecls) > 0 {
+				funcDecls := ast.FindFuncDecl(tree, rl.FunctionRegexp(),
+					rl.ReceiverTypeRegexp())
+				matchedDecls := matchSignature(rl, funcDecls, file)
+				if len(funcDecls) > 0 && len(matchedDecls) == 0 {
+					rm.report.skip(rule, importPath, version,
+						"signature mismatch in %s", filepath.Base(file))
+				}
+				if len(matchedDecls) > 0 {
 					bundle.AddFuncRule(file, rl)
+					added = true
 					util.Log("Match func rule %s with %v", rule, cmdArgs)
-					valid = true
+					// Pattern rule may match functions in multiple files, so
+					// it stays available for the rest files
+					valid = rl.IsExact()
 				}
 			case *rules.InstStructRule:
 				genDecl := ast.FindStructDecl(tree, rl.StructType)
 				if genDecl != nil {
 					bundle.AddStructRule(file, rl)
+					added = true
 					util.Log("Match struct rule %s with %v", rule, cmdArgs)
 					valid = true
 				}
+			case *rules.InstRawRule:
+				funcDecls := ast.FindFuncDecl(tree, regexp.QuoteMeta(rl.Func), rl.Recv)
+				if len(funcDecls) > 0 {
+					bundle.AddRawRule(file, rl)
+					added = true
+					util.Log("Match raw rule %s with %v", rule, cmdArgs)
+					valid = true
+				}
+			case *rules.InstCallRule:
+				// Methods can be called without importing the callee package,
+				// so only function calls can be filtered by imports here, the
+				// call sites are precisely located during instrumentation.
+				// Since calls may spread over multiple files, the rule stays
+				// available for the rest files
+				if rl.CalleeReceiverType != "" ||
+					ast.FindImport(tree, rl.CalleeImportPath) != nil {
+					bundle.AddCallRule(file, rl)
+					added = true
+					util.Log("Match call rule %s with %v", rule, cmdArgs)
+				}
 			case *rules.InstFileRule:
 				// File rule is always matched
 				util.Log("Match file rule %s with %v", rule, cmdArgs)
 				bundle.AddFileRule(rl)
+				added = true
 				bundle.SetPackageName(tree.Name.Name)
 				valid = true
 			default:
 				util.ShouldNotReachHereT("invalid rule type")
 			}
+			if added {
+				rm.report.match(rule, importPath, version)
+			}
 			if valid {
 				// Remove the rule from the available rules
 				filteredAvailables = append(filteredAvailables[:i], filteredAvailables[i+1:]...)
@@ -628,6 +847,12 @@ func (dp *DepProcessor) matchRules() ([]*rules.InstRuleSet, error) {
 	}
 
 	matcher := newRuleMatcher(compileCmds)
+	matcher.mainModule = dp.moduleName
+	if dp.report != nil {
+		// Only the last round of matching is reported
+		dp.report.reset(matcher)
+		matcher.report = dp.report
+	}
 
 	// If we are in vendor mode, we need to parse the vendor/modules.txt file
 	// to get the version of each module for future matching
@@ -656,5 +881,29 @@ func (dp *DepProcessor) matchRules() ([]*rules.InstRuleSet, error) {
 		}
 		cnt++
 	}
-	return bundles, nil
+	// Bundles are collected in the order of completion, sort them to make sure
+	// all generated files and go.mod edits are deterministic
+	sort.Slice(bundles, func(i, j int) bool {
+		if bundles[i].ImportPath != bundles[j].ImportPath {
+			return bundles[i].ImportPath < bundles[j].ImportPath
+		}
+		return bundles[i].String() < bundles[j].String()
+	})
+	return dedupBundles(bundles), nil
+}
+
+// dedupBundles removes bundles of the same package. When running go test, the
+// same package is compiled more than once, i.e. the regular one and the test
+// variant recompiled for the test binary, which yield the same bundle as test
+// files are never instrumented.
+func dedupBundles(bundles []*rules.InstRuleSet) []*rules.InstRuleSet {
+	deduped := make([]*rules.InstRuleSet, 0, len(bundles))
+	for _, bundle := range bundles {
+		if len(deduped) > 0 &&
+			deduped[len(deduped)-1].ImportPath == bundle.ImportPath {
+			continue
+		}
+		deduped = append(deduped, bundle)
+	}
+	return deduped
 }
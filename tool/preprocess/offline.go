@@ -0,0 +1,254 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preprocess
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/config"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// -----------------------------------------------------------------------------
+// Offline Mode
+//
+// In offline mode, we never fetch anything from the network. The pkg module and
+// all rule modules are resolved from the embedded archive, which is extracted
+// locally and referred by replace directives, while all other modules must be
+// present in the local module cache. Before syncing dependencies, we walk the
+// full module graph of the project, and report all modules that are missing
+// from the local module cache at once, rather than letting go mod tidy fail on
+// the first one. Every module in the graph needs its go.mod, while modules
+// required by the project and all locally replaced modules need their source
+// archive as well, since they provide the packages being built.
+
+// offlineEnv returns the environment variables that prevent the go command from
+// accessing the network. Checksums of modules in the local module cache were
+// already verified when they were downloaded, so the checksum database is not
+// consulted either.
+func offlineEnv() []string {
+	return []string{"GOPROXY=off", "GOSUMDB=off"}
+}
+
+// getGoEnv returns additional environment variables for all go commands issued
+// during preprocessing
+func getGoEnv() []string {
+	if config.GetConf().Offline {
+		return offlineEnv()
+	}
+	return nil
+}
+
+func findModCacheDir() (string, error) {
+	out, err := runCmdCombinedOutput("", nil, "go", "env", "GOMODCACHE")
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(out)
+	if dir == "" {
+		return "", ex.Newf("cannot find local module cache")
+	}
+	return dir, nil
+}
+
+// isModCached checks if the module version is available in the local module
+// cache. The go.mod is the minimum requirement for the module graph resolution,
+// the source archive is also required if packages of the module are built
+func isModCached(cacheDir string, mod module.Version, withZip bool) (bool, error) {
+	path, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return false, ex.Wrap(err)
+	}
+	version, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return false, ex.Wrap(err)
+	}
+	prefix := filepath.Join(cacheDir, "cache", "download", path, "@v", version)
+	if util.PathNotExists(prefix + ".mod") {
+		return false, nil
+	}
+	if withZip && util.PathNotExists(prefix+".zip") {
+		return false, nil
+	}
+	return true, nil
+}
+
+type listedModule struct {
+	Path    string
+	Version string
+	Main    bool
+	Replace *listedModule
+	Error   *struct {
+		Err string
+	}
+}
+
+// listModuleGraph lists all modules in the module graph of the project without
+// accessing the network, along with the modules that failed to be resolved.
+// Modules replaced by local directories are skipped as they are always present
+func (dp *DepProcessor) listModuleGraph() ([]module.Version,
+	map[module.Version]string, error) {
+	// Missing modules are reported individually with -e rather than failing
+	// the whole command, and -mod=mod tolerates missing go.sum entries, which
+	// are added by go mod tidy later
+	cmd := exec.Command("go", "list", "-mod=mod", "-m", "-e", "-json", "all")
+	cmd.Dir = dp.getGoModDir()
+	cmd.Env = append(os.Environ(), offlineEnv()...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil, ex.Wrapf(err, "%s", stderr.String())
+	}
+	mods := make([]module.Version, 0)
+	failed := make(map[module.Version]string)
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for {
+		m := &listedModule{}
+		err = decoder.Decode(m)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, ex.Wrap(err)
+		}
+		if m.Main {
+			continue
+		}
+		mod := module.Version{Path: m.Path, Version: m.Version}
+		if m.Replace != nil {
+			if m.Replace.Version == "" {
+				continue
+			}
+			mod = module.Version{Path: m.Replace.Path, Version: m.Replace.Version}
+		}
+		if m.Error != nil {
+			failed[mod] = m.Error.Err
+		}
+		mods = append(mods, mod)
+	}
+	return mods, failed, nil
+}
+
+// collectRequires collects all module requirements of the project, as well as
+// requirements of the locally replaced modules, i.e. the pkg module and rule
+// modules, with replacements taken into account
+func (dp *DepProcessor) collectRequires() ([]module.Version, error) {
+	gomod, err := parseGoMod(dp.getGoModPath())
+	if err != nil {
+		return nil, err
+	}
+	replaced := make(map[string]module.Version)
+	for _, r := range gomod.Replace {
+		replaced[r.Old.Path] = r.New
+	}
+	requires := make(map[module.Version]bool)
+	localDirs := make([]string, 0)
+	addRequires := func(reqs []*modfile.Require, main bool) {
+		for _, r := range reqs {
+			mod := r.Mod
+			if rep, ok := replaced[mod.Path]; ok {
+				if modfile.IsDirectoryPath(rep.Path) {
+					// Replaced by local directory, only requirements of the
+					// main module are further expanded
+					if main {
+						dir := rep.Path
+						if !filepath.IsAbs(dir) {
+							dir = filepath.Join(dp.getGoModDir(), dir)
+						}
+						localDirs = append(localDirs, dir)
+					}
+					continue
+				}
+				mod = rep
+			}
+			requires[mod] = true
+		}
+	}
+	addRequires(gomod.Require, true)
+	for _, dir := range localDirs {
+		file := filepath.Join(dir, util.GoModFile)
+		if util.PathNotExists(file) {
+			return nil, ex.Newf("replaced module %s has no go.mod", dir)
+		}
+		local, err := parseGoMod(file)
+		if err != nil {
+			return nil, err
+		}
+		addRequires(local.Require, false)
+	}
+	sorted := make([]module.Version, 0, len(requires))
+	for mod := range requires {
+		sorted = append(sorted, mod)
+	}
+	module.Sort(sorted)
+	return sorted, nil
+}
+
+// checkOfflineDeps makes sure all required modules are available locally, it
+// returns a precise list of missing modules otherwise
+func (dp *DepProcessor) checkOfflineDeps() error {
+	cacheDir, err := findModCacheDir()
+	if err != nil {
+		return err
+	}
+	graph, failed, err := dp.listModuleGraph()
+	if err != nil {
+		return err
+	}
+	requires, err := dp.collectRequires()
+	if err != nil {
+		return err
+	}
+	// Source archives are only required by modules that provide packages
+	withZip := make(map[module.Version]bool)
+	for _, mod := range graph {
+		withZip[mod] = false
+	}
+	for _, mod := range requires {
+		withZip[mod] = true
+	}
+	missing := make([]string, 0)
+	for mod, zip := range withZip {
+		cached := false
+		if _, ok := failed[mod]; !ok {
+			cached, err = isModCached(cacheDir, mod, zip)
+			if err != nil {
+				return err
+			}
+		}
+		if !cached {
+			missing = append(missing, mod.String())
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return ex.Newf("offline mode: %d module(s) missing from %s:\n\t%s",
+			len(missing), cacheDir, strings.Join(missing, "\n\t"))
+	}
+	util.Log("Offline mode: all %d required modules are available locally",
+		len(withZip))
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preprocess
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/module"
+)
+
+func TestIsModCached(t *testing.T) {
+	cacheDir := t.TempDir()
+	// Upper case letters are escaped in the module cache
+	dir := filepath.Join(cacheDir, "cache", "download", "github.com",
+		"!burnt!sushi", "toml", "@v")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"v1.4.0.mod", "v1.4.0.zip", "v1.5.0.mod"} {
+		err := os.WriteFile(filepath.Join(dir, file), []byte("module x"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name    string
+		mod     module.Version
+		withZip bool
+		want    bool
+	}{
+		{
+			name: "cached module",
+			mod:  module.Version{Path: "github.com/BurntSushi/toml", Version: "v1.4.0"},
+			want: true,
+		},
+		{
+			name:    "cached module with zip",
+			mod:     module.Version{Path: "github.com/BurntSushi/toml", Version: "v1.4.0"},
+			withZip: true,
+			want:    true,
+		},
+		{
+			name: "cached go.mod only",
+			mod:  module.Version{Path: "github.com/BurntSushi/toml", Version: "v1.5.0"},
+			want: true,
+		},
+		{
+			name:    "missing zip",
+			mod:     module.Version{Path: "github.com/BurntSushi/toml", Version: "v1.5.0"},
+			withZip: true,
+			want:    false,
+		},
+		{
+			name: "different version",
+			mod:  module.Version{Path: "github.com/BurntSushi/toml", Version: "v1.3.0"},
+			want: false,
+		},
+		{
+			name: "unknown module",
+			mod:  module.Version{Path: "golang.org/x/sys", Version: "v0.31.0"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isModCached(cacheDir, tt.mod, tt.withZip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("isModCached() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeCachedMod writes files of the module version to the module cache
+func writeCachedMod(t *testing.T, cacheDir, path, goMod string, exts ...string) {
+	dir := filepath.Join(cacheDir, "cache", "download", path, "@v")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, ext := range exts {
+		content := goMod
+		if ext == ".info" {
+			content = `{"Version":"v1.0.0"}`
+		}
+		err := os.WriteFile(filepath.Join(dir, "v1.0.0"+ext), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckOfflineDeps(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("GOMODCACHE", cacheDir)
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOWORK", "off")
+	// Required module that is fully cached, it requires a transitive module
+	// whose go.mod is missing
+	writeCachedMod(t, cacheDir, "example.com/cached",
+		"module example.com/cached\n\ngo 1.22\n\nrequire example.com/transitive v1.0.0\n",
+		".info", ".mod", ".zip")
+	// Required module without the source archive
+	writeCachedMod(t, cacheDir, "example.com/nozip",
+		"module example.com/nozip\n\ngo 1.22\n", ".info", ".mod")
+
+	projectDir := t.TempDir()
+	goMod := filepath.Join(projectDir, "go.mod")
+	err := os.WriteFile(goMod, []byte(`module example.com/project
+
+go 1.22
+
+require (
+	example.com/cached v1.0.0
+	example.com/nozip v1.0.0
+)
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dp := &DepProcessor{modulePath: goMod}
+	err = dp.checkOfflineDeps()
+	if err == nil {
+		t.Fatal("expect missing modules")
+	}
+	for _, mod := range []string{"example.com/transitive@v1.0.0",
+		"example.com/nozip@v1.0.0"} {
+		if !strings.Contains(err.Error(), mod) {
+			t.Errorf("missing module %s is not reported: %v", mod, err)
+		}
+	}
+	if strings.Contains(err.Error(), "example.com/cached@") {
+		t.Errorf("cached module is reported: %v", err)
+	}
+}
@@ -16,13 +16,16 @@ package preprocess
 
 import (
 	_ "embed"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/alibaba/loongsuite-go-agent/tool/config"
 	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/instrument"
 	"github.com/alibaba/loongsuite-go-agent/tool/rules"
 	"github.com/alibaba/loongsuite-go-agent/tool/util"
 )
@@ -36,13 +39,14 @@ import (
 // for preparing these dependencies in advance.
 
 const (
-	OtelRuntimeGo    = "otel.runtime.go"
-	OtelBackups      = "backups"
-	OtelBackupSuffix = ".bk"
-	DryRunLog        = "dry_run.log"
-	CompileRemix     = "remix"
-	VendorDir        = "vendor"
-	GoCacheDir       = "gocache"
+	OtelRuntimeGo     = "otel.runtime.go"
+	OtelRuntimeTestGo = "otel.runtime_test.go"
+	OtelBackups       = "backups"
+	OtelBackupSuffix  = ".bk"
+	DryRunLog         = "dry_run.log"
+	CompileRemix      = "remix"
+	VendorDir         = "vendor"
+	GoCacheDir        = "gocache"
 )
 
 type DepProcessor struct {
@@ -53,14 +57,22 @@ type DepProcessor struct {
 	vendorMode    bool
 	pkgModDir     string // Local module cache path of alibaba-otel pkg module
 	otelRuntimeGo string // Path to the otel.runtime.go file
+	// Paths to the otel.runtime_test.go files of tested packages, along with
+	// their package names, only used by go test
+	otelRuntimeTests map[string]string
+	// Report of the last round of rule matching, only used by rules explain
+	report *matchReport
+	// Instrumentation fingerprints of the matched rule sets
+	fingerprints *instrument.Fingerprints
 }
 
 func newDepProcessor() *DepProcessor {
 	dp := &DepProcessor{
-		backups:       map[string]string{},
-		vendorMode:    false,
-		pkgModDir:     "",
-		otelRuntimeGo: "",
+		backups:          map[string]string{},
+		vendorMode:       false,
+		pkgModDir:        "",
+		otelRuntimeGo:    "",
+		otelRuntimeTests: map[string]string{},
 	}
 	return dp
 }
@@ -74,6 +86,10 @@ func (dp *DepProcessor) getGoModDir() string {
 	return filepath.Dir(dp.getGoModPath())
 }
 
+func (dp *DepProcessor) isGoTest() bool {
+	return dp.goBuildCmd[1] == util.GoCmdTest
+}
+
 // Run runs the command and returns the combined standard output and standard
 // error. dir specifies the working directory of the command. If dir is the
 // empty string, run runs the command in the calling process's current directory.
@@ -90,6 +106,21 @@ func runCmdCombinedOutput(dir string, env []string, args ...string) (string, err
 	return string(out), nil
 }
 
+// runCmdAttached runs the command with the standard input, output and error of
+// the current process
+func runCmdAttached(env []string, args ...string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		return ex.Wrapf(err, "command %v", args)
+	}
+	return nil
+}
+
 func (dp *DepProcessor) postProcess() {
 	util.GuaranteeInPreprocess()
 
@@ -99,6 +130,9 @@ func (dp *DepProcessor) postProcess() {
 	}
 
 	_ = os.RemoveAll(dp.otelRuntimeGo)
+	for file := range dp.otelRuntimeTests {
+		_ = os.RemoveAll(file)
+	}
 	_ = os.RemoveAll(util.GetTempBuildDirWith("alibaba-pkg"))
 	_ = dp.restoreBackupFiles()
 }
@@ -155,12 +189,43 @@ func buildGoCacheEnv(value string) []string {
 	return []string{"GOCACHE=" + value}
 }
 
-func runBuildWithToolexec(goBuildCmd []string) error {
+// hasGcflags checks if -gcflags is specified by the user, either by the command
+// line or by GOFLAGS
+func hasGcflags(goBuildCmd []string) bool {
+	for _, arg := range goBuildCmd[2:] {
+		if strings.HasPrefix(arg, "-gcflags") ||
+			strings.HasPrefix(arg, "--gcflags") {
+			return true
+		}
+	}
+	return strings.Contains(os.Getenv("GOFLAGS"), "gcflags")
+}
+
+// computeFingerprints computes fingerprints of the matched rule sets and saves
+// the tool fingerprint for the tool ID query
+func (dp *DepProcessor) computeFingerprints(bundles []*rules.InstRuleSet) error {
+	fps, err := instrument.ComputeFingerprints(bundles,
+		!hasGcflags(dp.goBuildCmd))
+	if err != nil {
+		return err
+	}
+	_, err = util.WriteFile(
+		util.GetPreprocessLogPath(instrument.FingerprintFile), fps.Tool)
+	if err != nil {
+		return err
+	}
+	util.Log("Instrumentation fingerprints: %v", fps)
+	dp.fingerprints = fps
+	return nil
+}
+
+func runBuildWithToolexec(goBuildCmd []string,
+	fps *instrument.Fingerprints) error {
 	exe, err := os.Executable()
 	if err != nil {
 		return ex.Wrap(err)
 	}
-	// go build/install
+	// go build/install/test/run
 	args := []string{}
 	args = append(args, goBuildCmd[:2]...)
 	// Remix toolexec
@@ -169,12 +234,31 @@ func runBuildWithToolexec(goBuildCmd []string) error {
 	// Leave the temporary compilation directory
 	args = append(args, util.BuildWork)
 
-	// Force rebuilding
-	args = append(args, "-a")
+	// Packages are not rebuilt from scratch unless explicitly requested, the
+	// go command reuses previously instrumented objects from the isolated
+	// GOCACHE as long as their inputs, including the instrumentation fingerprint
+	// reported along with the compiler tool ID, stay unchanged
+	if config.GetConf().ForceRebuild {
+		args = append(args, "-a")
+	}
 
+	gcflags := ""
 	if config.GetConf().Debug {
 		// Disable compiler optimizations for debugging mode
-		args = append(args, "-gcflags=all=-N -l")
+		gcflags = "-N -l "
+		args = append(args, "-gcflags=all="+strings.TrimSpace(gcflags))
+	}
+
+	// Package fingerprints are passed by the per-package -gcflags, the last
+	// matching one wins so they go after the one for all packages
+	patterns := make([]string, 0, len(fps.Packages))
+	for pattern := range fps.Packages {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		args = append(args, fmt.Sprintf("-gcflags=%s=%s%s%s", pattern,
+			gcflags, instrument.FingerprintFlag, fps.Packages[pattern]))
 	}
 
 	// Append additional build arguments provided by the user
@@ -193,7 +277,13 @@ func runBuildWithToolexec(goBuildCmd []string) error {
 	// @@ Note that we should not set the working directory here, as the build
 	// with toolexec should be run in the same directory as the original build
 	// command
-	out, err := runCmdCombinedOutput("", buildGoCacheEnv(goCachePath), args...)
+	env := append(buildGoCacheEnv(goCachePath), getGoEnv()...)
+	if args[1] == util.GoCmdTest || args[1] == util.GoCmdRun {
+		// Users expect to see the test results or the program output, so
+		// the standard streams are attached to the current process
+		return runCmdAttached(env, args...)
+	}
+	out, err := runCmdCombinedOutput("", env, args...)
 	util.Log("Output from toolexec build: %v", out)
 	if err != nil {
 		return err
@@ -217,7 +307,7 @@ func precheck() error {
 		config.PrintVersion()
 		os.Exit(0)
 	}
-	if os.Args[2] != "build" && os.Args[2] != "install" {
+	if !util.IsGoBuildSubCmd(os.Args[2]) {
 		// exec original go command
 		err := util.RunCmd(os.Args[1:]...)
 		if err != nil {
@@ -237,6 +327,55 @@ func (dp *DepProcessor) keepForDebugs() {
 		}
 	}
 	_ = util.CopyFile(dp.otelRuntimeGo, filepath.Join(dir, OtelRuntimeGo))
+	for file := range dp.otelRuntimeTests {
+		// Test files of different packages share the same name, prefix them
+		// with the package directory name to tell them apart
+		name := filepath.Base(filepath.Dir(file)) + "_" + OtelRuntimeTestGo
+		_ = util.CopyFile(file, filepath.Join(dir, name))
+	}
+}
+
+// matchAllRules runs rounds of rule matching and dependency refreshing, and
+// returns the final set of matched rules
+func (dp *DepProcessor) matchAllRules() ([]*rules.InstRuleSet, error) {
+	// Two round of rule matching
+	//    {prepare->refresh}
+	//        1st match
+	//    {prepare->refresh}
+	//        2nd match
+	//    {prepare->refresh}
+	// Let's break it down a little bit. We first prepare the rule import,
+	// which is used to import foundational dependencies (e.g., otel, as we
+	// will instrument the otel SDK itself). Then, we perform a refresh to
+	// ensure dependencies are ready and proceed to the 1st match. During
+	// this phase, some rules matching specific criteria are identified. We
+	// then update the rule import again to include these newly matched rules.
+	// Since these rules may (and likely will) break the original dependency
+	// graph, a 2nd match is required to resolve the final set of rules.
+	// These final rules are used to perform a final update of the rule import.
+	// At this point, all preparations are complete, and the process can
+	// advance to the second stage: instrumentation.
+	bundles := make([]*rules.InstRuleSet, 0)
+	for i := 0; i < 3; i++ {
+		util.Log("Round %d of rule matching", i+1)
+		err := dp.newDeps(bundles)
+		if err != nil {
+			return nil, err
+		}
+
+		err = dp.syncDeps()
+		if err != nil {
+			return nil, err
+		}
+		if i == 2 {
+			continue
+		}
+		bundles, err = dp.matchRules()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bundles, nil
 }
 
 func Preprocess() error {
@@ -248,10 +387,15 @@ func Preprocess() error {
 
 	dp := newDepProcessor()
 
-	err = dp.init()
+	err = dp.init(os.Args[1:])
 	if err != nil {
 		return err
 	}
+	// Nothing to instrument if no package has test files, just let go test
+	// report that as usual
+	if dp.isGoTest() && len(dp.otelRuntimeTests) == 0 {
+		return runCmdAttached(nil, dp.goBuildCmd...)
+	}
 	defer func() { dp.postProcess() }()
 	{
 		defer util.PhaseTimer("Preprocess")()
@@ -263,42 +407,10 @@ func Preprocess() error {
 			return err
 		}
 
-		// Two round of rule matching
-		//    {prepare->refresh}
-		//        1st match
-		//    {prepare->refresh}
-		//        2nd match
-		//    {prepare->refresh}
-		// Let's break it down a little bit. We first prepare the rule import,
-		// which is used to import foundational dependencies (e.g., otel, as we
-		// will instrument the otel SDK itself). Then, we perform a refresh to
-		// ensure dependencies are ready and proceed to the 1st match. During
-		// this phase, some rules matching specific criteria are identified. We
-		// then update the rule import again to include these newly matched rules.
-		// Since these rules may (and likely will) break the original dependency
-		// graph, a 2nd match is required to resolve the final set of rules.
-		// These final rules are used to perform a final update of the rule import.
-		// At this point, all preparations are complete, and the process can
-		// advance to the second stage: instrumentation.
-		bundles := make([]*rules.InstRuleSet, 0)
-		for i := 0; i < 3; i++ {
-			util.Log("Round %d of rule matching", i+1)
-			err = dp.newDeps(bundles)
-			if err != nil {
-				return err
-			}
-
-			err = dp.syncDeps()
-			if err != nil {
-				return err
-			}
-			if i == 2 {
-				continue
-			}
-			bundles, err = dp.matchRules()
-			if err != nil {
-				return err
-			}
+		var bundles []*rules.InstRuleSet
+		bundles, err = dp.matchAllRules()
+		if err != nil {
+			return err
 		}
 
 		// Rectify file rules to make sure we can find them locally
@@ -312,16 +424,28 @@ func Preprocess() error {
 		if err != nil {
 			return err
 		}
+
+		err = dp.computeFingerprints(bundles)
+		if err != nil {
+			return err
+		}
 	}
 
 	{
 		defer util.PhaseTimer("Instrument")()
 
 		// Run go build with toolexec to start instrumentation
-		err = runBuildWithToolexec(dp.goBuildCmd)
+		err = runBuildWithToolexec(dp.goBuildCmd, dp.fingerprints)
 		if err != nil {
 			return err
 		}
+
+		// The build is done anyway, failing to write the report only deserves
+		// a warning
+		err = dp.writeReport()
+		if err != nil {
+			util.LogWarn("Failed to write instrumentation report: %v", err)
+		}
 	}
 	util.Log("Build completed successfully")
 	return nil
@@ -14,7 +14,15 @@
 
 package preprocess
 
-import "testing"
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/ast"
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+)
 
 func TestMatchVersion(t *testing.T) {
 	type args struct {
@@ -167,3 +175,209 @@ func TestMatchVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchSignature(t *testing.T) {
+	source := `package foo
+func (c *Client) Do(ctx context.Context, req *Request, opts ...Option) (*Response, error) {
+	return nil, nil
+}`
+	tests := []struct {
+		name string
+		rule string
+		want bool
+	}{
+		{
+			name: "no constraint",
+			rule: `[{"ImportPath":"foo","Function":"Do","ReceiverType":"\\*Client"}]`,
+			want: true,
+		},
+		{
+			name: "params and results match",
+			rule: `[{"ImportPath":"foo","Function":"Do","ReceiverType":"\\*Client",
+				"Params":["context.Context","* Request","...Option"],
+				"Results":["*Response","error"]}]`,
+			want: true,
+		},
+		{
+			name: "params mismatch",
+			rule: `[{"ImportPath":"foo","Function":"Do","ReceiverType":"\\*Client",
+				"Params":["context.Context","*Request"]}]`,
+			want: false,
+		},
+		{
+			name: "results mismatch",
+			rule: `[{"ImportPath":"foo","Function":"Do","ReceiverType":"\\*Client",
+				"Results":["error"]}]`,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := loadRuleRaw(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rule := loaded[0].(*rules.InstFuncRule)
+			root, err := ast.NewAstParser().ParseSource(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			funcDecls := ast.FindFuncDecl(root, rule.FunctionRegexp(),
+				rule.ReceiverTypeRegexp())
+			got := len(matchSignature(rule, funcDecls, "foo.go")) > 0
+			if got != tt.want {
+				t.Errorf("matchSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindFuncDeclPattern(t *testing.T) {
+	source := `package foo
+func (h *Handler) Do() {}
+func (h *HandlerFoo) Do() {}
+func (s *Service) Do() {}
+func (s *XService) Do() {}
+func Do() {}`
+	tests := []struct {
+		name string
+		rule string
+		want []string
+	}{
+		{
+			name: "receiver alternation",
+			rule: `[{"ImportPath":"foo","Function":"Do","ReceiverType":"\\*Handler|\\*Service"}]`,
+			want: []string{"*Handler", "*Service"},
+		},
+		{
+			name: "receiver glob",
+			rule: `[{"ImportPath":"foo","Function":"Do","ReceiverType":"*Handler*","Pattern":"glob"}]`,
+			want: []string{"*Handler", "*HandlerFoo"},
+		},
+		{
+			name: "no receiver",
+			rule: `[{"ImportPath":"foo","Function":"D.|Do"}]`,
+			want: []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := loadRuleRaw(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rule := loaded[0].(*rules.InstFuncRule)
+			root, err := ast.NewAstParser().ParseSource(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0)
+			for _, funcDecl := range ast.FindFuncDecl(root, rule.FunctionRegexp(),
+				rule.ReceiverTypeRegexp()) {
+				got = append(got, ast.ReceiverTypeName(funcDecl))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindFuncDecl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBadFuncRule(t *testing.T) {
+	for _, rule := range []rules.InstFuncRule{
+		{Function: "Do", Pattern: "wildcard"},
+		{Function: "Do("},
+		{Function: "Do", Params: []string{"map[string"}},
+	} {
+		bs, err := json.Marshal([]rules.InstFuncRule{rule})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = loadRuleRaw(string(bs)); err == nil {
+			t.Errorf("loadRuleRaw(%s) should fail", bs)
+		}
+	}
+}
+
+func TestLoadBadCallRule(t *testing.T) {
+	good := rules.InstCallRule{
+		CalleeImportPath:   "net/http",
+		CalleeFunction:     "Do",
+		CalleeReceiverType: "\\*Client|\\*Transport",
+		OnEnter:            "doOnEnter",
+	}
+	good.Path = "hook"
+	bs, err := json.Marshal([]rules.InstCallRule{good})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loadRuleRaw(string(bs)); err != nil {
+		t.Fatalf("loadRuleRaw(%s) failed: %v", bs, err)
+	}
+	noCallee, noHook, badRecv := good, good, good
+	noCallee.CalleeImportPath = ""
+	noHook.OnEnter = ""
+	badRecv.CalleeReceiverType = "\\*Client("
+	for _, rule := range []rules.InstCallRule{noCallee, noHook, badRecv} {
+		bs, err := json.Marshal([]rules.InstCallRule{rule})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = loadRuleRaw(string(bs)); err == nil {
+			t.Errorf("loadRuleRaw(%s) should fail", bs)
+		}
+	}
+}
+
+func TestCheckVersionRange(t *testing.T) {
+	for vr, valid := range map[string]bool{
+		"":                true,
+		"[1.0.0,)":        true,
+		"[,2.0.0)":        true,
+		"[1.0.0, 2.0.0)":  true,
+		"1.0.0":           false,
+		"[1.0.0,2.0.0]":   false,
+		",[)":             false,
+		"[v1.0.0,v2.0.0)": false,
+		"[1.0.0,2.0.0,3)": false,
+		"[1.0.0,[2.0.0))": false,
+	} {
+		err := checkVersionRange("Version", vr)
+		if (err == nil) != valid {
+			t.Errorf("checkVersionRange(%q) = %v, want valid %v", vr, err, valid)
+		}
+	}
+}
+
+func TestDecodeRulesStrict(t *testing.T) {
+	content := `[{"ImportPath":"net/http","Function":"Do","OnEnter":"onEnter",
+		"Versoin":"[1.0.0,)"}]`
+	if _, err := decodeRules(content, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decodeRules(content, true); err == nil {
+		t.Errorf("decodeRules() should reject unknown field in strict mode")
+	}
+}
+
+func TestFindRunArgs(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want []string
+	}{
+		{"go run .", []string{"."}},
+		{"go run . -port 8080 arg", []string{"."}},
+		{"go run -tags foo ./cmd/app arg", []string{"./cmd/app"}},
+		{"go run -tags=foo -race main.go util.go arg.go", []string{"main.go",
+			"util.go", "arg.go"}},
+		{"go run main.go util.go -v x.go", []string{"main.go", "util.go"}},
+		{"go run -exec wrapper cmd/app", []string{"cmd/app"}},
+		{"go run", nil},
+	}
+	for _, tt := range tests {
+		got := findRunArgs(strings.Fields(tt.cmd))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("findRunArgs(%q) = %v, want %v", tt.cmd, got, tt.want)
+		}
+	}
+}
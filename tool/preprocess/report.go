@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preprocess
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/config"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/instrument"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+)
+
+// -----------------------------------------------------------------------------
+// Instrumentation Report
+//
+// Once the build is done, reports of all instrumented packages are merged into
+// a single machine-readable report, which lists every instrumented package, file
+// and function, along with the applied rules, their hooks and versions. It can
+// be used to audit the injected code, or to diff the instrumentation between
+// different builds.
+
+const InstReportFile = "instrumentation.json"
+
+type instReport struct {
+	ToolVersion string                      `json:"ToolVersion"`
+	GoVersion   string                      `json:"GoVersion"`
+	BuildCmd    []string                    `json:"BuildCmd"`
+	Packages    []*instrument.PackageReport `json:"Packages"`
+}
+
+// findRuleModuleVersion finds the version of the rule module, rule modules
+// extracted from the embedded pkg module share the version of the tool
+func (dp *DepProcessor) findRuleModuleVersion(module string) (string, error) {
+	if module == pkgPrefix || strings.HasPrefix(module, pkgPrefix+"/") {
+		return config.ToolVersion, nil
+	}
+	gomod, err := parseGoMod(dp.getGoModPath())
+	if err != nil {
+		return "", err
+	}
+	for _, r := range gomod.Require {
+		if r.Mod.Path == module {
+			return r.Mod.Version, nil
+		}
+	}
+	return "", nil
+}
+
+// currentFingerprints returns fingerprints of the packages that are
+// instrumented by the current build
+func (dp *DepProcessor) currentFingerprints() map[string]bool {
+	current := map[string]bool{dp.fingerprints.Tool: true}
+	for _, fingerprint := range dp.fingerprints.Packages {
+		current[fingerprint] = true
+	}
+	return current
+}
+
+func (dp *DepProcessor) loadPackageReports(dir string) (
+	[]*instrument.PackageReport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, ex.Wrap(err)
+	}
+	current := dp.currentFingerprints()
+	moduleVersions := make(map[string]string)
+	reports := make([]*instrument.PackageReport, 0, len(files))
+	for _, file := range files {
+		// Reports left by other builds, e.g. the package is no longer a
+		// dependency or the build target is different
+		if !current[instrument.GetReportFingerprint(file)] {
+			continue
+		}
+		content, err := util.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		report := &instrument.PackageReport{}
+		err = json.Unmarshal([]byte(content), report)
+		if err != nil {
+			return nil, ex.Wrapf(err, "bad report %s", file)
+		}
+		sort.Slice(report.Files, func(i, j int) bool {
+			return report.Files[i].File < report.Files[j].File
+		})
+		for _, fr := range report.Files {
+			for _, applied := range fr.Rules {
+				if applied.Module == "" {
+					continue
+				}
+				version, ok := moduleVersions[applied.Module]
+				if !ok {
+					version, err = dp.findRuleModuleVersion(applied.Module)
+					if err != nil {
+						return nil, err
+					}
+					moduleVersions[applied.Module] = version
+				}
+				applied.ModuleVersion = version
+			}
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].ImportPath != reports[j].ImportPath {
+			return reports[i].ImportPath < reports[j].ImportPath
+		}
+		return reports[i].Files[0].File < reports[j].Files[0].File
+	})
+	return reports, nil
+}
+
+// writeReport merges reports of all instrumented packages into a single report
+func (dp *DepProcessor) writeReport() error {
+	packages, err := dp.loadPackageReports(instrument.GetReportDir())
+	if err != nil {
+		return err
+	}
+	goVersion, err := runCmdCombinedOutput("", nil, "go", "env", "GOVERSION")
+	if err != nil {
+		return err
+	}
+	report := &instReport{
+		ToolVersion: config.ToolVersion,
+		GoVersion:   strings.TrimSpace(goVersion),
+		BuildCmd:    dp.goBuildCmd,
+		Packages:    packages,
+	}
+	bs, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	file := util.GetTempBuildDirWith(InstReportFile)
+	_, err = util.WriteFile(file, string(bs))
+	if err != nil {
+		return err
+	}
+	util.Log("Instrumentation report: %s (%d packages)", file, len(packages))
+	return nil
+}
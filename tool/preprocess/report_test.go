@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preprocess
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/instrument"
+)
+
+func TestLoadPackageReports(t *testing.T) {
+	dir := t.TempDir()
+	reports := map[string]string{
+		// Package folded into the tool fingerprint
+		"toolfp_main_1.json": `{"ImportPath":"main","Files":[{"File":"main.go"}]}`,
+		"pkgfp_a_b_2.json":   `{"ImportPath":"a/b","Files":[{"File":"b.go"}]}`,
+		// Package of a different build target or no longer a dependency
+		"stalefp_a_c_3.json": `{"ImportPath":"a/c","Files":[{"File":"c.go"}]}`,
+	}
+	for name, content := range reports {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	dp := &DepProcessor{
+		fingerprints: &instrument.Fingerprints{
+			Tool:     "toolfp",
+			Packages: map[string]string{"a/b": "pkgfp"},
+		},
+	}
+	packages, err := dp.loadPackageReports(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(packages))
+	for _, p := range packages {
+		got = append(got, p.ImportPath)
+	}
+	want := []string{"a/b", "main"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadPackageReports() = %v, want %v", got, want)
+	}
+}
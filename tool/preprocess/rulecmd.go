@@ -0,0 +1,505 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preprocess
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/alibaba/loongsuite-go-agent/tool/config"
+	"github.com/alibaba/loongsuite-go-agent/tool/data"
+	"github.com/alibaba/loongsuite-go-agent/tool/ex"
+	"github.com/alibaba/loongsuite-go-agent/tool/instrument"
+	"github.com/alibaba/loongsuite-go-agent/tool/rules"
+	"github.com/alibaba/loongsuite-go-agent/tool/util"
+)
+
+// -----------------------------------------------------------------------------
+// Rules Command
+//
+// The rules command helps to find out what happens to the rules without really
+// building the project. It lists the embedded rules and custom rules specified
+// by -rule, validates the rule files in the same way as they are used during
+// preprocessing and instrumentation, and explains which rules would be matched
+// against the packages of a project, and why the others are skipped.
+
+const (
+	RulesList     = "list"
+	RulesValidate = "validate"
+	RulesExplain  = "explain"
+)
+
+var rulesUsage = `Usage: rules <list|validate|explain> [args]
+	list                     list all available rules
+	validate                 validate all available rules
+	explain [go build args]  explain rule matching of the project, the build
+	                         command defaults to "go build"
+`
+
+// ruleSource is a rule file where the rules come from, it's either an embedded
+// rule file or a custom rule file specified by -rule
+type ruleSource struct {
+	name  string
+	rules []rules.InstRule
+	err   error
+}
+
+func loadRuleSources(strict bool) ([]*ruleSource, error) {
+	files, err := data.ListRuleFiles()
+	if err != nil {
+		return nil, ex.Wrap(err)
+	}
+	sources := make([]*ruleSource, 0)
+	for _, name := range filterRuleFiles(files) {
+		source := &ruleSource{name: name}
+		content, err := data.ReadRuleFile(name)
+		if err != nil {
+			source.err = ex.Wrap(err)
+		} else {
+			source.rules, source.err = decodeRules(string(content), strict)
+		}
+		sources = append(sources, source)
+	}
+	if config.GetConf().RuleJsonFiles == "" {
+		return sources, nil
+	}
+	for _, name := range strings.Split(config.GetConf().RuleJsonFiles, ",") {
+		source := &ruleSource{name: name}
+		content, err := util.ReadFile(name)
+		if err != nil {
+			source.err = err
+		} else {
+			source.rules, source.err = decodeRules(content, strict)
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+func describeFunc(importPath, recv, name string) string {
+	if recv != "" {
+		return fmt.Sprintf("%s.(%s).%s", importPath, recv, name)
+	}
+	return importPath + "." + name
+}
+
+func describeHooks(onEnter, onExit string) string {
+	hooks := make([]string, 0, 2)
+	for _, hook := range []string{onEnter, onExit} {
+		if hook != "" {
+			hooks = append(hooks, hook)
+		}
+	}
+	return strings.Join(hooks, ",")
+}
+
+// describeRule returns the type, the target and the effect of the rule in a
+// human readable form
+func describeRule(rule rules.InstRule) (string, string, string) {
+	switch rl := rule.(type) {
+	case *rules.InstFuncRule:
+		target := describeFunc(rl.ImportPath, rl.ReceiverType, rl.Function)
+		if rl.UseRaw {
+			return "func", target, "raw code"
+		}
+		return "func", target, describeHooks(rl.OnEnter, rl.OnExit)
+	case *rules.InstStructRule:
+		return "struct", rl.ImportPath + "." + rl.StructType,
+			"field " + rl.FieldName + " " + rl.FieldType
+	case *rules.InstFileRule:
+		if rl.Replace {
+			return "file", rl.ImportPath, "replace " + rl.FileName
+		}
+		return "file", rl.ImportPath, "add " + rl.FileName
+	case *rules.InstRawRule:
+		return "raw", describeFunc(rl.ImportPath, rl.Recv, rl.Func), "raw code"
+	case *rules.InstCallRule:
+		callee := describeFunc(rl.CalleeImportPath, rl.CalleeReceiverType,
+			rl.CalleeFunction)
+		return "call", rl.ImportPath + " -> " + callee,
+			describeHooks(rl.OnEnter, rl.OnExit)
+	}
+	util.ShouldNotReachHereT("invalid rule type")
+	return "", "", ""
+}
+
+func describeVersion(rule rules.InstRule) string {
+	version := rule.GetVersion()
+	if version == "" {
+		version = "*"
+	}
+	if rule.GetGoVersion() != "" {
+		version += " go" + rule.GetGoVersion()
+	}
+	return version
+}
+
+func listRules() error {
+	sources, err := loadRuleSources(false)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SOURCE\tTYPE\tTARGET\tVERSION\tHOOK")
+	for _, source := range sources {
+		if source.err != nil {
+			_, _ = fmt.Fprintf(w, "%s\t-\tfailed to load: %s\t\t\n",
+				source.name, errorLine(source.err))
+			continue
+		}
+		for _, rule := range source.rules {
+			typ, target, hook := describeRule(rule)
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", source.name, typ,
+				target, describeVersion(rule), hook)
+		}
+	}
+	err = w.Flush()
+	if err != nil {
+		return ex.Wrap(err)
+	}
+	return nil
+}
+
+// versionRangeRegexp matches the version range in format [start,end), where
+// both start and end are optional
+var versionRangeRegexp = regexp.MustCompile(`^\[[^\[\],)]*,[^\[\],)]*\)$`)
+
+func checkVersionRange(name, vr string) error {
+	if vr == "" {
+		return nil
+	}
+	if !versionRangeRegexp.MatchString(strings.ReplaceAll(vr, " ", "")) {
+		return ex.Newf("bad %s %q, expect [start,end)", name, vr)
+	}
+	_, err := matchVersion("v0.0.0", vr)
+	if err != nil {
+		return ex.Wrapf(err, "bad %s %q", name, vr)
+	}
+	return nil
+}
+
+// resolveRuleDir finds the local directory of the rule path, the pkg module is
+// extracted on demand for embedded rules
+func (dp *DepProcessor) resolveRuleDir(path string) (string, error) {
+	if !util.PathExists(path) && dp.pkgModDir == "" {
+		pkgModDir, err := findPkgModDir()
+		if err != nil {
+			return "", err
+		}
+		dp.pkgModDir = pkgModDir
+	}
+	_, dir, err := dp.findRuleDir(path)
+	if err != nil {
+		return "", err
+	}
+	if util.PathNotExists(dir) {
+		return "", ex.Newf("rule path %s not found", path)
+	}
+	return dir, nil
+}
+
+// checkRule checks if the rule is well-formed and everything it refers to is
+// available, all problems are returned rather than the first one
+func (dp *DepProcessor) checkRule(rule rules.InstRule) []error {
+	problems := make([]error, 0)
+	if rule.GetImportPath() == "" {
+		problems = append(problems, ex.Newf("no ImportPath"))
+	}
+	if err := checkVersionRange("Version", rule.GetVersion()); err != nil {
+		problems = append(problems, err)
+	}
+	if err := checkVersionRange("GoVersion", rule.GetGoVersion()); err != nil {
+		problems = append(problems, err)
+	}
+	needHooks := false
+	switch rl := rule.(type) {
+	case *rules.InstFuncRule:
+		if rl.Function == "" {
+			problems = append(problems, ex.Newf("no Function"))
+		}
+		if rl.OnEnter == "" && rl.OnExit == "" {
+			problems = append(problems, ex.Newf("no OnEnter or OnExit"))
+		}
+		needHooks = !rl.UseRaw
+	case *rules.InstCallRule:
+		needHooks = true
+	case *rules.InstStructRule:
+		if rl.FieldName == "" || rl.FieldType == "" {
+			problems = append(problems, ex.Newf("no FieldName or FieldType"))
+		}
+	case *rules.InstFileRule:
+		if rule.GetPath() == "" {
+			problems = append(problems, ex.Newf("no Path"))
+			break
+		}
+		dir, err := dp.resolveRuleDir(rule.GetPath())
+		if err != nil {
+			problems = append(problems, err)
+			break
+		}
+		if util.PathNotExists(filepath.Join(dir, rl.FileName)) {
+			problems = append(problems, ex.Newf("file %s not found in %s",
+				rl.FileName, dir))
+		}
+	}
+	if !needHooks {
+		return problems
+	}
+	if rule.GetPath() == "" {
+		return append(problems, ex.Newf("no Path"))
+	}
+	dir, err := dp.resolveRuleDir(rule.GetPath())
+	if err != nil {
+		return append(problems, err)
+	}
+	rule.SetPath(dir)
+	if err = instrument.CheckHooks(rule); err != nil {
+		problems = append(problems, err)
+	}
+	return problems
+}
+
+// errorLine formats the error in a single line, messages attached by wrapping are
+// separated by semicolons
+func errorLine(err error) string {
+	msg := strings.TrimPrefix(err.Error(), ": ")
+	return strings.ReplaceAll(msg, "\n", "; ")
+}
+
+func validateRules() error {
+	sources, err := loadRuleSources(true)
+	if err != nil {
+		return err
+	}
+	dp := newDepProcessor()
+	defer func() { _ = os.RemoveAll(util.GetTempBuildDirWith("alibaba-pkg")) }()
+
+	total, failed := 0, 0
+	for _, source := range sources {
+		if source.err != nil {
+			fmt.Printf("%s: %s\n", source.name, errorLine(source.err))
+			failed++
+			continue
+		}
+		for i, rule := range source.rules {
+			total++
+			problems := dp.checkRule(rule)
+			if len(problems) == 0 {
+				continue
+			}
+			failed++
+			typ, target, _ := describeRule(rule)
+			for _, problem := range problems {
+				fmt.Printf("%s: rule #%d (%s %s): %s\n", source.name, i+1,
+					typ, target, errorLine(problem))
+			}
+		}
+	}
+	fmt.Printf("%d rules in %d files validated, %d failed\n",
+		total, len(sources), failed)
+	if failed > 0 {
+		return ex.Newf("%d rule(s) or rule file(s) are invalid", failed)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Rule Matching Report
+//
+// The report records whether each rule is matched against each compiled package
+// during the last round of rule matching, along with the reason if it's skipped.
+
+type matchRecord struct {
+	rule    rules.InstRule
+	pkg     string
+	version string
+	matched bool
+	reason  string
+}
+
+type matchKey struct {
+	rule rules.InstRule
+	pkg  string
+}
+
+type matchReport struct {
+	lock    sync.Mutex
+	rules   []rules.InstRule // all available rules
+	records map[matchKey]*matchRecord
+}
+
+func (r *matchReport) reset(matcher *ruleMatcher) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rules = make([]rules.InstRule, 0)
+	for _, rs := range matcher.availableRules {
+		r.rules = append(r.rules, rs...)
+	}
+	r.rules = append(r.rules, matcher.patternRules...)
+	r.records = make(map[matchKey]*matchRecord)
+}
+
+// match records that the rule is matched against the package, it overrides any
+// skip reason recorded before, e.g. a func rule may be matched in another file
+func (r *matchReport) match(rule rules.InstRule, pkg, version string) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.records[matchKey{rule, pkg}] = &matchRecord{
+		rule:    rule,
+		pkg:     pkg,
+		version: version,
+		matched: true,
+	}
+}
+
+// skip records the reason why the rule is not matched against the package, only
+// the first reason is kept
+func (r *matchReport) skip(rule rules.InstRule, pkg, version, format string,
+	args ...interface{}) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	key := matchKey{rule, pkg}
+	if _, exist := r.records[key]; exist {
+		return
+	}
+	r.records[key] = &matchRecord{
+		rule:    rule,
+		pkg:     pkg,
+		version: version,
+		reason:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (r *matchReport) print() error {
+	records := make([]*matchRecord, 0, len(r.records))
+	touched := make(map[rules.InstRule]bool)
+	for _, record := range r.records {
+		records = append(records, record)
+		touched[record.rule] = true
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].pkg != records[j].pkg {
+			return records[i].pkg < records[j].pkg
+		}
+		return records[i].rule.String() < records[j].rule.String()
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, matched := range []bool{true, false} {
+		if matched {
+			_, _ = fmt.Fprintln(w, "MATCHED\tPACKAGE\tVERSION\tTYPE\tTARGET\tHOOK")
+		} else {
+			_, _ = fmt.Fprintln(w, "\nSKIPPED\tPACKAGE\tVERSION\tTYPE\tTARGET\tREASON")
+		}
+		for _, record := range records {
+			if record.matched != matched {
+				continue
+			}
+			version := record.version
+			if version == "" {
+				version = "-"
+			}
+			typ, target, hook := describeRule(record.rule)
+			if !matched {
+				hook = record.reason
+			}
+			_, _ = fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\t%s\n", record.pkg,
+				version, typ, target, hook)
+		}
+	}
+	err := w.Flush()
+	if err != nil {
+		return ex.Wrap(err)
+	}
+
+	// Rules whose target packages are never compiled are the majority, they
+	// are summarized unless verbose output is requested
+	untouched := 0
+	for _, rule := range r.rules {
+		if touched[rule] {
+			continue
+		}
+		untouched++
+		if config.GetConf().Verbose {
+			typ, target, _ := describeRule(rule)
+			fmt.Printf("NOT COMPILED %s %s\n", typ, target)
+		}
+	}
+	fmt.Printf("\n%d of %d rules are not applicable as their target packages "+
+		"are not compiled\n", untouched, len(r.rules))
+	return nil
+}
+
+func explainRules(buildCmd []string) error {
+	if len(buildCmd) == 0 {
+		buildCmd = []string{"go", util.GoCmdBuild}
+	}
+	if len(buildCmd) < 2 || !strings.Contains(buildCmd[0], "go") ||
+		!util.IsGoBuildSubCmd(buildCmd[1]) {
+		return ex.Newf("bad build command %v", buildCmd)
+	}
+	if os.Getenv("GO111MODULE") == "off" {
+		return ex.Newf("GO111MODULE is off")
+	}
+
+	dp := newDepProcessor()
+	err := dp.init(buildCmd)
+	if err != nil {
+		return err
+	}
+	defer func() { dp.postProcess() }()
+
+	err = dp.updateGoMod()
+	if err != nil {
+		return err
+	}
+	dp.report = &matchReport{}
+	_, err = dp.matchAllRules()
+	if err != nil {
+		return err
+	}
+	return dp.report.print()
+}
+
+// Rules lists, validates or explains the rules according to the arguments
+func Rules(args []string) error {
+	if len(args) == 0 {
+		fmt.Print(rulesUsage)
+		return nil
+	}
+	switch args[0] {
+	case RulesList:
+		return listRules()
+	case RulesValidate:
+		return validateRules()
+	case RulesExplain:
+		return explainRules(args[1:])
+	default:
+		fmt.Print(rulesUsage)
+		return ex.Newf("unknown rules command %s", args[0])
+	}
+}
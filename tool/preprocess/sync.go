@@ -15,12 +15,13 @@
 package preprocess
 
 import (
+	"github.com/alibaba/loongsuite-go-agent/tool/config"
 	"github.com/alibaba/loongsuite-go-agent/tool/util"
 )
 
 func (dp *DepProcessor) runModTidy() error {
 	out, err := runCmdCombinedOutput(dp.getGoModDir(),
-		nil, "go", "mod", "tidy")
+		getGoEnv(), "go", "mod", "tidy")
 	util.Log("Run go mod tidy: %v", out)
 	if err != nil {
 		return err
@@ -30,7 +31,7 @@ func (dp *DepProcessor) runModTidy() error {
 
 func (dp *DepProcessor) runModVendor() error {
 	out, err := runCmdCombinedOutput(dp.getGoModDir(),
-		nil, "go", "mod", "vendor")
+		getGoEnv(), "go", "mod", "vendor")
 	util.Log("Run go mod vendor: %v", out)
 	if err != nil {
 		return err
@@ -39,6 +40,15 @@ func (dp *DepProcessor) runModVendor() error {
 }
 
 func (dp *DepProcessor) syncDeps() error {
+	// Make sure all dependencies are available locally in offline mode, so
+	// that we can report all missing modules at once
+	if config.GetConf().Offline {
+		err := dp.checkOfflineDeps()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Run go mod tidy to remove unused dependencies
 	err := dp.runModTidy()
 	if err != nil {
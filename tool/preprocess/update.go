@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/alibaba/loongsuite-go-agent/tool/config"
@@ -51,6 +52,11 @@ var otelDeps = map[string]string{
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric":            "v1.35.0",
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace":             "v1.35.0",
 	"go.opentelemetry.io/otel/exporters/zipkin":                         "v1.35.0",
+	"go.opentelemetry.io/otel/log":                                      "v0.11.0",
+	"go.opentelemetry.io/otel/sdk/log":                                  "v0.11.0",
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc":       "v0.11.0",
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp":       "v0.11.0",
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog":               "v0.11.0",
 }
 
 func parseGoMod(gomod string) (*modfile.File, error) {
@@ -214,7 +220,7 @@ func findPkgModDir() (string, error) {
 	return filepath.Join(tempPkg, "pkg"), nil
 }
 
-// updateRule rectifies the file rules path to the local module cache path.
+// updateRule rectifies the rules path to the local module cache path.
 func (dp *DepProcessor) updateRule(bundles []*rules.InstRuleSet) error {
 	util.GuaranteeInPreprocess()
 	defer util.PhaseTimer("Fetch")()
@@ -246,6 +252,19 @@ func (dp *DepProcessor) updateRule(bundles []*rules.InstRuleSet) error {
 				rectified[path] = true
 			}
 		}
+		for _, callRules := range bundle.CallRules {
+			for _, rule := range callRules {
+				if rectified[rule.GetPath()] {
+					continue
+				}
+				_, path, err := dp.findRuleDir(rule.GetPath())
+				if err != nil {
+					return err
+				}
+				rule.SetPath(path)
+				rectified[path] = true
+			}
+		}
 		for _, fileRule := range bundle.FileRules {
 			if rectified[fileRule.GetPath()] {
 				continue
@@ -291,7 +310,14 @@ func (dp *DepProcessor) updateGoMod() error {
 	// with the otel tool. In such cases, we need to add a replace directive
 	// to use certain versions of the OTel dependencies for given otel tool,
 	// otherwise, the otel tool may fail to run.
-	for path, version := range otelDeps {
+	// Iterate in sorted order to make sure go.mod is edited deterministically
+	paths := make([]string, 0, len(otelDeps))
+	for path := range otelDeps {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		version := otelDeps[path]
 		addDeps = append(addDeps, Dependency{
 			ImportPath:     path,
 			Version:        version,
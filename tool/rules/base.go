@@ -14,6 +14,10 @@
 
 package rules
 
+import (
+	"strings"
+)
+
 // -----------------------------------------------------------------------------
 // Instrumentation Rule
 //
@@ -28,6 +32,7 @@ package rules
 // - InstFuncRule: Instrumentation rule for a specific function call
 // - InstStructRule: Instrumentation rule for a specific struct type
 // - InstFileRule: Instrumentation rule for a specific file
+// - InstCallRule: Instrumentation rule for calls to a specific function
 
 type InstRule interface {
 	GetVersion() string    // GetVersion returns the version of the rule
@@ -57,3 +62,18 @@ func (rule *InstBaseRule) GetGoVersion() string  { return rule.GoVersion }
 func (rule *InstBaseRule) GetImportPath() string { return rule.ImportPath }
 func (rule *InstBaseRule) GetPath() string       { return rule.Path }
 func (rule *InstBaseRule) SetPath(path string)   { rule.Path = path }
+
+// IsImportPattern checks if the import path is a pattern ending with "/...",
+// which matches all packages under the given import path prefix
+func IsImportPattern(importPath string) bool {
+	return strings.HasSuffix(importPath, "/...")
+}
+
+// MatchImportPath checks if the import path matches the pattern, which is
+// either an exact import path or an import path prefix ending with "/..."
+func MatchImportPath(pattern, importPath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return importPath == prefix || strings.HasPrefix(importPath, prefix+"/")
+	}
+	return importPath == pattern
+}
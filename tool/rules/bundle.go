@@ -29,9 +29,12 @@ const (
 type InstRuleSet struct {
 	PackageName string
 	ImportPath  string
+	Version     string `json:"Version,omitempty"`
 	FileRules   []*InstFileRule
 	FuncRules   map[string][]*InstFuncRule
 	StructRules map[string][]*InstStructRule
+	RawRules    map[string][]*InstRawRule
+	CallRules   map[string][]*InstCallRule
 }
 
 func NewInstRuleSet(importPath string) *InstRuleSet {
@@ -41,6 +44,8 @@ func NewInstRuleSet(importPath string) *InstRuleSet {
 		FileRules:   make([]*InstFileRule, 0),
 		FuncRules:   make(map[string][]*InstFuncRule),
 		StructRules: make(map[string][]*InstStructRule),
+		RawRules:    make(map[string][]*InstRawRule),
+		CallRules:   make(map[string][]*InstCallRule),
 	}
 }
 
@@ -53,7 +58,9 @@ func (rb *InstRuleSet) IsValid() bool {
 	return rb != nil &&
 		(len(rb.FileRules) > 0 ||
 			len(rb.FuncRules) > 0 ||
-			len(rb.StructRules) > 0)
+			len(rb.StructRules) > 0 ||
+			len(rb.RawRules) > 0 ||
+			len(rb.CallRules) > 0)
 }
 
 func (rb *InstRuleSet) AddFuncRule(file string, rule *InstFuncRule) {
@@ -74,10 +81,30 @@ func (rb *InstRuleSet) AddStructRule(file string, rule *InstStructRule) {
 	}
 }
 
+func (rb *InstRuleSet) AddRawRule(file string, rule *InstRawRule) {
+	if _, exist := rb.RawRules[file]; !exist {
+		rb.RawRules[file] = []*InstRawRule{rule}
+	} else {
+		rb.RawRules[file] = append(rb.RawRules[file], rule)
+	}
+}
+
+func (rb *InstRuleSet) AddCallRule(file string, rule *InstCallRule) {
+	if _, exist := rb.CallRules[file]; !exist {
+		rb.CallRules[file] = []*InstCallRule{rule}
+	} else {
+		rb.CallRules[file] = append(rb.CallRules[file], rule)
+	}
+}
+
 func (rb *InstRuleSet) SetPackageName(name string) {
 	rb.PackageName = name
 }
 
+func (rb *InstRuleSet) SetVersion(version string) {
+	rb.Version = version
+}
+
 func (rb *InstRuleSet) AddFileRule(rule *InstFileRule) {
 	rb.FileRules = append(rb.FileRules, rule)
 }
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Alibaba Group Holding Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"encoding/json"
+)
+
+// InstCallRule finds calls to the specific function and instruments them at the
+// call sites, rather than at the function definition. This is useful when the
+// callee can not be recompiled, e.g. it's implemented in assembly. Unlike other
+// rules, ImportPath of the rule designates the caller packages, it can end with
+// "/..." to match all packages under the given import path prefix, e.g.
+// "github.com/foo/bar/..."
+type InstCallRule struct {
+	InstBaseRule
+	// Import path of the callee, e.g. "database/sql"
+	CalleeImportPath string `json:"CalleeImportPath,omitempty"`
+	// Callee function name, e.g. "QueryContext"
+	CalleeFunction string `json:"CalleeFunction,omitempty"`
+	// Receiver type name of the callee, e.g. "\\*DB"
+	CalleeReceiverType string `json:"CalleeReceiverType,omitempty"`
+	// OnEnter callback, called before the call
+	OnEnter string `json:"OnEnter,omitempty"`
+	// OnExit callback, called after the call
+	OnExit string `json:"OnExit,omitempty"`
+}
+
+// String returns string representation of the rule
+func (rule *InstCallRule) String() string {
+	bs, _ := json.Marshal(rule)
+	return string(bs)
+}
+
+// CalleeReceiverTypeRegexp returns CalleeReceiverType as a regular expression,
+// it's empty if the callee is a function without receiver
+func (rule *InstCallRule) CalleeReceiverTypeRegexp() string {
+	if rule.CalleeReceiverType == "" {
+		return ""
+	}
+	return "(?:" + rule.CalleeReceiverType + ")"
+}
@@ -16,15 +16,40 @@ package rules
 
 import (
 	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const (
+	PatternRegex = "regex"
+	PatternGlob  = "glob"
+)
+
+// Built-in hooks of trace rules, they create an internal span around the
+// matched functions
+const (
+	TracerPath    = "github.com/alibaba/loongsuite-go-agent/pkg/rules/tracer"
+	TracerOnEnter = "traceOnEnter"
+	TracerOnExit  = "traceOnExit"
 )
 
 // InstFuncRule finds specific function call and instrument by adding new code
 type InstFuncRule struct {
 	InstBaseRule
-	// Function name, e.g. "New"
+	// Function name, e.g. "New", it can be a pattern to match multiple
+	// functions, e.g. "New.*"
 	Function string `json:"Function,omitempty"`
-	// Receiver type name, e.g. "*gin.Engine"
+	// Receiver type name, e.g. "*gin.Engine", it can be a pattern as well
 	ReceiverType string `json:"ReceiverType,omitempty"`
+	// Pattern is the syntax of Function and ReceiverType, it's either "regex"
+	// or "glob", default is "regex"
+	Pattern string `json:"Pattern,omitempty"`
+	// Params is the list of parameter types of the function, e.g.
+	// ["context.Context", "...string"], types are spelled as in the source
+	// file of the function. Empty list means no constraint
+	Params []string `json:"Params,omitempty"`
+	// Results is the list of result types of the function, e.g. ["error"]
+	Results []string `json:"Results,omitempty"`
 	// UseRaw indicates whether to insert raw code string
 	UseRaw bool `json:"UseRaw,omitempty"`
 	// OnEnter callback, called before original function
@@ -34,6 +59,15 @@ type InstFuncRule struct {
 	// Dependencies is a list of additional dependencies that must be present
 	// for this rule to be applied. All dependencies must exist in the project.
 	Dependencies []string `json:"Dependencies,omitempty"`
+	// Trace traces the function by the built-in tracer hooks, OnEnter, OnExit
+	// and Path are filled automatically
+	Trace bool `json:"Trace,omitempty"`
+	// TraceName is the span name template of the traced function, where
+	// "{package}", "{receiver}" and "{func}" are replaced, e.g. "{func}"
+	TraceName string `json:"TraceName,omitempty"`
+	// TraceParams maps the index of parameters, as GetParam does, to the span
+	// attribute keys, e.g. {"1": "user.id"}
+	TraceParams map[int]string `json:"TraceParams,omitempty"`
 }
 
 // String returns string representation of the rule
@@ -41,3 +75,93 @@ func (rule *InstFuncRule) String() string {
 	bs, _ := json.Marshal(rule)
 	return string(bs)
 }
+
+// globToRegexp converts the glob pattern to the equivalent regular expression,
+// "*" matches any sequence of characters, "?" matches any single character and
+// "\" escapes the following character
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// isLiteral checks if the regular expression matches exactly one string
+func isLiteral(expr string) bool {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false
+	}
+	_, complete := re.LiteralPrefix()
+	return complete
+}
+
+// FunctionRegexp returns Function as a regular expression
+func (rule *InstFuncRule) FunctionRegexp() string {
+	if rule.Pattern == PatternGlob {
+		return globToRegexp(rule.Function)
+	}
+	return "(?:" + rule.Function + ")"
+}
+
+// ReceiverTypeRegexp returns ReceiverType as a regular expression
+func (rule *InstFuncRule) ReceiverTypeRegexp() string {
+	if rule.Pattern == PatternGlob {
+		return globToRegexp(rule.ReceiverType)
+	}
+	// No receiver type means functions without receiver, keep it empty
+	if rule.ReceiverType == "" {
+		return ""
+	}
+	return "(?:" + rule.ReceiverType + ")"
+}
+
+// IsExact checks if the rule designates exactly one function in exactly one
+// package. Otherwise, the rule may match functions of different signatures,
+// and its hooks only accept the CallContext parameter. Trace rules are never
+// exact as the built-in tracer hooks are shared by all traced functions
+func (rule *InstFuncRule) IsExact() bool {
+	return !rule.Trace && !IsImportPattern(rule.ImportPath) &&
+		isLiteral(rule.FunctionRegexp())
+}
+
+// IsHookPulled checks if the hooks are pulled from the hook package by the
+// target package, rather than pushed into the target package, which is the
+// case when the hook package can not know all target packages in advance
+func (rule *InstFuncRule) IsHookPulled() bool {
+	return rule.Trace || IsImportPattern(rule.ImportPath)
+}
+
+func matchTypes(constraint, actual []string) bool {
+	if len(constraint) == 0 {
+		return true
+	}
+	if len(constraint) != len(actual) {
+		return false
+	}
+	for i := range constraint {
+		if constraint[i] != actual[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchSignature checks if the parameter and result types of the function
+// satisfy the constraints of the rule, all types should be in canonical form
+func (rule *InstFuncRule) MatchSignature(params, results []string) bool {
+	return matchTypes(rule.Params, params) && matchTypes(rule.Results, results)
+}
@@ -27,8 +27,12 @@ type InstRawRule struct {
 	Func string `json:"func,omitempty"`
 	// The name of the receiver type
 	Recv string `json:"recv,omitempty"`
-	// The raw code to be injected
+	// The raw code to be injected at the entry of the target func
 	Raw string `json:"raw,omitempty"`
+	// The raw code to be injected at the exit of the target func, it's executed
+	// within a deferred closure, so that named or auto-named return values,
+	// i.e. retVal0, retVal1, etc, can be referenced
+	RawExit string `json:"raw_exit,omitempty"`
 }
 
 func (rule *InstRawRule) String() string {
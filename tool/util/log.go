@@ -45,3 +45,8 @@ func Log(format string, args ...interface{}) {
 	}
 	logMutex.Unlock()
 }
+
+// LogWarn logs something that likely goes wrong and needs user attention
+func LogWarn(format string, args ...interface{}) {
+	Log("WARNING: "+format, args...)
+}
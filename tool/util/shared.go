@@ -41,6 +41,23 @@ const (
 	BuildWork       = "-work"
 )
 
+// Go subcommands that are instrumented, all of them build packages and accept
+// the -toolexec flag
+const (
+	GoCmdBuild   = "build"
+	GoCmdInstall = "install"
+	GoCmdTest    = "test"
+	GoCmdRun     = "run"
+)
+
+func IsGoBuildSubCmd(subcmd string) bool {
+	switch subcmd {
+	case GoCmdBuild, GoCmdInstall, GoCmdTest, GoCmdRun:
+		return true
+	}
+	return false
+}
+
 func AssertGoBuild(args []string) {
 	if len(args) < 2 {
 		Assert(false, "empty go build command")
@@ -48,7 +65,7 @@ func AssertGoBuild(args []string) {
 	if !strings.Contains(args[0], "go") {
 		Assert(false, "invalid go build command %v", args)
 	}
-	if args[1] != "build" && args[1] != "install" {
+	if !IsGoBuildSubCmd(args[1]) {
 		Assert(false, "invalid go build command %v", args)
 	}
 }